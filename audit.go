@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// AuditSink receives the audit events of access denials, e.g. to send
+// them to a SIEM. Register an implementation with SetAuditSink.
+type AuditSink interface {
+	// Audit is called for each error of Kind Permission or
+	// Unauthorized sent by HTTPError or given to ReportError.
+	Audit(ctx context.Context, event AuditEvent)
+}
+
+// Outcomes of AuditEvents.
+const (
+	OutcomeDenied          = "denied"          // The actor is not allowed to access the resource.
+	OutcomeUnauthenticated = "unauthenticated" // The actor could not be authenticated.
+)
+
+// AuditEvent describes an access denial.
+type AuditEvent struct {
+	Time time.Time
+	// Actor is the user or service which was denied, if known
+	// (see WithActor)
+	Actor string
+	// Resource is the resource it tried to access (see WithResource)
+	Resource string
+	// Op is the outermost operation of the error, if any (see Ops)
+	Op Op
+	// Outcome is OutcomeDenied for errors of Kind Permission, and
+	// OutcomeUnauthenticated for errors of Kind Unauthorized
+	Outcome string
+	Kind    Kind
+	Code    Code
+	// Status is the HTTP status code sent for the error
+	Status int
+	// RequestID is the ID of the request, if known
+	RequestID string
+}
+
+// auditSink is the AuditSink used by the package. By default, it is
+// a no-op implementation.
+var auditSink AuditSink = noopAuditSink{}
+
+// SetAuditSink sets the AuditSink the audit events are sent to. If s
+// is nil, the default no-op implementation is restored. SetAuditSink
+// should be called at program start, before any errors are sent.
+func SetAuditSink(s AuditSink) {
+	if s == nil {
+		s = noopAuditSink{}
+	}
+	auditSink = s
+}
+
+// noopAuditSink is an AuditSink which does nothing.
+type noopAuditSink struct{}
+
+func (noopAuditSink) Audit(context.Context, AuditEvent) {}
+
+// WithActor returns a copy of ctx which sets the actor of the request,
+// e.g. the ID of the authenticated user, for its audit events.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// WithResource returns a copy of ctx which sets the resource accessed
+// by the request for its audit events. RequestContext sets it to the
+// method and path of the request, e.g. "DELETE /users/42", unless one
+// was already set.
+func WithResource(ctx context.Context, resource string) context.Context {
+	return context.WithValue(ctx, resourceKey, resource)
+}
+
+// requestResource returns the resource of r for its audit events.
+func requestResource(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// audit sends the audit event of err, which is sent with the given
+// HTTP status code at the time at, to the AuditSink, if err is an
+// access denial.
+func audit(ctx context.Context, err error, status int, at time.Time) {
+	var outcome string
+	kind := KindOf(err)
+	switch kind {
+	case Permission:
+		outcome = OutcomeDenied
+	case Unauthorized:
+		outcome = OutcomeUnauthenticated
+	default:
+		return
+	}
+	event := AuditEvent{
+		Time:      at,
+		Outcome:   outcome,
+		Kind:      kind,
+		Code:      chainCode(err),
+		Status:    status,
+		RequestID: RequestIDFunc(ctx),
+	}
+	event.Actor, _ = ctx.Value(actorKey).(string)
+	event.Resource, _ = ctx.Value(resourceKey).(string)
+	if ops := Ops(err); len(ops) > 0 {
+		event.Op = ops[0]
+	}
+	auditSink.Audit(ctx, event)
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testAuditSink records the audit events.
+type testAuditSink struct {
+	events []AuditEvent
+}
+
+func (s *testAuditSink) Audit(_ context.Context, event AuditEvent) {
+	s.events = append(s.events, event)
+}
+
+func TestAuditSink(t *testing.T) {
+	defer SetAuditSink(nil)
+	defer func() { now = time.Now }()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+
+	tests := []struct {
+		name string
+		err  error
+		want []AuditEvent
+	}{
+		{"Permission", Forbidden("users.Delete", "not your user"), []AuditEvent{{
+			Time: at, Actor: "user-7", Resource: "DELETE /users/42", Op: "users.Delete",
+			Outcome: OutcomeDenied, Kind: Permission, Status: http.StatusForbidden, RequestID: "req-1",
+		}}},
+		{"Unauthorized", RE(Unauthorized, Code("token_expired"), Str("token expired")), []AuditEvent{{
+			Time: at, Actor: "user-7", Resource: "DELETE /users/42",
+			Outcome: OutcomeUnauthenticated, Kind: Unauthorized, Code: "token_expired", Status: http.StatusUnauthorized, RequestID: "req-1",
+		}}},
+		{"Not a denial", NotFound("users.Delete", "no user 42"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &testAuditSink{}
+			SetAuditSink(sink)
+			h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			})
+			r := httptest.NewRequest(http.MethodDelete, "/users/42", nil)
+			r = r.WithContext(WithActor(WithRequestID(r.Context(), "req-1"), "user-7"))
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			if !reflect.DeepEqual(sink.events, tt.want) {
+				t.Errorf("events = %+v; want %+v", sink.events, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package awserrors translates the errors of the AWS SDK for Go v2
+// into errors of the errors package, so the outages of AWS services
+// are sent as an HTTP 503 rather than as unanticipated errors:
+//
+//	errors.RegisterTranslator(awserrors.Translate)
+package awserrors
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+	"github.com/gilcrest/errors"
+)
+
+// The messages sent to the client for translated errors. The message
+// of the SDK error may name buckets, tables or ARNs, so it is only
+// logged, as the error wrapped by the translated error.
+const (
+	unavailableMsg = "Service temporarily unavailable - try again later"
+	notFoundMsg    = "Not found"
+)
+
+// notFoundCodes are the error codes of AWS services for missing
+// resources.
+var notFoundCodes = map[string]bool{
+	"NotFound":                  true,
+	"NoSuchKey":                 true,
+	"NoSuchBucket":              true,
+	"ResourceNotFoundException": true,
+	"ParameterNotFound":         true,
+	"QueueDoesNotExist":         true,
+}
+
+// throttlingCodes are the error codes of AWS services for requests
+// which were throttled.
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"TooManyRequestsException":               true,
+	"RequestLimitExceeded":                   true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"SlowDown":                               true,
+	"ProvisionedThroughputExceededException": true,
+}
+
+// FromAWSErr translates err, an error returned by a client of the AWS
+// SDK, as Translate does. Other errors are returned unchanged.
+func FromAWSErr(err error) error {
+	if te := Translate(err); te != nil {
+		return te
+	}
+	return err
+}
+
+// Translate is an errors.TranslateFunc for the errors of the AWS SDK.
+// Server faults, 5xx responses and throttling errors are translated
+// into retryable errors of Kind IO with Code Unavailable, sent as an
+// HTTP 503, and missing resources into errors of Kind NotExist with
+// Code NotFound, sent as an HTTP 404. The name of the AWS service and
+// operation, e.g. "S3.GetObject", is the Op of the error. The client
+// is sent a generic message, and the AWS error is only logged. Other
+// errors are not translated, so Translate returns nil.
+func Translate(err error) error {
+	var code string
+	var fault smithy.ErrorFault
+	var ae smithy.APIError
+	if stderrors.As(err, &ae) {
+		code, fault = ae.ErrorCode(), ae.ErrorFault()
+	}
+	status := 0
+	var re interface{ HTTPStatusCode() int }
+	if stderrors.As(err, &re) {
+		status = re.HTTPStatusCode()
+	}
+
+	var args []interface{}
+	switch {
+	case fault == smithy.FaultServer, status >= http.StatusInternalServerError, throttlingCodes[code]:
+		args = []interface{}{http.StatusServiceUnavailable, errors.IO, errors.Code("Unavailable"), errors.Retry(0), errors.UserMsg(unavailableMsg)}
+	case notFoundCodes[code], status == http.StatusNotFound:
+		args = []interface{}{http.StatusNotFound, errors.NotExist, errors.Code("NotFound"), errors.UserMsg(notFoundMsg)}
+	default:
+		return nil
+	}
+	var oe *smithy.OperationError
+	if stderrors.As(err, &oe) {
+		args = append(args, errors.Op(oe.Service()+"."+oe.Operation()))
+	}
+	return errors.RE(append(args, err)...)
+}
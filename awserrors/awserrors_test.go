@@ -0,0 +1,96 @@
+package awserrors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/gilcrest/errors"
+)
+
+// responseError returns err as returned by a client of the AWS SDK
+// for a response with the given status code.
+func responseError(status int, err error) error {
+	return &smithy.OperationError{
+		ServiceID:     "S3",
+		OperationName: "GetObject",
+		Err: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      err,
+		},
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantKind   errors.Kind
+		wantCode   errors.Code
+		wantOps    []errors.Op
+	}{
+		{
+			"Server fault",
+			responseError(http.StatusInternalServerError, &smithy.GenericAPIError{Code: "InternalError", Fault: smithy.FaultServer}),
+			http.StatusServiceUnavailable, errors.IO, "Unavailable", []errors.Op{"S3.GetObject"},
+		},
+		{
+			"Throttling",
+			responseError(http.StatusBadRequest, &smithy.GenericAPIError{Code: "ThrottlingException", Fault: smithy.FaultClient}),
+			http.StatusServiceUnavailable, errors.IO, "Unavailable", []errors.Op{"S3.GetObject"},
+		},
+		{
+			"Not found",
+			responseError(http.StatusNotFound, &smithy.GenericAPIError{Code: "NoSuchKey", Fault: smithy.FaultClient}),
+			http.StatusNotFound, errors.NotExist, "NotFound", []errors.Op{"S3.GetObject"},
+		},
+		{
+			"API error alone",
+			&smithy.GenericAPIError{Code: "ResourceNotFoundException"},
+			http.StatusNotFound, errors.NotExist, "NotFound", nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := Translate(tt.err).(*errors.HTTPErr)
+			if !ok {
+				t.Fatalf("Translate() = %v; want an *errors.HTTPErr", Translate(tt.err))
+			}
+			if e.Status() != tt.wantStatus || e.Kind != tt.wantKind || e.Code != tt.wantCode {
+				t.Errorf("Translate() = %d %v %q; want %d %v %q", e.Status(), e.Kind, e.Code, tt.wantStatus, tt.wantKind, tt.wantCode)
+			}
+			if got := errors.Ops(e); !reflect.DeepEqual(got, tt.wantOps) {
+				t.Errorf("Ops() = %v; want %v", got, tt.wantOps)
+			}
+			if e.Unwrap() != tt.err {
+				t.Errorf("Unwrap() = %v; want the AWS error", e.Unwrap())
+			}
+			rr := httptest.NewRecorder()
+			errors.HTTPError(rr, e)
+			if strings.Contains(rr.Body.String(), tt.err.Error()) {
+				t.Errorf("body = %s; want no message of the AWS error", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestFromAWSErr(t *testing.T) {
+	// Client errors other than missing resources are not translated
+	err := responseError(http.StatusBadRequest, &smithy.GenericAPIError{Code: "ValidationException", Fault: smithy.FaultClient})
+	if got := FromAWSErr(err); got != err {
+		t.Errorf("FromAWSErr() = %v; want the error unchanged", got)
+	}
+	other := fmt.Errorf("some error")
+	if got := FromAWSErr(other); got != other {
+		t.Errorf("FromAWSErr() = %v; want the error unchanged", got)
+	}
+	if got := errors.KindOf(FromAWSErr(responseError(http.StatusServiceUnavailable, nil))); got != errors.IO {
+		t.Errorf("KindOf(FromAWSErr()) = %v; want %v", got, errors.IO)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/gilcrest/errors/awserrors
+
+go 1.21
+
+require (
+	github.com/aws/smithy-go v1.20.2
+	github.com/gilcrest/errors v0.0.0
+)
+
+require github.com/rs/zerolog v1.14.0 // indirect
+
+replace github.com/gilcrest/errors => ../
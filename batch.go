@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+)
+
+// BatchError reports the items of a bulk request which failed, so a
+// bulk endpoint can report them without failing the whole batch. When
+// sent with HTTPError, it is rendered as an HTTP 207 (Multi-Status)
+// BatchResponse, which lists the index, status code and error of each
+// failed item.
+type BatchError struct {
+	// Total is the number of items of the batch
+	Total int
+	// Items are the failed items, in the order they were added
+	Items []ItemError
+}
+
+// ItemError is the error of the item of a batch at Index.
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+// NewBatchError returns a BatchError for a batch of total items.
+func NewBatchError(total int) *BatchError {
+	return &BatchError{Total: total}
+}
+
+// Add records that the item at index failed with err. A nil err is
+// ignored.
+func (be *BatchError) Add(index int, err error) {
+	if err == nil {
+		return
+	}
+	be.Items = append(be.Items, ItemError{Index: index, Err: err})
+}
+
+// Err returns the BatchError as an error, or nil if no item failed.
+func (be *BatchError) Err() error {
+	if len(be.Items) == 0 {
+		return nil
+	}
+	return be
+}
+
+func (be *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d items failed", len(be.Items), be.Total)
+}
+
+// Unwrap returns the errors of the failed items, so they can be
+// inspected with errors.Is and errors.As.
+func (be *BatchError) Unwrap() []error {
+	errs := make([]error, len(be.Items))
+	for i, item := range be.Items {
+		errs[i] = item.Err
+	}
+	return errs
+}
+
+// Kind returns the most severe Kind of the errors of the failed items
+// (see SetKindPrecedence), or Other if no item failed.
+func (be *BatchError) Kind() Kind {
+	err := mostSevere(be.Unwrap())
+	if err == nil {
+		return Other
+	}
+	k, _ := classify(err)
+	return k
+}
+
+// serviceErrors returns a ServiceError for each failed item.
+func (be *BatchError) serviceErrors() []ServiceError {
+	ses := make([]ServiceError, len(be.Items))
+	for i, item := range be.Items {
+		status, se := serviceError(item.Err)
+		if se == nil {
+			se = &ServiceError{Message: ReasonPhrase(status)}
+		}
+		ses[i] = *se
+	}
+	return ses
+}
+
+// BatchResponse is the Response Body of a BatchError.
+type BatchResponse struct {
+	Status int `json:"status" xml:"status"`
+	// Total is the number of items of the batch, and Failed the
+	// number of items which failed
+	Total  int `json:"total" xml:"total"`
+	Failed int `json:"failed" xml:"failed"`
+	// Errors lists the failed items
+	Errors []BatchItem `json:"errors" xml:"errors>error"`
+	// RequestID is the ID of the request which failed, if known
+	RequestID string `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	// ErrorID is the ID of the error response (see ErrorIDFunc)
+	ErrorID string `json:"error_id,omitempty" xml:"error_id,omitempty"`
+}
+
+// BatchItem is the error of a failed item in a BatchResponse.
+type BatchItem struct {
+	Index   int    `json:"index" xml:"index"`
+	Status  int    `json:"status" xml:"status"`
+	Kind    string `json:"kind,omitempty" xml:"kind,omitempty"`
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Param   string `json:"param,omitempty" xml:"param,omitempty"`
+	Message string `json:"message,omitempty" xml:"message,omitempty"`
+}
+
+// batchOf returns the BatchError in the chain of err, if any.
+func batchOf(err error) *BatchError {
+	var be *BatchError
+	if stderrors.As(err, &be) {
+		return be
+	}
+	return nil
+}
+
+// sendBatch sends the BatchResponse of be with the given status code.
+// The errors of its items are built from be itself, and redacted and
+// localized as the ServiceError of any other error, as the ServiceError
+// HTTPError determined for the error sent does not list them when be
+// is wrapped, e.g. by RE.
+func sendBatch(ctx context.Context, w http.ResponseWriter, status int, be *BatchError) {
+	items := &ServiceError{Errors: be.serviceErrors()}
+	Redaction.redactServiceError(items)
+	localize(ctx, items)
+	truncateServiceError(items)
+	r := BatchResponse{
+		Status:    status,
+		Total:     be.Total,
+		Failed:    len(be.Items),
+		Errors:    make([]BatchItem, len(be.Items)),
+		RequestID: RequestIDFunc(ctx),
+		ErrorID:   errorIDFromContext(ctx),
+	}
+	for i, item := range be.Items {
+		ise := items.Errors[i]
+		itemStatus, _ := serviceError(item.Err)
+		r.Errors[i] = BatchItem{
+			Index:   item.Index,
+			Status:  itemStatus,
+			Kind:    ise.Kind,
+			Code:    ise.Code,
+			Param:   ise.Param,
+			Message: ise.Message,
+		}
+	}
+
+	if enc := responseEncoder(ctx); enc != nil {
+		sendEncoded(ctx, w, enc, status, r)
+		return
+	}
+	errJSON, err := marshalResponse(r)
+	if err != nil {
+		sendMarshalFailure(w, status, err, "application/json", staticResponse)
+		return
+	}
+	sendError(ctx, w, string(errJSON), status)
+}
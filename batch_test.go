@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBatchError(t *testing.T) {
+	be := NewBatchError(4)
+	if be.Err() != nil {
+		t.Fatal("Err() of a BatchError without failed items != nil")
+	}
+	be.Add(0, nil)
+	be.Add(1, NotFound("users.Create", "no group 7"))
+	be.Add(3, TooLong("name", 64))
+	be.Add(2, E(Op("users.Create"), Database, Str("pq: connection refused")))
+
+	if got, want := be.Error(), "3 of 4 items failed"; got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+	var fe *FieldError
+	if !stderrors.As(be.Err(), &fe) || fe.Param != "name" {
+		t.Errorf("errors.As() did not find the FieldError of item 3")
+	}
+
+	rr := httptest.NewRecorder()
+	HTTPErrorCtx(WithRequestID(context.Background(), "req-1"), rr, be.Err())
+
+	if rr.Code != http.StatusMultiStatus {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusMultiStatus)
+	}
+	var got BatchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := BatchResponse{
+		Status: http.StatusMultiStatus,
+		Total:  4,
+		Failed: 3,
+		Errors: []BatchItem{
+			{Index: 1, Status: http.StatusNotFound, Kind: NotExist.String(), Message: "no group 7"},
+			{Index: 3, Status: http.StatusBadRequest, Kind: Validation.String(), Code: "TooLong", Param: "name", Message: "name must have a length of at most 64"},
+			{Index: 2, Status: http.StatusInternalServerError, Kind: Unanticipated.String(), Code: "Unanticipated", Message: "Unexpected error - contact support"},
+		},
+		RequestID: "req-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %+v; want %+v", got, want)
+	}
+}
+
+func TestBatchErrorWrapped(t *testing.T) {
+	be := NewBatchError(2)
+	be.Add(1, NotFound("users.Create", "no group 7"))
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"RE", RE(http.StatusBadRequest, Validation, be.Err()), http.StatusBadRequest},
+		{"RE status only", RE(http.StatusBadRequest, be.Err()), http.StatusBadRequest},
+		{"E", E(Op("users.Import"), be.Err()), http.StatusMultiStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+			var got BatchResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			want := []BatchItem{{Index: 1, Status: http.StatusNotFound, Kind: NotExist.String(), Message: "no group 7"}}
+			if !reflect.DeepEqual(got.Errors, want) {
+				t.Errorf("Errors = %+v; want %+v", got.Errors, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GzipMinLength is the length in bytes from which the bodies of error
+// responses, such as debug responses with the chain of the error or
+// responses listing the errors of a batch, are compressed with gzip
+// for the clients which accept it, as determined by the Accept-Encoding
+// header of the request given to RequestContext. It is 0 by default,
+// so the bodies are never compressed. Bodies are not compressed either
+// if a Content-Encoding was already set, e.g. by a middleware which
+// compresses the responses itself.
+var GzipMinLength = 0
+
+// writeBody sends body, of the given content type, with the status
+// code, setting its Content-Length. The body is compressed with gzip
+// if the request of ctx accepts it (see GzipMinLength).
+func writeBody(ctx context.Context, w http.ResponseWriter, status int, contentType string, body []byte) {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	if GzipMinLength > 0 && h.Get("Content-Encoding") == "" {
+		h.Add("Vary", "Accept-Encoding")
+		if len(body) >= GzipMinLength && acceptsGzip(ctx) {
+			if gz, ok := gzipped(body); ok {
+				h.Set("Content-Encoding", "gzip")
+				body = gz
+			}
+		}
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// gzipped returns body compressed with gzip.
+func gzipped(body []byte) ([]byte, bool) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, false
+	}
+	if err := zw.Close(); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of the request
+// of ctx, if known, accepts gzip.
+func acceptsGzip(ctx context.Context) bool {
+	r, ok := ctx.Value(requestKey).(*http.Request)
+	if !ok {
+		return false
+	}
+	accepted := false
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(v, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding != "gzip" && coding != "*" {
+				continue
+			}
+			q := 1.0
+			for _, p := range strings.Split(params, ";") {
+				k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+				if strings.EqualFold(k, "q") {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						q = f
+					}
+				}
+			}
+			if coding == "gzip" {
+				// An explicit gzip takes precedence over *
+				return q > 0
+			}
+			accepted = q > 0
+		}
+	}
+	return accepted
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.8", true},
+		{"gzip;q=0", false},
+		{"br", false},
+		{"*", true},
+		{"*;q=0.5, gzip;q=0", false},
+		{"GZIP", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept-Encoding", tt.accept)
+			}
+			if got := acceptsGzip(RequestContext(r)); got != tt.want {
+				t.Errorf("acceptsGzip(%q) = %v; want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorBody(t *testing.T) {
+	defer func(n int) { GzipMinLength = n }(GzipMinLength)
+	long := RE(http.StatusBadRequest, Validation, Str(strings.Repeat("name is required; ", 20)))
+	short := RE(http.StatusNotFound, NotExist, Str("no user"))
+	tests := []struct {
+		name     string
+		minLen   int
+		encoding string
+		err      error
+		wantGzip bool
+	}{
+		{"Disabled", 0, "gzip", long, false},
+		{"Long", 100, "gzip", long, true},
+		{"Short", 1000, "gzip", short, false},
+		{"Not accepted", 100, "", long, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			GzipMinLength = tt.minLen
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.encoding != "" {
+				r.Header.Set("Accept-Encoding", tt.encoding)
+			}
+			rr := httptest.NewRecorder()
+			HTTPErrorCtx(RequestContext(r), rr, tt.err)
+
+			if got := rr.Header().Get("Content-Length"); got != strconv.Itoa(rr.Body.Len()) {
+				t.Errorf("Content-Length = %s; want %d", got, rr.Body.Len())
+			}
+			gotGzip := rr.Header().Get("Content-Encoding") == "gzip"
+			if gotGzip != tt.wantGzip {
+				t.Fatalf("gzip = %v; want %v", gotGzip, tt.wantGzip)
+			}
+			body := rr.Body.String()
+			if gotGzip {
+				zr, err := gzip.NewReader(rr.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = string(b)
+			}
+			if want := KindOf(tt.err).String(); !strings.Contains(body, want) {
+				t.Errorf("body = %q; want it to contain %q", body, want)
+			}
+		})
+	}
+}
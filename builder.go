@@ -0,0 +1,139 @@
+package errors
+
+import "time"
+
+// HTTPBuilder builds an HTTPErr one field at a time, as an alternative
+// to RE, whose arguments are told apart by their type only. For
+// example:
+//
+//	return errors.NewHTTP().Status(http.StatusNotFound).Kind(errors.NotExist).
+//		Code("USER_NOT_FOUND").Param("id").Msg("user not found").Err()
+//
+// The fields are the ones RE accepts, with the same meaning.
+type HTTPBuilder struct {
+	status   int
+	kind     Kind
+	code     Code
+	param    Parameter
+	ops      []Op
+	retry    *Retry
+	severity Severity
+	data     TemplateData
+	userMsg  UserMsg
+	devMsg   DevMsg
+	err      error
+}
+
+// NewHTTP returns a new HTTPBuilder with no field set.
+func NewHTTP() *HTTPBuilder {
+	return &HTTPBuilder{}
+}
+
+// Status sets the HTTP status code of the error. If it is not set,
+// the status code mapped to the Kind is sent (see KindStatus).
+func (b *HTTPBuilder) Status(status int) *HTTPBuilder {
+	b.status = status
+	return b
+}
+
+// Kind sets the Kind of the error.
+func (b *HTTPBuilder) Kind(k Kind) *HTTPBuilder {
+	b.kind = k
+	return b
+}
+
+// Code sets the Code of the error.
+func (b *HTTPBuilder) Code(c Code) *HTTPBuilder {
+	b.code = c
+	return b
+}
+
+// Param sets the Parameter the error relates to.
+func (b *HTTPBuilder) Param(p Parameter) *HTTPBuilder {
+	b.param = p
+	return b
+}
+
+// Op adds op to the operations of the error (see Ops).
+func (b *HTTPBuilder) Op(op Op) *HTTPBuilder {
+	b.ops = append(b.ops, op)
+	return b
+}
+
+// Retry marks the error as retryable after the suggested delay d,
+// or with no suggestion if d is 0.
+func (b *HTTPBuilder) Retry(d time.Duration) *HTTPBuilder {
+	r := Retry(d)
+	b.retry = &r
+	return b
+}
+
+// Severity sets the Severity of the error.
+func (b *HTTPBuilder) Severity(s Severity) *HTTPBuilder {
+	b.severity = s
+	return b
+}
+
+// Data sets the data of the message template of the Code, used when
+// no message or error is set (see RegisterTemplate).
+func (b *HTTPBuilder) Data(d TemplateData) *HTTPBuilder {
+	b.data = d
+	return b
+}
+
+// Msg sets the message of the error, which is sent to the client.
+// It replaces any error given to Wrap.
+func (b *HTTPBuilder) Msg(msg string) *HTTPBuilder {
+	b.err = Str(msg)
+	return b
+}
+
+// UserMsg sets the message sent to the client instead of the message
+// of the error (see UserMsg).
+func (b *HTTPBuilder) UserMsg(msg string) *HTTPBuilder {
+	b.userMsg = UserMsg(msg)
+	return b
+}
+
+// DevMsg sets the message for developers, which is logged but never
+// sent to the client (see DevMsg).
+func (b *HTTPBuilder) DevMsg(msg string) *HTTPBuilder {
+	b.devMsg = DevMsg(msg)
+	return b
+}
+
+// Wrap sets the error wrapped by the error, whose message is sent to
+// the client. As with RE, the Kind, Code and Parameter of err are
+// inherited unless they are set. It replaces any message given to Msg.
+func (b *HTTPBuilder) Wrap(err error) *HTTPBuilder {
+	b.err = err
+	return b
+}
+
+// Err returns the *HTTPErr built from the fields set, as returned by
+// RE. Its location is the caller of Err.
+func (b *HTTPBuilder) Err() error {
+	return b.build(1)
+}
+
+// build returns the *HTTPErr built from the fields set. Its location is
+// skip frames above the caller of build.
+func (b *HTTPBuilder) build(skip int) error {
+	args := []interface{}{b.status, b.kind, b.code, b.param, b.ops, b.severity, b.userMsg, b.devMsg}
+	if b.retry != nil {
+		args = append(args, *b.retry)
+	}
+	if b.data != nil {
+		args = append(args, b.data)
+	}
+	if b.err != nil {
+		args = append(args, b.err)
+	}
+	e := RE(args...).(*HTTPErr)
+	// Record where the error was built, rather than here
+	if stackOf(b.err) == nil {
+		e.trace = captureStack(skip + 1)
+	}
+	e.pc = captureLocation(skip)
+	return e
+}
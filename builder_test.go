@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHTTPBuilder(t *testing.T) {
+	const op Op = "users.Get"
+	inner := E(op, Validation, Code("bad_id"), Parameter("id"), Str("id is not a number"))
+
+	tests := []struct {
+		name string
+		got  error
+		want error
+	}{
+		{
+			"Fields",
+			NewHTTP().Status(http.StatusNotFound).Kind(NotExist).Code("USER_NOT_FOUND").Param("id").Msg("user not found").Err(),
+			RE(http.StatusNotFound, NotExist, Code("USER_NOT_FOUND"), Parameter("id"), Str("user not found")),
+		},
+		{
+			"StatusOnly",
+			NewHTTP().Status(http.StatusNoContent).Err(),
+			RE(http.StatusNoContent),
+		},
+		{
+			"Op Retry Severity",
+			NewHTTP().Kind(IO).Op(op).Retry(time.Second).Severity(WarnSeverity).Msg("try again").Err(),
+			RE(IO, op, Retry(time.Second), WarnSeverity, Str("try again")),
+		},
+		{
+			"Wrap",
+			NewHTTP().Status(http.StatusBadRequest).Wrap(inner).Err(),
+			RE(http.StatusBadRequest, inner),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := *tt.got.(*HTTPErr), *tt.want.(*HTTPErr)
+			// Only the locations differ
+			got.pc, want.pc = 0, 0
+			got.trace, want.trace = nil, nil
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Err() = %#v; want %#v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ErrorCatalog lists the Kinds and the Codes of the errors of a
+// service, as returned by Catalog, e.g. to generate the reference
+// documentation of an API or the error schemas of its OpenAPI
+// definition. It is encoded as JSON with the tags of its fields.
+type ErrorCatalog struct {
+	Kinds []KindInfo  `json:"kinds"`
+	Codes []CodeEntry `json:"codes"`
+}
+
+// KindInfo describes a Kind in an ErrorCatalog.
+type KindInfo struct {
+	// Kind is the name of the Kind, as sent in error responses.
+	Kind string `json:"kind"`
+	// Status is the HTTP status code the Kind is mapped to (see
+	// KindStatus).
+	Status int `json:"status"`
+}
+
+// CodeEntry describes a Code in an ErrorCatalog.
+type CodeEntry struct {
+	Code Code `json:"code"`
+	// Description is the description given to RegisterCode, if any.
+	Description string `json:"description,omitempty"`
+	// Kind and Status are the defaults given to RegisterCodeDefaults,
+	// if any.
+	Kind   string `json:"kind,omitempty"`
+	Status int    `json:"status,omitempty"`
+	// DocURL is the URL given to RegisterDocURL, if any.
+	DocURL string `json:"doc_url,omitempty"`
+}
+
+// Catalog returns the Kinds of this package and those registered with
+// RegisterKind, in the order of their values, and the Codes registered
+// with RegisterCode, RegisterCodeDefaults or RegisterDocURL, sorted, as
+// an ErrorCatalog.
+func Catalog() ErrorCatalog {
+	var c ErrorCatalog
+	for k := Other; k <= Unauthorized; k++ {
+		c.Kinds = append(c.Kinds, KindInfo{Kind: k.String(), Status: KindStatus(k)})
+	}
+	kindMu.RLock()
+	custom := make([]Kind, 0, len(kindNames))
+	for k := range kindNames {
+		custom = append(custom, k)
+	}
+	kindMu.RUnlock()
+	sort.Slice(custom, func(i, j int) bool { return custom[i] < custom[j] })
+	for _, k := range custom {
+		c.Kinds = append(c.Kinds, KindInfo{Kind: k.String(), Status: KindStatus(k)})
+	}
+
+	entries := map[Code]*CodeEntry{}
+	entry := func(code Code) *CodeEntry {
+		e, ok := entries[code]
+		if !ok {
+			e = &CodeEntry{Code: code}
+			entries[code] = e
+		}
+		return e
+	}
+	codeMu.RLock()
+	for code, desc := range codes {
+		entry(code).Description = desc
+	}
+	for code, d := range codeDefaults {
+		e := entry(code)
+		if d.kind != Other {
+			e.Kind = d.kind.String()
+		}
+		e.Status = d.status
+		if e.Status == 0 {
+			e.Status = KindStatus(d.kind)
+		}
+	}
+	codeMu.RUnlock()
+	docMu.RLock()
+	for code, url := range docURLs {
+		entry(code).DocURL = url
+	}
+	docMu.RUnlock()
+	for _, e := range entries {
+		c.Codes = append(c.Codes, *e)
+	}
+	sort.Slice(c.Codes, func(i, j int) bool {
+		return c.Codes[i].Code < c.Codes[j].Code
+	})
+	return c
+}
+
+// WriteCSV writes the Codes of the catalog to w as CSV, with a header
+// row, one row per Code, in the order of their JSON fields.
+func (c ErrorCatalog) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"code", "description", "kind", "status", "doc_url"})
+	for _, e := range c.Codes {
+		var status string
+		if e.Status != 0 {
+			status = strconv.Itoa(e.Status)
+		}
+		cw.Write([]string{string(e.Code), e.Description, e.Kind, status, e.DocURL})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	defer func() {
+		codeMu.Lock()
+		codes = map[Code]string{}
+		codeDefaults = map[Code]codeDefault{}
+		codeMu.Unlock()
+		docMu.Lock()
+		delete(docURLs, "card_declined")
+		docMu.Unlock()
+		kindMu.Lock()
+		for k := range kindNames {
+			statusMu.Lock()
+			delete(kindStatus, k)
+			statusMu.Unlock()
+		}
+		kindNames = map[Kind]string{}
+		kindsByName = map[string]Kind{}
+		nextKind = firstCustomKind
+		kindMu.Unlock()
+	}()
+	RegisterKind("payment_required", http.StatusPaymentRequired)
+	RegisterCode("card_declined", "The card was declined.")
+	RegisterCodeDefaults("card_declined", Invalid, http.StatusPaymentRequired)
+	RegisterDocURL("card_declined", "https://example.com/docs/card_declined")
+	RegisterCodeDefaults("user_not_found", NotExist, 0)
+
+	c := Catalog()
+	if n := len(c.Kinds); n != int(Unauthorized)+2 {
+		t.Errorf("len(Kinds) = %d; want %d", n, int(Unauthorized)+2)
+	}
+	if got, want := c.Kinds[NotExist], (KindInfo{"item_does_not_exist", http.StatusNotFound}); got != want {
+		t.Errorf("Kinds[NotExist] = %+v; want %+v", got, want)
+	}
+	if got, want := c.Kinds[len(c.Kinds)-1], (KindInfo{"payment_required", http.StatusPaymentRequired}); got != want {
+		t.Errorf("last Kind = %+v; want %+v", got, want)
+	}
+	wantCodes := []CodeEntry{
+		{Code: "card_declined", Description: "The card was declined.", Kind: "invalid_operation", Status: http.StatusPaymentRequired, DocURL: "https://example.com/docs/card_declined"},
+		{Code: "user_not_found", Kind: "item_does_not_exist", Status: http.StatusNotFound},
+	}
+	if !reflect.DeepEqual(c.Codes, wantCodes) {
+		t.Errorf("Codes = %+v; want %+v", c.Codes, wantCodes)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded ErrorCatalog
+	if err := json.Unmarshal(b, &decoded); err != nil || !reflect.DeepEqual(decoded, c) {
+		t.Errorf("json round trip = %+v, %v; want %+v", decoded, err, c)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	const wantCSV = "code,description,kind,status,doc_url\n" +
+		"card_declined,The card was declined.,invalid_operation,402,https://example.com/docs/card_declined\n" +
+		"user_not_found,,item_does_not_exist,404,\n"
+	if buf.String() != wantCSV {
+		t.Errorf("WriteCSV() = %q; want %q", buf.String(), wantCSV)
+	}
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"context"
+)
+
+// DebugResponses determines whether HTTPError sends the full chain of
+// nested errors in the response body (see ChainLink), to speed up
+// debugging in development and staging environments. It is false by
+// default and should not be enabled in production, as the chain
+// reveals the internals of the service. Debug responses can also be
+// enabled per request with WithDebug or DebugHeader.
+var DebugResponses = false
+
+// DebugHeader is the name of a request header which enables debug
+// responses for the request when set to a non-empty value, e.g.
+// "X-Debug-Errors". The header is read by RequestContext, so it applies
+// to the requests served by Handler. When empty, the default, debug
+// responses cannot be enabled by a header.
+var DebugHeader = ""
+
+// ChainLink describes one of the nested errors in the chain of an
+// error, as sent in debug responses. The outermost error is first.
+// Elapsed and Deadline are the timing attached to the error, if any
+// (see Elapsed and Deadline).
+type ChainLink struct {
+	Op       string `json:"op,omitempty" xml:"op,omitempty"`
+	Kind     string `json:"kind,omitempty" xml:"kind,omitempty"`
+	Message  string `json:"message,omitempty" xml:"message,omitempty"`
+	Elapsed  string `json:"elapsed,omitempty" xml:"elapsed,omitempty"`
+	Deadline string `json:"deadline,omitempty" xml:"deadline,omitempty"`
+}
+
+// WithDebug returns a copy of ctx which enables debug responses for
+// the errors sent with HTTPErrorCtx.
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey, true)
+}
+
+// debugEnabled reports whether debug responses are enabled,
+// either for all requests or for the request of ctx.
+func debugEnabled(ctx context.Context) bool {
+	if DebugResponses {
+		return true
+	}
+	debug, _ := ctx.Value(debugKey).(bool)
+	return debug
+}
+
+// chain returns the links of the chain of errors wrapped by err, from
+// the outermost to the innermost. Errors of this package are described
+// by their Op and Kind, and other errors by their message. The errors
+// added by StripStack and Redact are skipped, as they only change the
+// message of the error they wrap.
+func chain(err error) []ChainLink {
+	var links []ChainLink
+	for _, err := range chainOf(err) {
+		var l ChainLink
+		switch e := err.(type) {
+		case *strippedError:
+			continue
+		case *Error:
+			l.Op = joinOps(e.opChain())
+			if e.Kind != Other {
+				l.Kind = e.Kind.String()
+			}
+			l.setTiming(e)
+		case *HTTPErr:
+			l.Op = joinOps(e.ops)
+			l.Kind = e.ErrKind()
+			l.setTiming(e)
+		case *annotation:
+			l.Message = Redaction.redact("", e.msg)
+		default:
+			l.Message = Redaction.redact("", err.Error())
+		}
+		if l != (ChainLink{}) {
+			links = append(links, l)
+		}
+	}
+	return links
+}
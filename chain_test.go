@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDebugChain(t *testing.T) {
+	newErr := func() error {
+		return E(Op("handler.Create"), E(Op("service.Insert"), E(Op("db.Exec"), Database, Str("connection reset"))))
+	}
+	want := []ChainLink{
+		{Op: "handler.Create", Kind: "database_error"},
+		{Op: "service.Insert"},
+		{Op: "db.Exec"},
+		{Message: "connection reset"},
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		problem bool
+		want    []ChainLink
+	}{
+		{"Disabled", context.Background(), false, nil},
+		{"WithDebug", WithDebug(context.Background()), false, want},
+		{"ProblemDetails", WithDebug(context.Background()), true, want},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(prev bool) {
+				ProblemDetails = prev
+			}(ProblemDetails)
+			ProblemDetails = tt.problem
+
+			rr := httptest.NewRecorder()
+			HTTPErrorCtx(tt.ctx, rr, newErr())
+
+			var got []ChainLink
+			if tt.problem {
+				var pr ProblemResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &pr); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				got = pr.Chain
+			} else {
+				var er ErrResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				got = er.Error.Chain
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chain = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugHeader(t *testing.T) {
+	defer func(prev string) {
+		DebugHeader = prev
+	}(DebugHeader)
+	DebugHeader = "X-Debug-Errors"
+
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return RE(http.StatusNotFound, E(Op("service.Get"), NotExist, "no such user"))
+	})
+	for _, debug := range []bool{false, true} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if debug {
+			req.Header.Set(DebugHeader, "1")
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		var er ErrResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if got := len(er.Error.Chain) > 0; got != debug {
+			t.Errorf("header set = %t: got chain %+v", debug, er.Error.Chain)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MaxChainDepth is the number of nested errors beyond which the chain
+// of an error is considered broken. The functions of this package stop
+// walking a chain at this depth, and HTTPError sends a chain deeper
+// than this as an internal error. It guards against wrappers which
+// never stop unwrapping, such as an error type wrapping new copies of
+// itself.
+var MaxChainDepth = 100
+
+var (
+	// ErrChainCycle is wrapped by the error CheckChain returns for a
+	// chain in which an error wraps itself, directly or not.
+	ErrChainCycle = Str("error chain has a cycle")
+	// ErrChainTooDeep is wrapped by the error CheckChain returns for a
+	// chain with more than MaxChainDepth nested errors.
+	ErrChainTooDeep = Str("error chain is too deep")
+)
+
+// chainError describes the broken chain of an error.
+type chainError struct {
+	// typ is the type of the outermost error of the chain.
+	typ string
+	// reason is ErrChainCycle or ErrChainTooDeep.
+	reason error
+}
+
+func (e *chainError) Error() string {
+	if e.reason == ErrChainCycle {
+		return fmt.Sprintf("errors: the chain of %s error has a cycle", e.typ)
+	}
+	return fmt.Sprintf("errors: the chain of %s error is deeper than %d errors", e.typ, MaxChainDepth)
+}
+
+func (e *chainError) Unwrap() error {
+	return e.reason
+}
+
+// CheckChain walks the errors wrapped by err, following both the
+// Unwrap() error and Unwrap() []error methods, and returns an error
+// wrapping ErrChainCycle if one of them wraps itself, or
+// ErrChainTooDeep if they are nested more than MaxChainDepth deep. It
+// returns nil if the chain of err is sound. Only errors of comparable
+// types can be detected in a cycle; cycles of other errors end up too
+// deep.
+func CheckChain(err error) error {
+	if err == nil {
+		return nil
+	}
+	if reason := walkChain(err, nil); reason != nil {
+		return &chainError{typ: fmt.Sprintf("%T", err), reason: reason}
+	}
+	return nil
+}
+
+// walkChain walks the chain of err, below the errors of path, and
+// returns ErrChainCycle or ErrChainTooDeep if the chain is broken.
+func walkChain(err error, path []error) error {
+	for err != nil && !nilHTTPErr(err) {
+		if len(path) >= MaxChainDepth {
+			return ErrChainTooDeep
+		}
+		if inPath(err, path) {
+			return ErrChainCycle
+		}
+		path = append(path, err)
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if reason := walkChain(e, path[:len(path):len(path)]); reason != nil {
+					return reason
+				}
+			}
+			return nil
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// inPath reports whether err is one of the errors of path. Only
+// pointers are compared: a struct type is comparable even if it has
+// interface fields, but comparing it panics if they hold values which
+// are not, such as slices. A cycle always goes through a pointer, so
+// it is still found, and a chain of values is cut by MaxChainDepth.
+func inPath(err error, path []error) bool {
+	if reflect.TypeOf(err).Kind() != reflect.Pointer {
+		return false
+	}
+	for _, e := range path {
+		if reflect.TypeOf(e) == reflect.TypeOf(err) && e == err {
+			return true
+		}
+	}
+	return false
+}
+
+// chainOf returns the chain of err as given by errors.Unwrap, from err
+// to the innermost error. It stops before an error which is already in
+// the chain, and after MaxChainDepth errors.
+func chainOf(err error) []error {
+	var chain []error
+	eachOf(err, func(err error) bool {
+		chain = append(chain, err)
+		return true
+	})
+	return chain
+}
+
+// eachOf calls f with each error of the chain of err, as returned by
+// chainOf, until f returns false. Unlike chainOf, it does not allocate
+// for the usual chains, so it is used on the path of E and RE.
+func eachOf(err error, f func(error) bool) {
+	var buf [16]error
+	path := buf[:0]
+	// A nil *HTTPErr ends the chain, as its methods would panic
+	for err != nil && !nilHTTPErr(err) && len(path) < MaxChainDepth && !inPath(err, path) {
+		if !f(err) {
+			return
+		}
+		path = append(path, err)
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return
+		}
+		err = u.Unwrap()
+	}
+}
+
+// soundChain returns err, or the error of CheckChain if the chain of
+// err is broken, so that the errors of the standard library, which
+// walk chains without limit, can be used on it. An error with a nil
+// *HTTPErr in its chain is replaced too, as walking or printing it
+// panics.
+func soundChain(err error) error {
+	if cerr := CheckChain(err); cerr != nil {
+		return cerr
+	}
+	if hasNilHTTPErr(err) {
+		return nilHTTPErrError()
+	}
+	return err
+}
+
+// hasNilHTTPErr reports whether the chain of err, which must be sound,
+// has a nil *HTTPErr.
+func hasNilHTTPErr(err error) bool {
+	for err != nil {
+		if nilHTTPErr(err) {
+			return true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if hasNilHTTPErr(e) {
+					return true
+				}
+			}
+			return false
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// loopError is a buggy wrapper which wraps the error set in next,
+// possibly itself.
+type loopError struct {
+	next error
+}
+
+func (e *loopError) Error() string { return "loop" }
+func (e *loopError) Unwrap() error { return e.next }
+
+// endlessError is a buggy wrapper which always wraps a new copy of
+// itself, so its chain never ends.
+type endlessError struct {
+	n int
+}
+
+func (e endlessError) Error() string { return "endless" }
+func (e endlessError) Unwrap() error { return endlessError{e.n + 1} }
+
+// fieldsError is a comparable type whose comparison panics, as its
+// interface field holds a slice.
+type fieldsError struct {
+	fields interface{}
+	err    error
+}
+
+func (e fieldsError) Error() string { return "fields" }
+func (e fieldsError) Unwrap() error { return e.err }
+
+// cycle returns an error whose chain has a cycle below an Error.
+func cycle() error {
+	l := &loopError{}
+	l.next = &loopError{next: l}
+	return E(Op("svc.Get"), NotExist, l)
+}
+
+func TestCheckChain(t *testing.T) {
+	self := &loopError{}
+	self.next = self
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"Sound", E(Op("svc.Get"), NotExist, Str("not found")), nil},
+		{"Joined", stderrors.Join(Str("a"), E(Op("svc.Get"), Str("b"))), nil},
+		{"Self", self, ErrChainCycle},
+		{"Cycle", cycle(), ErrChainCycle},
+		{"Joined cycle", stderrors.Join(Str("a"), self), ErrChainCycle},
+		{"Endless", endlessError{}, ErrChainTooDeep},
+		{"Not comparable", fieldsError{fields: []string{"a"}, err: fieldsError{fields: []string{"b"}, err: Str("c")}}, nil},
+		{"Not comparable cycle", fieldsError{fields: []string{"a"}, err: self}, ErrChainCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckChain(tt.err)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("CheckChain() = %v; want nil", got)
+				}
+				return
+			}
+			if !stderrors.Is(got, tt.want) {
+				t.Errorf("CheckChain() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBrokenChains(t *testing.T) {
+	for name, err := range map[string]error{"Cycle": cycle(), "Endless": endlessError{}} {
+		t.Run(name, func(t *testing.T) {
+			// The functions walking the chain must return
+			KindOf(err)
+			RootKind(err)
+			Ops(err)
+			IsRetryable(err)
+			if n := len(chainOf(err)); n > MaxChainDepth {
+				t.Errorf("len(chainOf()) = %d; want at most %d", n, MaxChainDepth)
+			}
+
+			if got := Format(err); !strings.Contains(got, "error chain") {
+				t.Errorf("Format() = %q; want the broken chain described", got)
+			}
+
+			rr := httptest.NewRecorder()
+			HTTPError(rr, err)
+			if rr.Code != http.StatusInternalServerError {
+				t.Errorf("HTTPError() status = %d; want %d", rr.Code, http.StatusInternalServerError)
+			}
+		})
+	}
+}
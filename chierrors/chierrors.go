@@ -0,0 +1,30 @@
+// Package chierrors integrates the errors package with the chi router.
+//
+// chi handlers are plain http.Handlers, so handlers which return an
+// error can be adapted with errors.Handler:
+//
+//	r := chi.NewRouter()
+//	r.Use(middleware.RequestID, chierrors.Middleware)
+//	r.Method(http.MethodGet, "/users/{id}", errors.Handler(getUser))
+package chierrors
+
+import (
+	"net/http"
+
+	"github.com/gilcrest/errors"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware adds the request ID set by chi's middleware.RequestID to
+// the context of the request, so it is included in the error responses
+// sent by errors.HTTPErrorCtx and errors.Handler. It must be used
+// after middleware.RequestID.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if id := middleware.GetReqID(ctx); id != "" && errors.RequestIDFromContext(ctx) == "" {
+			r = r.WithContext(errors.WithRequestID(ctx, id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,36 @@
+package chierrors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, Middleware)
+	r.Method(http.MethodGet, "/users/{id}", errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.RE(http.StatusNotFound, errors.NotExist, errors.Str("no such user"))
+	}))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusNotFound)
+	}
+	var er errors.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if er.Error.RequestID != "req-123" {
+		t.Errorf("RequestID = %q; want %q", er.Error.RequestID, "req-123")
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/gilcrest/errors/chierrors
+
+go 1.21
+
+require (
+	github.com/gilcrest/errors v0.0.0
+	github.com/go-chi/chi/v5 v5.0.12
+)
+
+require github.com/rs/zerolog v1.14.0 // indirect
+
+replace github.com/gilcrest/errors => ../
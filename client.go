@@ -0,0 +1,147 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// MaxErrorBodySize is the number of bytes of the body of an error
+// response read by ParseHTTPError, so a misbehaving server cannot make
+// a client buffer an arbitrarily large body. It is 1 MiB by default.
+// A body cut at this size is usually not valid JSON, so its text is
+// used as the message.
+var MaxErrorBodySize int64 = 1 << 20
+
+// ParseHTTPError decodes the error response in resp, as sent by
+// HTTPError or HTTPProblem, back into an *HTTPErr with its status code,
+// Kind, Code and Param restored, and the message as the underlying
+// error. Responses listing several validation errors are restored as
+// a ValidationErrors. If the body is not in either format, the body
+// text is used as the message. If resp has a Retry-After header, the
+// returned error is marked as retryable with its delay.
+//
+// If resp does not have an error status code (400 or above),
+// ParseHTTPError returns nil. The body of resp is read, but it is
+// not closed, and no more than MaxErrorBodySize bytes of it are read.
+// The fields of the response are named as set by Schema.
+func ParseHTTPError(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	e := &HTTPErr{HTTPStatusCode: resp.StatusCode}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		e.Retryable = true
+		e.RetryAfter = d
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodySize))
+	if err != nil {
+		e.Err = err
+		return e
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return e
+	}
+
+	var se ServiceError
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mt == "application/problem+json" {
+		var pr ProblemResponse
+		if err := json.Unmarshal(body, &pr); err != nil {
+			e.Err = Str(string(body))
+			return e
+		}
+		se = ServiceError{Kind: pr.Kind, Code: pr.Code, Param: pr.Param, Message: pr.Detail, Errors: pr.Errors}
+	} else {
+		var er ErrResponse
+		data, err := Schema.apply(body, true)
+		if err == nil {
+			err = json.Unmarshal(data, &er)
+		}
+		if err != nil {
+			e.Err = Str(string(body))
+			return e
+		}
+		se = er.Error
+	}
+
+	e.Kind = kindFromString(se.Kind)
+	e.Code = Code(se.Code)
+	e.Param = Parameter(se.Param)
+	if len(se.Errors) > 0 {
+		ve := make(ValidationErrors, len(se.Errors))
+		for i, fe := range se.Errors {
+			ve[i] = &HTTPErr{
+				HTTPStatusCode: resp.StatusCode,
+				Kind:           kindFromString(fe.Kind),
+				Code:           Code(fe.Code),
+				Param:          Parameter(fe.Param),
+				Err:            Str(fe.Message),
+			}
+		}
+		e.Err = ve
+	} else if se.Message != "" {
+		e.Err = Str(se.Message)
+	}
+	return e
+}
+
+// Transport is an http.RoundTripper which returns the error responses
+// of upstream services as errors, so they can be propagated without
+// losing their Kind, Code and Param:
+//
+//	client := &http.Client{Transport: &errors.Transport{Op: "users.Get"}}
+//
+// The responses with an error status code (400 or above) are parsed
+// with ParseHTTPError, their body closed, and the *HTTPErr returned
+// with the Op of the Transport as its innermost operation. As for any
+// error of a RoundTripper, http.Client returns it wrapped in a
+// *url.Error, which unwraps to it.
+type Transport struct {
+	// Base is the RoundTripper which sends the requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Op is the operation added to the errors, e.g. the name of the
+	// upstream service. If empty, the method and URL of the request,
+	// without its query, are used, e.g. "GET users.internal/users/42".
+	Op Op
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+	defer resp.Body.Close()
+	e := ParseHTTPError(resp).(*HTTPErr)
+	op := t.Op
+	if op == "" {
+		op = Op(req.Method + " " + req.URL.Host + req.URL.Path)
+	}
+	e.ops = append(e.ops, op)
+	return nil, e
+}
+
+// kindFromString returns the Kind whose String method returns s,
+// including the Kinds registered with RegisterKind, or Other if
+// there is none.
+func kindFromString(s string) Kind {
+	for k := Other; k <= Unauthorized; k++ {
+		if k.String() == s {
+			return k
+		}
+	}
+	if k, ok := registeredKind(s); ok {
+		return k
+	}
+	return Other
+}
@@ -0,0 +1,165 @@
+package errors
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHTTPError(t *testing.T) {
+	defer func(prev bool) {
+		ProblemDetails = prev
+	}(ProblemDetails)
+
+	tests := []struct {
+		name     string
+		problem  bool
+		err      error
+		wantCode int
+		wantKind Kind
+		wantMsg  string
+	}{
+		{"ErrResponse", false, RE(http.StatusNotFound, NotExist, Code("user_not_found"), Parameter("id"), Str("no such user")), http.StatusNotFound, NotExist, "no such user"},
+		{"Problem", true, RE(http.StatusConflict, Exist, Code("user_exists"), Parameter("id"), Str("user exists")), http.StatusConflict, Exist, "user exists"},
+		{"Status Only", false, RE(http.StatusUnauthorized), http.StatusUnauthorized, Other, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ProblemDetails = tt.problem
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			err := ParseHTTPError(rr.Result())
+			var he *HTTPErr
+			if !stderrors.As(err, &he) {
+				t.Fatalf("ParseHTTPError() = %T; want *HTTPErr", err)
+			}
+			want := tt.err.(*HTTPErr)
+			if he.HTTPStatusCode != tt.wantCode || he.Kind != tt.wantKind || he.Code != want.Code || he.Param != want.Param {
+				t.Errorf("ParseHTTPError() = %+v; want %+v", he, want)
+			}
+			if he.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q; want %q", he.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseHTTPErrorBodyLimit(t *testing.T) {
+	defer func(prev int64) { MaxErrorBodySize = prev }(MaxErrorBodySize)
+	MaxErrorBodySize = 16
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(strings.Repeat("upstream failed ", 1000))),
+	}
+	err := ParseHTTPError(resp)
+	if got := err.Error(); len(got) > 16 {
+		t.Errorf("Error() = %q; want at most %d bytes of the body", got, 16)
+	}
+}
+
+func TestParseHTTPErrorValidation(t *testing.T) {
+	ve := ValidationErrors{MissingField("name"), InputUnwanted("id")}
+	rr := httptest.NewRecorder()
+	HTTPError(rr, ve)
+
+	err := ParseHTTPError(rr.Result())
+	var got ValidationErrors
+	if !stderrors.As(err, &got) {
+		t.Fatalf("ParseHTTPError() does not wrap ValidationErrors")
+	}
+	if len(got) != 2 || paramOf(got[0]) != "name" || paramOf(got[1]) != "id" {
+		t.Errorf("ValidationErrors = %v; want params name, id", got)
+	}
+	if err.Error() != ve.Error() {
+		t.Errorf("Error() = %q; want %q", err.Error(), ve.Error())
+	}
+}
+
+func TestParseHTTPErrorSuccess(t *testing.T) {
+	rr := httptest.NewRecorder()
+	rr.WriteHeader(http.StatusOK)
+	if err := ParseHTTPError(rr.Result()); err != nil {
+		t.Errorf("ParseHTTPError() = %v; want nil", err)
+	}
+}
+
+func TestParseHTTPErrorRetryAfter(t *testing.T) {
+	rr := httptest.NewRecorder()
+	HTTPError(rr, RE(http.StatusServiceUnavailable, IO, Retry(3*time.Second), Str("try later")))
+
+	err := ParseHTTPError(rr.Result())
+	if !IsRetryable(err) {
+		t.Error("IsRetryable() = false; want true")
+	}
+	if d := RetryDelay(err); d != 3*time.Second {
+		t.Errorf("RetryDelay() = %v; want %v", d, 3*time.Second)
+	}
+
+	rr = httptest.NewRecorder()
+	rr.Header().Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	rr.WriteHeader(http.StatusTooManyRequests)
+	err = ParseHTTPError(rr.Result())
+	if d := RetryDelay(err); d <= 59*time.Minute || d > time.Hour {
+		t.Errorf("RetryDelay() = %v; want about an hour", d)
+	}
+
+	rr = httptest.NewRecorder()
+	HTTPError(rr, RE(http.StatusServiceUnavailable, Str("try later")))
+	if IsRetryable(ParseHTTPError(rr.Result())) {
+		t.Error("IsRetryable() = true; want false")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	defer SetLogger(nil)
+	SetLogger(&testLogger{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		HTTPError(w, RE(http.StatusNotFound, NotExist, Code("no_user"), Parameter("id"), Str("no user 42")))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		op     Op
+		wantOp Op
+	}{
+		{"Op", "users.Get", "users.Get"},
+		{"Default Op", "", Op("GET " + srv.Listener.Addr().String() + "/users/42")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: &Transport{Op: tt.op}}
+			_, err := client.Get(srv.URL + "/users/42?verbose=1")
+			var e *HTTPErr
+			if !stderrors.As(err, &e) {
+				t.Fatalf("Get() error = %v; want an *HTTPErr", err)
+			}
+			if e.HTTPStatusCode != http.StatusNotFound || e.Kind != NotExist || e.Code != "no_user" || e.Param != "id" || e.Error() != "no user 42" {
+				t.Errorf("Get() error = %+v; want the upstream error", e)
+			}
+			if ops := Ops(err); len(ops) != 1 || ops[0] != tt.wantOp {
+				t.Errorf("Ops() = %v; want [%s]", ops, tt.wantOp)
+			}
+		})
+	}
+
+	resp, err := (&http.Client{Transport: &Transport{}}).Get(srv.URL + "/ok")
+	if err != nil {
+		t.Fatalf("Get() error = %v; want nil", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Get() status = %d; want %d", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+// Clone returns a deep copy of err, so that the copy can be changed
+// without changing err, e.g. to adapt an error cached as a sentinel.
+// Each *Error and *HTTPErr of the chain of err is copied, down to the
+// first error of another type, which is shared by err and its copy
+// since it cannot be copied. Other errors are returned as is.
+//
+// Clone is not needed to wrap errors: E and RE never change the errors
+// they are given.
+func Clone(err error) error {
+	switch e := err.(type) {
+	case *Error:
+		if e == nil {
+			return err
+		}
+		c := copyError(e)
+		c.Err = Clone(e.Err)
+		return c
+	case *HTTPErr:
+		if e == nil {
+			return err
+		}
+		c := copyHTTPErr(e)
+		c.Err = Clone(e.Err)
+		return c
+	}
+	return err
+}
+
+// copyError returns a copy of e which shares no slice with e, but
+// wraps the same error.
+func copyError(e *Error) *Error {
+	c := *e
+	c.ops = append([]Op(nil), e.ops...)
+	c.trace = append(StackTrace(nil), e.trace...)
+	return &c
+}
+
+// copyHTTPErr returns a copy of e which shares no slice, map or
+// pointer with e, but wraps the same error.
+func copyHTTPErr(e *HTTPErr) *HTTPErr {
+	c := *e
+	c.ops = append([]Op(nil), e.ops...)
+	c.trace = append(StackTrace(nil), e.trace...)
+	if e.RateLimit != nil {
+		rl := *e.RateLimit
+		c.RateLimit = &rl
+	}
+	if e.Headers != nil {
+		c.Headers = e.Headers.Clone()
+	}
+	return &c
+}
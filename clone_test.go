@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestWrapDoesNotChangeSentinel(t *testing.T) {
+	sentinel := E(Op("store.Get"), NotExist, Code("NotFound"), UserName("joe@example.com"), Str("item not found")).(*Error)
+	want := *sentinel
+	want.ops = append([]Op(nil), sentinel.ops...)
+
+	tests := []struct {
+		name string
+		wrap func() error
+	}{
+		{"E", func() error { return E(Op("svc.Get"), UserName("joe@example.com"), sentinel) }},
+		{"E with Kind", func() error { return E(Op("svc.Get"), NotExist, sentinel) }},
+		{"RE", func() error { return RE(http.StatusNotFound, Op("svc.Get"), sentinel) }},
+		{"Wrap", func() error { return Wrap(sentinel, "svc.Get", "getting item") }},
+		{"InternalError", func() error { return InternalError("svc.Get", sentinel) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.wrap()
+			if !reflect.DeepEqual(*sentinel, want) {
+				t.Errorf("sentinel changed to %+v; want %+v", *sentinel, want)
+			}
+			if got := Root(err).Error(); got != "item not found" {
+				t.Errorf("Root() = %q; want %q", got, "item not found")
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	inner := E(Op("store.Get"), NotExist, Str("item not found"))
+	orig := RE(http.StatusNotFound, Op("svc.Get"), Header("X-Reason", "gone"), inner).(*HTTPErr)
+
+	c, ok := Clone(orig).(*HTTPErr)
+	if !ok {
+		t.Fatalf("Clone() = %T; want *HTTPErr", Clone(orig))
+	}
+	if c == orig {
+		t.Fatal("Clone() returned the same error")
+	}
+	if c.Error() != orig.Error() || KindOf(c) != KindOf(orig) || !reflect.DeepEqual(Ops(c), Ops(orig)) {
+		t.Errorf("Clone() = %v; want a copy of %v", c, orig)
+	}
+
+	c.Headers.Set("X-Reason", "changed")
+	c.ops[0] = "changed"
+	c.Kind = Internal
+	if got := orig.Headers.Get("X-Reason"); got != "gone" {
+		t.Errorf("original header = %q; want %q", got, "gone")
+	}
+	if Ops(orig)[0] != "svc.Get" || orig.Kind != NotExist {
+		t.Errorf("original changed to %+v", orig)
+	}
+
+	for _, err := range []error{nil, Str("plain"), (*Error)(nil)} {
+		if got := Clone(err); got != err {
+			t.Errorf("Clone(%#v) = %#v; want it unchanged", err, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	codeMu sync.RWMutex
+	// codes maps each registered Code to its description.
+	codes = map[Code]string{}
+	// codeDefaults maps the Codes registered with RegisterCodeDefaults
+	// to their default Kind and HTTP status code.
+	codeDefaults = map[Code]codeDefault{}
+)
+
+// codeDefault is the default Kind and HTTP status code of a Code.
+type codeDefault struct {
+	kind   Kind
+	status int
+}
+
+// CodeInfo describes a Code registered with RegisterCode.
+type CodeInfo struct {
+	Code        Code
+	Description string
+}
+
+// RegisterCode registers code with a description of the problem it
+// identifies, and returns it, so Codes can be declared as variables:
+//
+//	var CodeUserNotFound = errors.RegisterCode("user_not_found", "No user has the given ID.")
+//
+// RegisterCode panics if code is empty or was already registered, so
+// the same Code cannot be reused for different problems by accident.
+// It is meant to be called during program initialization.
+func RegisterCode(code Code, description string) Code {
+	if code == "" {
+		panic("errors: RegisterCode called with an empty Code")
+	}
+	codeMu.Lock()
+	defer codeMu.Unlock()
+	if prev, ok := codes[code]; ok {
+		panic(fmt.Sprintf("errors: Code %q registered twice, for %q and %q", code, prev, description))
+	}
+	codes[code] = description
+	return code
+}
+
+// RegisterCodeDefaults sets the Kind and the HTTP status code of the
+// errors built by RE with the given Code, when they are not given,
+// so each Code is sent consistently:
+//
+//	errors.RegisterCodeDefaults("USER_NOT_FOUND", errors.NotExist, http.StatusNotFound)
+//	...
+//	return errors.RE("USER_NOT_FOUND") // 404, NotExist
+//
+// The Kind inherited from the underlying error given to RE takes
+// precedence over the default Kind. If status is 0, the status code
+// mapped to the Kind is used (see KindStatus). It overrides the
+// defaults previously registered for code.
+func RegisterCodeDefaults(code Code, kind Kind, status int) {
+	codeMu.Lock()
+	defer codeMu.Unlock()
+	codeDefaults[code] = codeDefault{kind: kind, status: status}
+}
+
+// defaultsOf returns the defaults registered for code, if any.
+func defaultsOf(code Code) (codeDefault, bool) {
+	codeMu.RLock()
+	defer codeMu.RUnlock()
+	d, ok := codeDefaults[code]
+	return d, ok
+}
+
+// codeDescription returns the description code was registered with,
+// or "" if it was not registered.
+func codeDescription(code Code) string {
+	codeMu.RLock()
+	defer codeMu.RUnlock()
+	return codes[code]
+}
+
+// Codes returns the Codes registered with RegisterCode, sorted by
+// Code, e.g. to generate the documentation of an API.
+func Codes() []CodeInfo {
+	codeMu.RLock()
+	defer codeMu.RUnlock()
+	infos := make([]CodeInfo, 0, len(codes))
+	for c, d := range codes {
+		infos = append(infos, CodeInfo{Code: c, Description: d})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Code < infos[j].Code
+	})
+	return infos
+}
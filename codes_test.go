@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterCode(t *testing.T) {
+	defer func() {
+		codeMu.Lock()
+		codes = map[Code]string{}
+		codeMu.Unlock()
+	}()
+
+	if got := RegisterCode("user_not_found", "No user has the given ID."); got != "user_not_found" {
+		t.Errorf("RegisterCode() = %q; want user_not_found", got)
+	}
+	RegisterCode("card_declined", "The card was declined.")
+	want := []CodeInfo{
+		{"card_declined", "The card was declined."},
+		{"user_not_found", "No user has the given ID."},
+	}
+	if got := Codes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Codes() = %v; want %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		code Code
+	}{
+		{"Duplicate", "user_not_found"},
+		{"Empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterCode(%q) did not panic", tt.code)
+				}
+			}()
+			RegisterCode(tt.code, "Another problem.")
+		})
+	}
+}
+
+func TestRegisterCodeDefaults(t *testing.T) {
+	defer func() {
+		codeMu.Lock()
+		codeDefaults = map[Code]codeDefault{}
+		codeMu.Unlock()
+	}()
+	RegisterCodeDefaults("USER_NOT_FOUND", NotExist, http.StatusNotFound)
+	RegisterCodeDefaults("ACCOUNT_LOCKED", Permission, 0)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   Kind
+		wantStatus int
+	}{
+		{"Code alone", RE("USER_NOT_FOUND"), NotExist, http.StatusNotFound},
+		{"Kind status", RE(Code("ACCOUNT_LOCKED")), Permission, http.StatusForbidden},
+		{"Given status", RE(http.StatusGone, Code("USER_NOT_FOUND")), NotExist, http.StatusGone},
+		{"Given Kind", RE(Validation, Code("USER_NOT_FOUND")), Validation, http.StatusNotFound},
+		{"Inherited Kind", RE(Code("USER_NOT_FOUND"), E(Op("db.Get"), Database)), Database, http.StatusNotFound},
+		{"Inherited Code", RE(E(Op("users.Get"), Code("USER_NOT_FOUND"))), NotExist, http.StatusNotFound},
+		{"Unregistered", RE(Code("OTHER")), Other, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.err.(*HTTPErr)
+			if e.Kind != tt.wantKind || e.Status() != tt.wantStatus {
+				t.Errorf("Kind, Status() = %v, %d; want %v, %d", e.Kind, e.Status(), tt.wantKind, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package errors
+
+import "strconv"
+
+// The functions below build the errors most commonly returned by
+// handlers, with their Kind set. Errors for the client, such as
+// NotFound, are returned as an *HTTPErr which wraps an *Error for op,
+// so they are sent with the HTTP status code mapped to their Kind (see
+// KindStatus) and keep op in their chain of operations (see Ops).
+
+// NotFound returns an error of Kind NotExist for op with the message
+// msg, which is sent as an HTTP 404.
+func NotFound(op Op, msg string) error {
+	return httpErr(op, NotExist, "", Str(msg))
+}
+
+// AlreadyExists returns an error of Kind Exist for op with the message
+// msg, which is sent as an HTTP 409.
+func AlreadyExists(op Op, msg string) error {
+	return httpErr(op, Exist, "", Str(msg))
+}
+
+// Forbidden returns an error of Kind Permission for op with the
+// message msg, which is sent as an HTTP 403. It is meant for clients
+// which are authenticated but not allowed to perform op; clients which
+// are not authenticated get an Unauthenticated error instead.
+func Forbidden(op Op, msg string) error {
+	return httpErr(op, Permission, "", Str(msg))
+}
+
+// Unauthenticated returns an error of Kind Unauthorized for op, for
+// requests with missing or invalid credentials, which is sent as an
+// HTTP 401 with a WWW-Authenticate header challenging the client to
+// authenticate with the given scheme and realm, as RFC 7235 requires:
+//
+//	WWW-Authenticate: Bearer realm="api"
+//
+// The realm is omitted from the challenge if it is empty.
+func Unauthenticated(op Op, scheme, realm string) error {
+	challenge := scheme
+	if realm != "" {
+		challenge += " realm=" + strconv.Quote(realm)
+	}
+	hse := httpErr(op, Unauthorized, "", Str("authentication required")).(*HTTPErr)
+	hse.addHeader(Header("WWW-Authenticate", challenge))
+	return hse
+}
+
+// InvalidParam returns an error of Kind Validation for op about the
+// parameter param, with the message msg, which is sent as an HTTP 400.
+func InvalidParam(op Op, param Parameter, msg string) error {
+	return httpErr(op, Validation, param, Str(msg))
+}
+
+// InternalError returns an error of Kind Internal for op which wraps
+// err. Unlike the other constructors, it returns an *Error, so it is
+// sent by HTTPError as an HTTP 500 with a generic message, and the
+// message of err is only logged.
+func InternalError(op Op, err error) error {
+	e := &Error{Op: op, Kind: Internal, Err: err}
+	if inner, ok := err.(*Error); ok {
+		// Make a copy, as E does
+		e.Err = copyError(inner)
+	}
+	e.populateStack()
+	e.trace = captureStack(1)
+	e.pc = captureLocation(0)
+	e.recordCreated()
+	return e
+}
+
+// httpErr returns an *HTTPErr of the given Kind and Parameter, which
+// wraps an *Error for op with the same Kind and Parameter.
+func httpErr(op Op, kind Kind, param Parameter, err error) error {
+	e := &Error{Op: op, Kind: kind, Param: param, Err: err}
+	e.populateStack()
+	e.trace = captureStack(2)
+	e.pc = captureLocation(1)
+	e.recordCreated()
+	hse := &HTTPErr{Kind: kind, Param: param, Err: StripStack(e), trace: e.trace, pc: e.pc}
+	hse.recordCreated()
+	return hse
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestConstructors(t *testing.T) {
+	const op Op = "service.Get"
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantKind Kind
+		wantMsg  string
+	}{
+		{"NotFound", NotFound(op, "no user jane@doe.com"), http.StatusNotFound, NotExist, "no user jane@doe.com"},
+		{"AlreadyExists", AlreadyExists(op, "user exists"), http.StatusConflict, Exist, "user exists"},
+		{"Forbidden", Forbidden(op, "not your user"), http.StatusForbidden, Permission, "not your user"},
+		{"Unauthenticated", Unauthenticated(op, "Bearer", "api"), http.StatusUnauthorized, Unauthorized, "authentication required"},
+		{"InvalidParam", InvalidParam(op, "id", "id is not a number"), http.StatusBadRequest, Validation, "id is not a number"},
+		{"InternalError", InternalError(op, Str("pq: connection refused")), http.StatusInternalServerError, Unanticipated, "Unexpected error - contact support"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantCode)
+			}
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Kind != tt.wantKind.String() || er.Error.Message != tt.wantMsg {
+				t.Errorf("body = %+v; want Kind %q and Message %q", er.Error, tt.wantKind, tt.wantMsg)
+			}
+			if got := Ops(tt.err); !reflect.DeepEqual(got, []Op{op}) {
+				t.Errorf("Ops() = %v; want [%s]", got, op)
+			}
+			if frames := stackOf(tt.err).Frames(); len(frames) > 0 && frames[0].Function != "github.com/gilcrest/errors.TestConstructors" {
+				t.Errorf("first frame = %q; want the caller", frames[0].Function)
+			}
+		})
+	}
+
+	var e *Error
+	if !stderrors.As(InvalidParam(op, "id", "id is not a number"), &e) || e.Param != "id" {
+		t.Errorf("InvalidParam() does not wrap an *Error with Param id")
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	const op Op = "service.Get"
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Unauthenticated", Unauthenticated(op, "Bearer", "api"), `Bearer realm="api"`},
+		{"No realm", Unauthenticated(op, "Basic", ""), "Basic"},
+		{"Forbidden", Forbidden(op, "not your user"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+			if got := rr.Header().Get("WWW-Authenticate"); got != tt.want {
+				t.Errorf("WWW-Authenticate = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
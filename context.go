@@ -0,0 +1,50 @@
+package errors
+
+import "context"
+
+// contextKey is the type of the keys used to store values in a
+// context by this package.
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	localeKey
+	debugKey
+	versionKey
+	encoderKey
+	logLevelsKey
+	actorKey
+	resourceKey
+	errorIDKey
+	warningsKey
+	originKey
+	requestKey
+	requestInfoKey
+)
+
+// WithRequestID returns a copy of ctx which carries the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the request ID added to ctx with
+// WithRequestID, or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestIDFunc extracts the request (or trace) ID from the context
+// given to HTTPErrorCtx. By default, it returns the ID added with
+// WithRequestID. Set it to use an ID stored in the context by other
+// middleware, e.g. under your own context key.
+var RequestIDFunc = RequestIDFromContext
+
+// requestFields returns the log fields for a request with the given ID.
+func requestFields(requestID string) Fields {
+	f := Fields{}
+	if requestID != "" {
+		f["request_id"] = requestID
+	}
+	return f
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// CORSPolicy is the CORS policy of the error responses (see CORS).
+type CORSPolicy struct {
+	// AllowedOrigins are the origins allowed to read the responses,
+	// e.g. "https://app.example.com", or "*" for any origin.
+	AllowedOrigins []string
+	// AllowCredentials allows the responses to requests with
+	// credentials, such as cookies, to be read by the origins listed
+	// in AllowedOrigins. It does not apply to the origins only allowed
+	// by "*", as any site could then read the responses of its users.
+	AllowCredentials bool
+	// ExposedHeaders are the response headers the browser lets
+	// scripts read, e.g. "Retry-After" or FingerprintHeader.
+	ExposedHeaders []string
+}
+
+// CORS, if set, is the CORS policy applied to the error responses sent
+// by HTTPError, so browser clients can read them even when a handler
+// fails before the CORS middleware sets its headers. The origin of the
+// request is the Origin header kept in the context by RequestContext;
+// errors sent without it, e.g. with HTTPError, are only readable if any
+// origin is allowed. The CORS headers already set on the response, if
+// any, are kept. It is nil by default.
+var CORS *CORSPolicy
+
+// setCORS sets the CORS headers of the response of w, for the origin
+// of the request of ctx, if CORS is set and allows it.
+func setCORS(ctx context.Context, w http.ResponseWriter) {
+	p := CORS
+	if p == nil {
+		return
+	}
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") != "" {
+		// Set by a CORS middleware, or already by HTTPError
+		return
+	}
+	origin, _ := ctx.Value(originKey).(string)
+	var any, allowed bool
+	for _, o := range p.AllowedOrigins {
+		switch o {
+		case "*":
+			any = true
+		case origin:
+			allowed = origin != ""
+		}
+	}
+	switch {
+	case allowed && (p.AllowCredentials || !any):
+		// The origin is sent back, as credentials are not allowed
+		// with a wildcard
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		if p.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+	case any:
+		// Credentials are never allowed for any origin
+		h.Set("Access-Control-Allow-Origin", "*")
+	default:
+		return
+	}
+	if len(p.ExposedHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	defer func() { CORS = nil }()
+	const app = "https://app.example.com"
+	tests := []struct {
+		name        string
+		policy      *CORSPolicy
+		origin      string
+		problem     bool
+		wantOrigin  string
+		wantCreds   string
+		wantExposed string
+	}{
+		{"Disabled", nil, app, false, "", "", ""},
+		{"Any", &CORSPolicy{AllowedOrigins: []string{"*"}}, app, false, "*", "", ""},
+		{"Any without Origin", &CORSPolicy{AllowedOrigins: []string{"*"}}, "", false, "*", "", ""},
+		{"Listed", &CORSPolicy{AllowedOrigins: []string{"https://other.example.com", app}}, app, false, app, "", ""},
+		{"Not listed", &CORSPolicy{AllowedOrigins: []string{"https://other.example.com"}}, app, false, "", "", ""},
+		{"Credentials", &CORSPolicy{AllowedOrigins: []string{app}, AllowCredentials: true}, app, false, app, "true", ""},
+		{"Credentials listed and any", &CORSPolicy{AllowedOrigins: []string{"*", app}, AllowCredentials: true}, app, false, app, "true", ""},
+		{"Credentials with any origin", &CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}, app, false, "*", "", ""},
+		{"Credentials without Origin", &CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}, "", false, "*", "", ""},
+		{"Exposed", &CORSPolicy{AllowedOrigins: []string{app}, ExposedHeaders: []string{"Retry-After", "X-Error-Fingerprint"}}, app, false, app, "", "Retry-After, X-Error-Fingerprint"},
+		{"Problem", &CORSPolicy{AllowedOrigins: []string{app}}, app, true, app, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			CORS = tt.policy
+			ProblemDetails = tt.problem
+			defer func() { ProblemDetails = false }()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			rr := httptest.NewRecorder()
+			HTTPErrorCtx(RequestContext(r), rr, NotFound("users.Get", "no such user"))
+
+			h := rr.Header()
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q; want %q", got, tt.wantOrigin)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials"); got != tt.wantCreds {
+				t.Errorf("Access-Control-Allow-Credentials = %q; want %q", got, tt.wantCreds)
+			}
+			if got := h.Get("Access-Control-Expose-Headers"); got != tt.wantExposed {
+				t.Errorf("Access-Control-Expose-Headers = %q; want %q", got, tt.wantExposed)
+			}
+		})
+	}
+
+	// The headers of a CORS middleware are kept
+	CORS = &CORSPolicy{AllowedOrigins: []string{"*"}}
+	rr := httptest.NewRecorder()
+	rr.Header().Set("Access-Control-Allow-Origin", app)
+	HTTPErrorCtx(context.Background(), rr, NotFound("users.Get", "no such user"))
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != app {
+		t.Errorf("Access-Control-Allow-Origin = %q; want %q", got, app)
+	}
+}
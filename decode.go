@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// FromJSONDecodeErr converts an error returned by encoding/json while
+// decoding a request body into an HTTP 400 error whose message does
+// not expose Go types, e.g.
+//
+//	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+//		return errors.FromJSONDecodeErr(err)
+//	}
+//
+// The errors converted are:
+//   - *json.SyntaxError: Kind InvalidRequest and Code InvalidJSON, with
+//     the byte offset of the error in the message;
+//   - *json.UnmarshalTypeError: Kind Validation and Code InvalidType,
+//     with the field as the Parameter, and its expected JSON type and
+//     the byte offset of the error in the message;
+//   - an unknown field error, returned when DisallowUnknownFields is
+//     set: Kind Validation and Code UnknownField, with the field as
+//     the Parameter;
+//   - io.EOF: Kind InvalidRequest and Code EmptyBody;
+//   - io.ErrUnexpectedEOF: Kind InvalidRequest and Code InvalidJSON.
+//
+// The returned error wraps err. Other errors, such as the errors of
+// reading the body, are returned as is. If err is nil,
+// FromJSONDecodeErr returns nil.
+func FromJSONDecodeErr(err error) error {
+	var (
+		kind  = InvalidRequest
+		code  Code
+		param Parameter
+		msg   string
+
+		se *json.SyntaxError
+		te *json.UnmarshalTypeError
+	)
+	switch {
+	case err == nil:
+		return nil
+	case stderrors.As(err, &se):
+		code = "InvalidJSON"
+		msg = fmt.Sprintf("request body is not valid JSON (at byte %d)", se.Offset)
+	case stderrors.As(err, &te):
+		kind, code = Validation, "InvalidType"
+		if te.Field != "" {
+			param = Parameter(te.Field)
+			msg = fmt.Sprintf("%s must be %s, not %s (at byte %d)", te.Field, jsonType(te.Type), te.Value, te.Offset)
+		} else {
+			msg = fmt.Sprintf("request body must be %s, not %s", jsonType(te.Type), te.Value)
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// The error of DisallowUnknownFields has no type of its own
+		kind, code = Validation, "UnknownField"
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		param = Parameter(field)
+		msg = fmt.Sprintf("%s is not a known field", field)
+	case stderrors.Is(err, io.EOF):
+		code = "EmptyBody"
+		msg = "request body is empty"
+	case stderrors.Is(err, io.ErrUnexpectedEOF):
+		code = "InvalidJSON"
+		msg = "request body is not valid JSON (unexpected end)"
+	default:
+		return err
+	}
+	e := &HTTPErr{Kind: kind, Code: code, Param: param, Err: &strippedError{s: msg, err: err}}
+	e.trace = captureStack(1)
+	e.pc = captureLocation(0)
+	e.recordCreated()
+	return e
+}
+
+// jsonType returns the JSON type, with an article, of the values
+// decoded into Go values of type t, e.g. "a number" for an int.
+func jsonType(t reflect.Type) string {
+	if t == nil {
+		return "a value"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return "a value"
+}
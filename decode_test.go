@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFromJSONDecodeErr(t *testing.T) {
+	type address struct {
+		Zip string `json:"zip"`
+	}
+	type user struct {
+		Name    string  `json:"name"`
+		Age     int     `json:"age"`
+		Address address `json:"address"`
+	}
+	decode := func(body string) error {
+		d := json.NewDecoder(strings.NewReader(body))
+		d.DisallowUnknownFields()
+		var u user
+		return d.Decode(&u)
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		wantKind  Kind
+		wantCode  Code
+		wantParam Parameter
+		wantMsg   string
+	}{
+		{"Syntax", `{"name": "jane",}`, InvalidRequest, "InvalidJSON", "", "request body is not valid JSON (at byte 17)"},
+		{"Type", `{"age": "ten"}`, Validation, "InvalidType", "age", "age must be an integer, not string (at byte 13)"},
+		{"Nested type", `{"address": {"zip": 75001}}`, Validation, "InvalidType", "address.zip", "address.zip must be a string, not number (at byte 25)"},
+		{"Body type", `[1]`, Validation, "InvalidType", "", "request body must be an object, not array"},
+		{"Unknown field", `{"email": "jane@doe.com"}`, Validation, "UnknownField", "email", "email is not a known field"},
+		{"Empty", ``, InvalidRequest, "EmptyBody", "", "request body is empty"},
+		{"Truncated", `{"name": "ja`, InvalidRequest, "InvalidJSON", "", "request body is not valid JSON (unexpected end)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			derr := decode(tt.body)
+			err := FromJSONDecodeErr(derr)
+			var e *HTTPErr
+			if !stderrors.As(err, &e) {
+				t.Fatalf("FromJSONDecodeErr(%v) = %T; want an *HTTPErr", derr, err)
+			}
+			if e.Status() != http.StatusBadRequest || e.Kind != tt.wantKind || e.Code != tt.wantCode || e.Param != tt.wantParam {
+				t.Errorf("FromJSONDecodeErr(%v) = %d %v %q %q; want 400 %v %q %q",
+					derr, e.Status(), e.Kind, e.Code, e.Param, tt.wantKind, tt.wantCode, tt.wantParam)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("message = %q; want %q", err.Error(), tt.wantMsg)
+			}
+			if !stderrors.Is(err, derr) {
+				t.Errorf("FromJSONDecodeErr(%v) does not wrap it", derr)
+			}
+		})
+	}
+
+	if err := FromJSONDecodeErr(nil); err != nil {
+		t.Errorf("FromJSONDecodeErr(nil) = %v; want nil", err)
+	}
+	if err := FromJSONDecodeErr(io.ErrClosedPipe); err != io.ErrClosedPipe {
+		t.Errorf("FromJSONDecodeErr(io.ErrClosedPipe) = %v; want it unchanged", err)
+	}
+}
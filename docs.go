@@ -0,0 +1,30 @@
+package errors
+
+import "sync"
+
+var (
+	docMu sync.RWMutex
+	// docURLs maps error Codes to the URL of their documentation.
+	docURLs = map[Code]string{}
+)
+
+// RegisterDocURL sets the URL of the documentation of the given error
+// Code, e.g. a page describing how to remedy the error. It is sent in
+// the doc_url member of the error responses for errors with that Code,
+// so API consumers can click through to it.
+func RegisterDocURL(code Code, url string) {
+	docMu.Lock()
+	defer docMu.Unlock()
+	docURLs[code] = url
+}
+
+// DocURL returns the URL of the documentation registered for the
+// given Code with RegisterDocURL, or "" if there is none.
+func DocURL(code Code) string {
+	if code == "" {
+		return ""
+	}
+	docMu.RLock()
+	defer docMu.RUnlock()
+	return docURLs[code]
+}
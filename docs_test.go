@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDocURL(t *testing.T) {
+	defer func() {
+		docMu.Lock()
+		delete(docURLs, "card_declined")
+		docMu.Unlock()
+		ProblemDetails = false
+	}()
+	const url = "https://example.com/docs/errors/card_declined"
+	RegisterDocURL("card_declined", url)
+
+	declined := RE(http.StatusPaymentRequired, Code("card_declined"), Str("card declined"))
+	rr := httptest.NewRecorder()
+	HTTPError(rr, declined)
+	var er ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if er.Error.DocURL != url {
+		t.Errorf("doc_url = %q; want %q", er.Error.DocURL, url)
+	}
+
+	rr = httptest.NewRecorder()
+	HTTPError(rr, RE(http.StatusNotFound, Code("no_user"), Str("no such user")))
+	er = ErrResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if er.Error.DocURL != "" {
+		t.Errorf("doc_url = %q for an unregistered Code; want none", er.Error.DocURL)
+	}
+
+	ProblemDetails = true
+	rr = httptest.NewRecorder()
+	HTTPError(rr, declined)
+	var pr ProblemResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &pr); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if pr.Type != url {
+		t.Errorf("type = %q; want %q", pr.Type, url)
+	}
+}
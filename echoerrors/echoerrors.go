@@ -0,0 +1,40 @@
+// Package echoerrors sends errors built with the errors package as
+// error responses from Echo handlers, in the same format as
+// errors.HTTPError.
+package echoerrors
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/gilcrest/errors"
+	"github.com/labstack/echo/v4"
+)
+
+// Error sends err to the client as errors.HTTPErrorCtx does, using the
+// context of the request. An *echo.HTTPError, as returned by Echo for
+// unknown routes or by its middleware, is sent with its status code
+// and message. If err is nil, Error does nothing.
+func Error(c echo.Context, err error) {
+	if err == nil {
+		return
+	}
+	var he *echo.HTTPError
+	if stderrors.As(err, &he) {
+		err = errors.RE(he.Code, errors.Str(fmt.Sprint(he.Message)))
+	}
+	errors.HTTPErrorCtx(errors.RequestContext(c.Request()), c.Response(), err)
+}
+
+// HTTPErrorHandler is an echo.HTTPErrorHandler which sends errors
+// with Error. Register it to send every error returned by handlers
+// in the format of errors.HTTPError:
+//
+//	e := echo.New()
+//	e.HTTPErrorHandler = echoerrors.HTTPErrorHandler
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	Error(c, err)
+}
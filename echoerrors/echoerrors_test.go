@@ -0,0 +1,45 @@
+package echoerrors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.GET("/error", func(c echo.Context) error {
+		return errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("user_not_found"), errors.Str("no such user"))
+	})
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantMsg  string
+	}{
+		{"/error", http.StatusNotFound, "no such user"},
+		{"/unknown", http.StatusNotFound, "Not Found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			e.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantCode)
+			}
+			var er errors.ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Message != tt.wantMsg {
+				t.Errorf("Message = %q; want %q", er.Error.Message, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ResponseEncoder encodes the error responses sent by HTTPError in a
+// content type other than JSON. Register an implementation for the
+// media types it serves with RegisterEncoder.
+type ResponseEncoder interface {
+	// ContentType returns the Content-Type of the encoded responses.
+	ContentType() string
+	// Encode writes the encoding of the response body v to w. v is
+	// an ErrResponse or an ErrResponseV2 (see ResponseVersion).
+	Encode(w io.Writer, v interface{}) error
+}
+
+var (
+	encoderMu sync.RWMutex
+	// encoders maps media types to the ResponseEncoder for them.
+	encoders = map[string]ResponseEncoder{
+		"application/xml":       xmlEncoder{},
+		"text/xml":              xmlEncoder{},
+		"application/msgpack":   msgpackEncoder{},
+		"application/x-msgpack": msgpackEncoder{},
+	}
+)
+
+// RegisterEncoder sets the ResponseEncoder of error responses for
+// clients which accept the given media type, e.g. "application/cbor".
+// It overrides the default encoders, which serve XML for
+// application/xml and text/xml, and MessagePack for
+// application/msgpack and application/x-msgpack.
+func RegisterEncoder(mediaType string, enc ResponseEncoder) {
+	encoderMu.Lock()
+	defer encoderMu.Unlock()
+	encoders[strings.ToLower(mediaType)] = enc
+}
+
+// WithResponseEncoder returns a copy of ctx which sets the encoder of
+// the error responses sent with HTTPErrorCtx. If enc is nil, responses
+// are sent as JSON.
+func WithResponseEncoder(ctx context.Context, enc ResponseEncoder) context.Context {
+	return context.WithValue(ctx, encoderKey, encoderValue{enc})
+}
+
+// encoderValue wraps the ResponseEncoder stored in a context, so a nil
+// encoder can be told apart from no encoder.
+type encoderValue struct {
+	enc ResponseEncoder
+}
+
+// responseEncoder returns the encoder of the error responses for the
+// request of ctx, or nil if they are sent as JSON.
+func responseEncoder(ctx context.Context) ResponseEncoder {
+	v, _ := ctx.Value(encoderKey).(encoderValue)
+	return v.enc
+}
+
+// acceptEncoder returns the ResponseEncoder of the media type preferred
+// by a client with the given Accept header, or nil if it prefers JSON,
+// or accepts no media type with a registered encoder.
+func acceptEncoder(accept string) ResponseEncoder {
+	var (
+		best  ResponseEncoder
+		bestQ float64
+	)
+	encoderMu.RLock()
+	defer encoderMu.RUnlock()
+	for _, mr := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(mr)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		switch mt {
+		case "application/json", "application/*", "*/*":
+			best, bestQ = nil, q
+			continue
+		}
+		if enc, ok := encoders[mt]; ok {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// xmlEncoder encodes error responses as XML, in a response element.
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (xmlEncoder) Encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).EncodeElement(v, xml.StartElement{Name: xml.Name{Local: "response"}})
+}
+
+// msgpackEncoder encodes error responses as MessagePack, with the same
+// structure and keys as their JSON encoding.
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpackEncoder) Encode(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	_, err = w.Write(appendMsgpack(nil, doc))
+	return err
+}
+
+// sendEncoded sends the error response v encoded with enc, with the
+// given status code, compressed if the request of ctx accepts it (see
+// GzipMinLength). If v cannot be encoded, the static response is sent.
+func sendEncoded(ctx context.Context, w http.ResponseWriter, enc ResponseEncoder, status int, v interface{}) {
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, v); err != nil {
+		sendMarshalFailure(w, status, err, "application/json", staticResponse)
+		return
+	}
+	writeBody(ctx, w, status, enc.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAcceptEncoder(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   ResponseEncoder
+	}{
+		{"", nil},
+		{"application/json", nil},
+		{"application/xml", xmlEncoder{}},
+		{"text/xml; charset=utf-8", xmlEncoder{}},
+		{"application/msgpack", msgpackEncoder{}},
+		{"application/x-msgpack", msgpackEncoder{}},
+		{"text/html, application/xml", xmlEncoder{}},
+		{"application/json, application/xml;q=0.9", nil},
+		{"application/json;q=0.5, application/msgpack", msgpackEncoder{}},
+		{"application/xml;q=0.5, */*", nil},
+		{"application/cbor", nil},
+		{"application/xml;q=bad", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := acceptEncoder(tt.accept); got != tt.want {
+				t.Errorf("acceptEncoder(%q) = %#v, want %#v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorEncoder(t *testing.T) {
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return RE(http.StatusBadRequest, Validation, Parameter("name"), Code("name_required"), Str("name is required"))
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/xml")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want application/xml", ct)
+	}
+	var got ErrResponse
+	if err := xml.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v, body %s", err, rr.Body)
+	}
+	want := ErrResponse{Error: ServiceError{
+		Kind:    Validation.String(),
+		Code:    "name_required",
+		Param:   "name",
+		Message: "name is required",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response = %+v, want %+v", got, want)
+	}
+	if !strings.HasPrefix(rr.Body.String(), xml.Header+"<response>") {
+		t.Errorf("body = %s, want a response element", rr.Body)
+	}
+}
+
+func TestMsgpackEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	err := msgpackEncoder{}.Encode(&buf, ErrResponseV2{
+		Status:  404,
+		Code:    "x",
+		Message: "not found",
+		Fields:  []FieldViolation{},
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := []byte{
+		0x84, // map of 4, keys sorted
+		0xa4, 'c', 'o', 'd', 'e', 0xa1, 'x',
+		0xa6, 'f', 'i', 'e', 'l', 'd', 's', 0x90,
+		0xa7, 'm', 'e', 's', 's', 'a', 'g', 'e', 0xa9, 'n', 'o', 't', ' ', 'f', 'o', 'u', 'n', 'd',
+		0xa6, 's', 't', 'a', 't', 'u', 's', 0xd2, 0, 0, 0x01, 0x94,
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Encode() = % x, want % x", buf.Bytes(), want)
+	}
+}
+
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string { return "text/plain" }
+
+func (textEncoder) Encode(w io.Writer, v interface{}) error {
+	_, err := io.WriteString(w, v.(ErrResponse).Error.Code)
+	return err
+}
+
+func TestWithResponseEncoder(t *testing.T) {
+	ctx := WithResponseEncoder(context.Background(), textEncoder{})
+	rr := httptest.NewRecorder()
+	HTTPErrorCtx(ctx, rr, RE(http.StatusConflict, Exist, Code("taken")))
+
+	if ct := rr.Header().Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	if got := rr.Body.String(); got != "taken" {
+		t.Errorf("body = %q, want %q", got, "taken")
+	}
+}
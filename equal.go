@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"hash/fnv"
+	"io"
+)
+
+// identity is what identifies the class of an error for Equal and
+// Hash, leaving out its message.
+type identity struct {
+	kind  Kind
+	code  Code
+	param Parameter
+	ops   string
+}
+
+// identityOf returns the identity of err.
+func identityOf(err error) identity {
+	return identity{
+		kind:  KindOf(err),
+		code:  chainCode(err),
+		param: chainParam(err),
+		ops:   joinOps(Ops(err)),
+	}
+}
+
+// Equal reports whether a and b are the same error, as far as their
+// classification goes: they have the same Kind, Code and Param (see
+// KindOf) and the same op chain (see Ops). Their messages, which often
+// hold values such as IDs, are not compared, so errors can be compared
+// in tests without depending on their text:
+//
+//	if !errors.Equal(err, errors.RE(op, errors.NotExist, errors.Code("NotFound"))) {
+//
+// Two nil errors are equal, but a nil error is not equal to any other.
+func Equal(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return identityOf(a) == identityOf(b)
+}
+
+// Hash returns a hash of the Kind, Code, Param and op chain of err, so
+// errors which are Equal have the same hash. It can key the maps of
+// sets of distinct errors, e.g. to deduplicate the errors of a batch,
+// or of caches of failed operations. Unlike Fingerprint, it ignores the
+// stack trace of err, and is not meant to be stable across versions of
+// this package. If err is nil, Hash returns 0.
+func Hash(err error) uint64 {
+	if err == nil {
+		return 0
+	}
+	id := identityOf(err)
+	h := fnv.New64a()
+	io.WriteString(h, id.ops)
+	io.WriteString(h, "\x00"+id.kind.String())
+	io.WriteString(h, "\x00"+string(id.code))
+	io.WriteString(h, "\x00"+string(id.param))
+	return h.Sum64()
+}
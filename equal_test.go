@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b error
+		want bool
+	}{
+		{"Same class, other message", E(Op("db.Get"), NotExist, Code("NoUser"), Str("user 1 not found")), E(Op("db.Get"), NotExist, Code("NoUser"), Str("user 2 not found")), true},
+		{"Other Kind", E(Op("db.Get"), NotExist), E(Op("db.Get"), Exist), false},
+		{"Other Code", RE(Validation, Code("TooLong")), RE(Validation, Code("TooShort")), false},
+		{"Other Param", RE(Validation, Parameter("name")), RE(Validation, Parameter("email")), false},
+		{"Other Op", E(Op("db.Get"), IO), E(Op("db.Put"), IO), false},
+		{"Wrapped", E(Op("service.Get"), E(Op("db.Get"), NotExist)), E(Op("service.Get"), E(Op("db.Get"), NotExist, Str("gone"))), true},
+		{"Error and HTTPErr", RE(http.StatusNotFound, NotExist, Code("NoUser")), E(NotExist, Code("NoUser")), true},
+		{"Other errors", Str("a"), Str("b"), true},
+		{"Both nil", nil, nil, true},
+		{"One nil", E(IO), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("Equal() = %v; want %v", got, tt.want)
+			}
+			if got := Equal(tt.b, tt.a); got != tt.want {
+				t.Errorf("Equal() reversed = %v; want %v", got, tt.want)
+			}
+			if tt.want && Hash(tt.a) != Hash(tt.b) {
+				t.Errorf("Hash() = %x, %x; want equal hashes", Hash(tt.a), Hash(tt.b))
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	if got := Hash(nil); got != 0 {
+		t.Errorf("Hash(nil) = %x; want 0", got)
+	}
+
+	seen := map[uint64]bool{}
+	errs := []error{
+		E(Op("db.Get"), NotExist, Str("user 1 not found")),
+		E(Op("db.Get"), NotExist, Str("user 2 not found")),
+		E(Op("db.Get"), IO, Str("connection reset")),
+		RE(Validation, Parameter("name"), Str("too long")),
+	}
+	var distinct []error
+	for _, err := range errs {
+		if h := Hash(err); !seen[h] {
+			seen[h] = true
+			distinct = append(distinct, err)
+		}
+	}
+	if len(distinct) != 3 {
+		t.Errorf("got %d distinct errors; want 3", len(distinct))
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// ErrorIDFunc generates the ID of each error response, which is sent
+// in the response body and added to the log entry of the error as the
+// error_id field, so support can find the log entry of the error a
+// user reports. By default, it is NewULID. Set it to generate IDs in
+// another format, e.g. a fixed ID in tests, or to a function which
+// returns "" to send no ID.
+var ErrorIDFunc = NewULID
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a new ULID (https://github.com/ulid/spec): 26
+// characters encoding the current time in milliseconds and 80 random
+// bits, so IDs are unique and sort by time.
+func NewULID() string {
+	var b [16]byte
+	ms := uint64(now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	rand.Read(b[6:])
+
+	// Encode the 128 bits in 26 groups of 5 bits, from the most
+	// significant, the first group having only 3 bits
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	var s [26]byte
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// withErrorID returns a copy of ctx which carries a new error ID
+// generated with ErrorIDFunc.
+func withErrorID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, errorIDKey, ErrorIDFunc())
+}
+
+// errorIDFromContext returns the error ID added to ctx with
+// withErrorID, or "" if there is none.
+func errorIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(errorIDKey).(string)
+	return id
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Most tests compare whole response bodies, so no error ID is
+	// sent unless a test sets ErrorIDFunc
+	ErrorIDFunc = func() string { return "" }
+	os.Exit(m.Run())
+}
+
+func TestNewULID(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.UnixMilli(1469918176385) }
+
+	id := NewULID()
+	if !regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`).MatchString(id) {
+		t.Fatalf("NewULID() = %q; want 26 Crockford base32 characters", id)
+	}
+	// The example timestamp of the ULID spec
+	if got := id[:10]; got != "01ARYZ6S41" {
+		t.Errorf("NewULID() timestamp = %q; want %q", got, "01ARYZ6S41")
+	}
+	if other := NewULID(); other == id {
+		t.Errorf("NewULID() returned %q twice", id)
+	}
+}
+
+func TestErrorID(t *testing.T) {
+	defer func() { ErrorIDFunc = func() string { return "" } }()
+	ids := []string{"id-1", "id-2"}
+	ErrorIDFunc = func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	for _, want := range []string{"id-1", "id-2"} {
+		w := httptest.NewRecorder()
+		HTTPErrorCtx(context.Background(), w, RE(http.StatusNotFound, NotExist, Str("no user")))
+		var er ErrResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil {
+			t.Fatal(err)
+		}
+		if er.Error.ErrorID != want {
+			t.Errorf("ErrorID = %q; want %q", er.Error.ErrorID, want)
+		}
+		if got := tl.entries[len(tl.entries)-1].fields["error_id"]; got != want {
+			t.Errorf("fields[error_id] = %v; want %q", got, want)
+		}
+	}
+}
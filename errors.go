@@ -12,8 +12,7 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
-
-	"github.com/rs/zerolog/log"
+	"time"
 )
 
 // UserName is a string representing a user
@@ -21,7 +20,8 @@ type UserName string
 
 // Error is the type that implements the error interface.
 // It contains a number of fields, each of different type.
-// An Error value may leave some values unset.
+// An Error value may leave some values unset. The methods of Error
+// may be called on a nil *Error, which behaves as a zero Error.
 type Error struct {
 	// Path is the path name of the item being accessed.
 	Path PathName
@@ -30,6 +30,9 @@ type Error struct {
 	// Op is the operation being performed, usually the name of the method
 	// being invoked (Get, Put, etc.). It should not contain an at sign @.
 	Op Op
+	// The operations following Op in the op chain of the error, when
+	// several are given to E. They are not kept by MarshalBinary.
+	ops []Op
 	// Kind is the class of error, such as permission failure,
 	// or "Other" if its class is unknown or irrelevant.
 	Kind Kind
@@ -38,14 +41,31 @@ type Error struct {
 	Param Parameter
 	// Code is a human-readable, short representation of the error
 	Code Code
+	// Severity determines the level at which the error is logged.
+	Severity Severity
+	// Retryable reports whether the operation may succeed if retried.
+	Retryable bool
+	// RetryAfter is the suggested delay before retrying, if known.
+	RetryAfter time.Duration
+	// Elapsed is the time spent by the operation before it failed,
+	// if known.
+	Elapsed time.Duration
+	// Deadline is the deadline of the operation, if known.
+	Deadline time.Time
 	// The underlying error that triggered this one, if any.
 	Err error
 	// Stack information; used only when the 'debug' build tag is set.
 	stack
+	// The call stack recorded when the error was constructed,
+	// if CaptureStack is true.
+	trace StackTrace
+	// The location where the error was constructed,
+	// if CaptureLocation is true.
+	pc uintptr
 }
 
 func (e *Error) isZero() bool {
-	return e.Path == "" && e.User == "" && e.Op == "" && e.Kind == 0 && e.Err == nil
+	return e == nil || e.Path == "" && e.User == "" && e.Op == "" && e.Kind == 0 && e.Err == nil
 }
 
 var (
@@ -101,6 +121,9 @@ const (
 	Validation                 // Input validation error.
 	Unanticipated              // Unanticipated error.
 	InvalidRequest             // Invalid Request
+	Timeout                    // Operation timed out.
+	Canceled                   // Operation canceled, e.g. by the client.
+	Unauthorized               // Missing or invalid credentials.
 )
 
 func (k Kind) String() string {
@@ -131,12 +154,23 @@ func (k Kind) String() string {
 		return "unanticipated_error"
 	case InvalidRequest:
 		return "invalid_request_error"
+	case Timeout:
+		return "timeout"
+	case Canceled:
+		return "canceled"
+	case Unauthorized:
+		return "unauthorized"
+	}
+	if name, ok := registeredKindName(k); ok {
+		return name
 	}
 	return "unknown_error_kind"
 }
 
 // E builds an error value from its arguments.
-// There must be at least one argument or E panics.
+// If there are no arguments, E does not panic, as it is usually
+// called in error paths, but returns an error of Kind Internal with
+// Code InvalidErrorConstruction, as RE does.
 // The type of each argument determines its meaning.
 // If more than one argument of a given type is presented,
 // only the last one is recorded.
@@ -146,9 +180,12 @@ func (k Kind) String() string {
 //		The Upspin path name of the item being accessed.
 //	upspin.UserName
 //		The Upspin name of the user attempting the operation.
-//	errors.Op
+//	errors.Op, []errors.Op
 //		The operation being performed, usually the method
-//		being invoked (Get, Put, etc.).
+//		being invoked (Get, Put, etc.). If several are given,
+//		from the outermost to the innermost, they all belong
+//		to the op chain of the error (see Ops), so a layer can
+//		add its operation without nesting another Error.
 //	string
 //		Treated as an error message and assigned to the
 //		Err field after a call to errors.Str. To avoid a common
@@ -157,6 +194,17 @@ func (k Kind) String() string {
 //		errors.Str explicitly to avoid this special-casing.
 //	errors.Kind
 //		The class of error, such as permission failure.
+//	errors.Retry
+//		Marks the error as retryable, with the suggested delay
+//		before retrying.
+//	errors.Severity
+//		The level at which the error is logged.
+//	errors.Elapsed, errors.Deadline
+//		The time spent by the operation before it failed, and its
+//		deadline, which are logged.
+//	errors.TemplateData
+//		The data of the message template of the Code, used when
+//		no message or error is given (see RegisterTemplate).
 //	error
 //		The underlying error that triggered this one.
 //
@@ -164,13 +212,18 @@ func (k Kind) String() string {
 // set to non-zero values will appear in the result.
 //
 // If Kind is not specified or Other, we set it to the Kind of
-// the underlying error.
+// the underlying error. Likewise, if Code or Param is not specified,
+// we set it to the Code or Param of the underlying error.
+//
+// E never changes the errors it is given: an underlying *Error is
+// copied, so errors cached as sentinels may be wrapped safely.
 //
 func E(args ...interface{}) error {
 	if len(args) == 0 {
-		panic("call to errors.E with no arguments")
+		args = []interface{}{Internal, Code("InvalidErrorConstruction"), Str("call to errors.E with no arguments")}
 	}
-	e := &Error{}
+	e := newError()
+	var data TemplateData
 	for _, arg := range args {
 		switch arg := arg.(type) {
 		case PathName:
@@ -178,13 +231,17 @@ func E(args ...interface{}) error {
 		case UserName:
 			e.User = arg
 		case Op:
-			e.Op = arg
+			e.addOp(arg)
+		case []Op:
+			for _, op := range arg {
+				e.addOp(op)
+			}
 		case string:
 			// Someone might accidentally call us with a user or path name
 			// that is not of the right type. Take care of that and log it.
 			if strings.Contains(arg, "@") {
 				_, file, line, _ := runtime.Caller(1)
-				log.Error().Msgf("errors.E: unqualified type for %q from %s:%d", arg, file, line)
+				logf(ErrorLevel, "errors.E: unqualified type for %q from %s:%d", arg, file, line)
 				if strings.Contains(arg, "/") {
 					if e.Path == "" { // Don't overwrite a valid path.
 						e.Path = PathName(arg)
@@ -200,24 +257,46 @@ func E(args ...interface{}) error {
 		case Kind:
 			e.Kind = arg
 		case *Error:
-			// Make a copy
-			copy := *arg
-			e.Err = &copy
+			// Make a copy, as the duplicated fields are removed
+			// from it below
+			e.Err = copyError(arg)
 		case error:
 			e.Err = arg
 		case Code:
 			e.Code = arg
 		case Parameter:
 			e.Param = arg
+		case Retry:
+			e.Retryable = true
+			e.RetryAfter = time.Duration(arg)
+		case Severity:
+			e.Severity = arg
+		case Elapsed:
+			e.Elapsed = time.Duration(arg)
+		case Deadline:
+			e.Deadline = time.Time(arg)
+		case TemplateData:
+			data = arg
 		default:
 			_, file, line, _ := runtime.Caller(1)
-			log.Error().Msgf("errors.E: bad call from %s:%d: %v", file, line, args)
+			// Log a copy, so args does not escape to the heap
+			logf(ErrorLevel, "errors.E: bad call from %s:%d: %v", file, line, append([]interface{}(nil), args...))
 			return Errorf("unknown type %T, value %v in error call", arg, arg)
 		}
 	}
+	if e.Err == nil {
+		if msg, ok := templateMessage(e.Code, e.Kind, e.Param, data); ok {
+			e.Err = Str(msg)
+		}
+	}
+
+	// Record the error with the Metrics once its Kind is settled.
+	defer e.recordCreated()
 
 	// Populate stack information (only in debug mode).
 	e.populateStack()
+	e.trace = captureStack(1)
+	e.pc = captureLocation(0)
 	prev, ok := e.Err.(*Error)
 	if !ok {
 		return e
@@ -239,10 +318,28 @@ func E(args ...interface{}) error {
 		e.Kind = prev.Kind
 		prev.Kind = Other
 	}
+	// Likewise for the Code and Param, so the outermost error carries
+	// the full classification. They are not printed, so they are kept
+	// in the inner error too.
+	if e.Code == "" {
+		e.Code = prev.Code
+	}
+	if e.Param == "" {
+		e.Param = prev.Param
+	}
 
 	return e
 }
 
+// addOp adds op to the end of the op chain of e.
+func (e *Error) addOp(op Op) {
+	if e.Op == "" {
+		e.Op = op
+		return
+	}
+	e.ops = append(e.ops, op)
+}
+
 // pad appends str to the buffer if the buffer already has some data.
 func pad(b *bytes.Buffer, str string) {
 	if b.Len() == 0 {
@@ -251,12 +348,19 @@ func pad(b *bytes.Buffer, str string) {
 	b.WriteString(str)
 }
 func (e *Error) Error() string {
+	if e == nil {
+		return "no error"
+	}
 	b := new(bytes.Buffer)
 	e.printStack(b)
 	if e.Op != "" {
 		pad(b, ": ")
 		b.WriteString(string(e.Op))
 	}
+	for _, op := range e.ops {
+		pad(b, ": ")
+		b.WriteString(string(op))
+	}
 	if e.Path != "" {
 		pad(b, ": ")
 		b.WriteString(string(e.Path))
@@ -292,6 +396,16 @@ func (e *Error) Error() string {
 	return b.String()
 }
 
+// Unwrap returns the underlying error that triggered this one, if any.
+// It allows Error to be used with errors.Is and errors.As from the
+// standard library.
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
 // Str recreates the errors.New functionality of the standard Go errors package
 // so we can create simple text errors when needed.
 // Str returns an error that formats as the given text. It is intended to
@@ -411,7 +525,7 @@ func UnmarshalError(b []byte) error {
 		var data []byte
 		data, b = getBytes(b)
 		if len(b) != 0 {
-			log.Error().Msgf("Unmarshal error: trailing bytes")
+			logf(ErrorLevel, "Unmarshal error: trailing bytes")
 		}
 		return Str(string(data))
 	case 'E':
@@ -420,7 +534,7 @@ func UnmarshalError(b []byte) error {
 		err.UnmarshalBinary(b)
 		return &err
 	default:
-		log.Error().Msgf("Unmarshal error: corrupt data %q", b)
+		logf(ErrorLevel, "Unmarshal error: corrupt data %q", b)
 		return Str(string(b))
 	}
 }
@@ -438,59 +552,128 @@ func appendString(b []byte, str string) []byte {
 func getBytes(b []byte) (data, remaining []byte) {
 	u, N := binary.Uvarint(b)
 	if len(b) < N+int(u) {
-		log.Error().Msgf("Unmarshal error: bad encoding")
+		logf(ErrorLevel, "Unmarshal error: bad encoding")
 		return nil, nil
 	}
 	if N == 0 {
-		log.Error().Msgf("Unmarshal error: bad encoding")
+		logf(ErrorLevel, "Unmarshal error: bad encoding")
 		return nil, b
 	}
 	return b[N : N+int(u)], b[N+int(u):]
 }
 
 // Match compares its two error arguments. It can be used to check
-// for expected errors in tests. Both arguments must have underlying
-// type *Error or Match will return false. Otherwise it returns true
-// iff every non-zero element of the first error is equal to the
-// corresponding element of the second.
-// If the Err field is a *Error, Match recurs on that field;
-// otherwise it compares the strings returned by the Error methods.
+// for expected errors in tests, or to branch on the class of an error
+// in business logic. The first argument is a template, which must have
+// type *Error or *HTTPErr or Match will return false. Otherwise it
+// returns true iff an error of the same type in the chain of the second
+// argument (see errors.Unwrap), or in the chain of any of the errors it
+// joins (see errors.Join), has every non-zero element of the template
+// equal to its corresponding element.
+// If the Err field of the template is a *Error or an *HTTPErr, Match
+// recurs on that field; otherwise it compares the strings returned by
+// the Error methods.
 // Elements that are in the second argument but not present in
-// the first are ignored.
+// the template are ignored.
 //
 // For example,
 //	Match(errors.E(upspin.UserName("joe@schmoe.com"), errors.Permission), err)
-// tests whether err is an Error with Kind=Permission and User=joe@schmoe.com.
-func Match(err1, err2 error) bool {
-	e1, ok := err1.(*Error)
-	if !ok {
-		return false
+// tests whether err is an Error with Kind=Permission and User=joe@schmoe.com,
+// and
+//	Match(&errors.HTTPErr{Code: "user_not_found"}, err)
+// tests whether err wraps an HTTPErr with Code=user_not_found.
+func Match(template, err error) bool {
+	for _, err := range chainOf(err) {
+		if matchOne(template, err) {
+			return true
+		}
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range u.Unwrap() {
+				if Match(template, err) {
+					return true
+				}
+			}
+		}
 	}
-	e2, ok := err2.(*Error)
-	if !ok {
-		return false
+	return false
+}
+
+// matchOne reports whether err itself matches template, as described
+// for Match.
+func matchOne(template, err error) bool {
+	switch t := template.(type) {
+	case *Error:
+		e, ok := err.(*Error)
+		if !ok {
+			return false
+		}
+		if t.Path != "" && e.Path != t.Path {
+			return false
+		}
+		if t.User != "" && e.User != t.User {
+			return false
+		}
+		if !matchOps(t.opChain(), e.opChain()) {
+			return false
+		}
+		if !matchClass(t.Kind, t.Code, t.Param, e.Kind, e.Code, e.Param) {
+			return false
+		}
+		return matchErr(t.Err, e.Err)
+	case *HTTPErr:
+		e, ok := err.(*HTTPErr)
+		if !ok {
+			return false
+		}
+		if t.HTTPStatusCode != 0 && e.Status() != t.HTTPStatusCode {
+			return false
+		}
+		if !matchOps(t.ops, e.ops) {
+			return false
+		}
+		if !matchClass(t.Kind, t.Code, t.Param, e.Kind, e.Code, e.Param) {
+			return false
+		}
+		return matchErr(t.Err, e.Err)
 	}
-	if e1.Path != "" && e2.Path != e1.Path {
+	return false
+}
+
+// matchOps reports whether ops starts with the op chain of a template.
+func matchOps(template, ops []Op) bool {
+	if len(ops) < len(template) {
 		return false
 	}
-	if e1.User != "" && e2.User != e1.User {
-		return false
+	for i := range template {
+		if ops[i] != template[i] {
+			return false
+		}
 	}
-	if e1.Op != "" && e2.Op != e1.Op {
+	return true
+}
+
+// matchClass reports whether the Kind, Code and Param of an error
+// match the non-zero ones of a template.
+func matchClass(tKind Kind, tCode Code, tParam Parameter, kind Kind, code Code, param Parameter) bool {
+	if tKind != Other && kind != tKind {
 		return false
 	}
-	if e1.Kind != Other && e2.Kind != e1.Kind {
+	if tCode != "" && code != tCode {
 		return false
 	}
-	if e1.Err != nil {
-		if _, ok := e1.Err.(*Error); ok {
-			return Match(e1.Err, e2.Err)
-		}
-		if e2.Err == nil || e2.Err.Error() != e1.Err.Error() {
-			return false
-		}
-	}
-	return true
+	return tParam == "" || param == tParam
+}
+
+// matchErr reports whether the underlying error of an error matches
+// the underlying error of a template.
+func matchErr(template, err error) bool {
+	switch template.(type) {
+	case nil:
+		return true
+	case *Error, *HTTPErr:
+		return Match(template, err)
+	}
+	return err != nil && err.Error() == template.Error()
 }
 
 // Is reports whether err is an *Error of the given Kind.
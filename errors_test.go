@@ -7,7 +7,9 @@
 package errors
 
 import (
+	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"testing"
@@ -81,13 +83,13 @@ func TestDoesNotChangePreviousError(t *testing.T) {
 	}
 }
 func TestNoArgs(t *testing.T) {
-	defer func() {
-		err := recover()
-		if err == nil {
-			t.Fatal("E() did not panic")
-		}
-	}()
-	_ = E()
+	e, ok := E().(*Error)
+	if !ok {
+		t.Fatalf("E() = %T; want *Error", E())
+	}
+	if e.Kind != Internal || e.Code != "InvalidErrorConstruction" {
+		t.Errorf("E() = %+v; want an Internal error with Code InvalidErrorConstruction", e)
+	}
 }
 
 type matchTest struct {
@@ -143,6 +145,39 @@ func TestMatch(t *testing.T) {
 	}
 }
 
+var matchChainTests = []matchTest{
+	// Code and Param.
+	{E(Code("no_user")), E(op, NotExist, Code("no_user")), true},
+	{E(Code("no_user")), E(op, NotExist, Code("no_group")), false},
+	{E(Parameter("id"), Invalid), E(op, Invalid, Parameter("id")), true},
+	{E(Parameter("id")), E(op, Invalid, Parameter("name")), false},
+	// Several Ops.
+	{E(op1), E(op1, op2, io.EOF), true},
+	{E(op1, op2), E(op1, op2, io.EOF), true},
+	{E(op1, op2), E(op1, io.EOF), false},
+	// Error chains.
+	{E(op2, Code("eof")), E(op1, E(op2, Code("eof"), io.EOF)), true},
+	{E(op2, NotExist), fmt.Errorf("wrapped: %w", E(op2, NotExist)), true},
+	{E(op2, NotExist), RE(http.StatusNotFound, E(op2, NotExist)), true},
+	{E(op2, NotExist), E(op1, E(op2, Exist)), false},
+	// HTTPErr templates.
+	{&HTTPErr{Code: "no_user"}, RE(http.StatusNotFound, NotExist, Code("no_user")), true},
+	{&HTTPErr{HTTPStatusCode: http.StatusNotFound}, RE(NotExist, Code("no_user")), true},
+	{&HTTPErr{HTTPStatusCode: http.StatusConflict}, RE(NotExist, Code("no_user")), false},
+	{&HTTPErr{Kind: NotExist}, fmt.Errorf("wrapped: %w", RE(NotExist)), true},
+	{&HTTPErr{Kind: NotExist}, E(op, NotExist), false},
+	{&HTTPErr{Err: E(op2)}, RE(http.StatusNotFound, E(op1, E(op2, NotExist))), true},
+}
+
+func TestMatchChain(t *testing.T) {
+	for _, test := range matchChainTests {
+		matched := Match(test.err1, test.err2)
+		if matched != test.matched {
+			t.Errorf("Match(%q, %q)=%t; want %t", test.err1, test.err2, matched, test.matched)
+		}
+	}
+}
+
 type kindTest struct {
 	err  error
 	kind Kind
@@ -0,0 +1,15 @@
+// Command errorslint runs the errorslint analyzer, which checks that
+// errors are classified with the Kinds and Codes of the errors package.
+// It is meant to be run by go vet:
+//
+//	go vet -vettool=$(which errorslint) ./...
+package main
+
+import (
+	"github.com/gilcrest/errors/errorslint"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(errorslint.Analyzer)
+}
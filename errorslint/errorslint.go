@@ -0,0 +1,278 @@
+// Package errorslint defines an analyzer which enforces the taxonomy of
+// the errors package at build time. It reports:
+//
+//   - calls of errors.E and errors.RE which are given neither a Kind,
+//     nor a Code, nor an error to inherit them from, so the error is
+//     sent as an unclassified 500 (calls of RE given only a status code
+//     are not reported, as they are meant to send no body);
+//   - string literals given to RE or where an errors.Code is expected,
+//     instead of a Code declared once, e.g. as a constant registered
+//     with errors.RegisterCode, so typos do not create new Codes;
+//   - HTTP handlers returning an error (see errors.HandlerFunc) which
+//     return an error of fmt.Errorf or of the standard errors.New, which
+//     is sent as an unclassified 500.
+//
+// Run it with go vet, using the errorslint command:
+//
+//	go install github.com/gilcrest/errors/errorslint/cmd/errorslint@latest
+//	go vet -vettool=$(which errorslint) ./...
+package errorslint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// errorsPath is the import path of the errors package.
+const errorsPath = "github.com/gilcrest/errors"
+
+// Analyzer reports the errors built or returned outside of the taxonomy
+// of the errors package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorslint",
+	Doc:      "check that errors are classified with the Kinds and Codes of the errors package",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.CallExpr)(nil), (*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			checkCall(pass, n)
+		case *ast.FuncDecl:
+			if fn, ok := pass.TypesInfo.Defs[n.Name].(*types.Func); ok && n.Body != nil {
+				checkHandler(pass, fn.Type(), n.Body)
+			}
+		case *ast.FuncLit:
+			checkHandler(pass, pass.TypesInfo.TypeOf(n), n.Body)
+		}
+	})
+	return nil, nil
+}
+
+// checkCall reports the calls of E and RE which are not classified, and
+// the string literals given where a Code is expected.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := calledFunc(pass, call)
+	if fn == nil {
+		return
+	}
+	if isErrorsFunc(fn, "E") || isErrorsFunc(fn, "RE") {
+		checkConstructor(pass, call, fn.Name())
+		return
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return
+	}
+	for i, arg := range call.Args {
+		if isStringLit(arg) && isCode(paramType(sig, i)) {
+			pass.Reportf(arg.Pos(), "string literal used as an errors.Code: declare the Code once and use it instead")
+		}
+	}
+}
+
+// checkConstructor reports a call of E or RE with neither a Kind, nor
+// a Code, nor an error to inherit them from, and the string literals
+// given to RE, which are taken as its Code.
+func checkConstructor(pass *analysis.Pass, call *ast.CallExpr, name string) {
+	if call.Ellipsis.IsValid() {
+		// The arguments are not known
+		return
+	}
+	classified := false
+	statusOnly := name == "RE" && len(call.Args) > 0
+	for _, arg := range call.Args {
+		t := pass.TypesInfo.TypeOf(arg)
+		switch {
+		case isErrorsType(t, "Kind"), isErrorsType(t, "Code"):
+			classified = true
+		case name == "RE" && isStringLit(arg):
+			classified = true
+			pass.Reportf(arg.Pos(), "string literal used as the Code of errors.RE: declare the Code once and use it instead")
+		case isError(t) && !isMessage(pass, arg):
+			// The Kind and Code are inherited from the error
+			classified = true
+		}
+		if !isInt(t) {
+			statusOnly = false
+		}
+	}
+	if !classified && !statusOnly {
+		pass.Reportf(call.Pos(), "errors.%s call has no Kind or Code: the error is sent as an unclassified 500", name)
+	}
+}
+
+// checkHandler reports the errors of fmt.Errorf and of the standard
+// errors.New returned by a function of type errors.HandlerFunc.
+func checkHandler(pass *analysis.Pass, t types.Type, body *ast.BlockStmt) {
+	if !isHandler(t) {
+		return
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// Checked on its own
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				return true
+			}
+			call, ok := astutil.Unparen(n.Results[0]).(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if fn := calledFunc(pass, call); fn != nil && fn.Pkg() != nil {
+				if p := fn.Pkg().Path(); p == "fmt" && fn.Name() == "Errorf" || p == "errors" && fn.Name() == "New" {
+					pass.Reportf(call.Pos(), "HTTP handler returns an unclassified error of %s.%s, sent as a 500: build it with errors.RE", fn.Pkg().Name(), fn.Name())
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isHandler reports whether t is the type of an errors.HandlerFunc,
+// i.e. func(http.ResponseWriter, *http.Request) error. Methods of that
+// type are handlers too.
+func isHandler(typ types.Type) bool {
+	t, ok := typ.(*types.Signature)
+	if !ok || t.Params().Len() != 2 || t.Results().Len() != 1 {
+		return false
+	}
+	if !isError(t.Results().At(0).Type()) {
+		return false
+	}
+	w, ok := t.Params().At(0).Type().(*types.Named)
+	if !ok || !isNamed(w, "net/http", "ResponseWriter") {
+		return false
+	}
+	r, ok := t.Params().At(1).Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	req, ok := r.Elem().(*types.Named)
+	return ok && isNamed(req, "net/http", "Request")
+}
+
+// isMessage reports whether arg is a call which only builds a message,
+// such as errors.Str, errors.Errorf, fmt.Errorf or the standard
+// errors.New, so the error it gives to E or RE is not classified.
+func isMessage(pass *analysis.Pass, arg ast.Expr) bool {
+	call, ok := astutil.Unparen(arg).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn := calledFunc(pass, call)
+	if fn == nil || fn.Pkg() == nil {
+		return false
+	}
+	switch fn.Pkg().Path() {
+	case errorsPath:
+		return fn.Name() == "Str" || fn.Name() == "Errorf"
+	case "fmt":
+		return fn.Name() == "Errorf"
+	case "errors":
+		return fn.Name() == "New"
+	}
+	return false
+}
+
+// calledFunc returns the function or method called by call, or nil if
+// it is not a statically known function, e.g. a conversion.
+func calledFunc(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch f := astutil.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	case *ast.IndexExpr:
+		// An instantiated generic function
+		return calledFunc(pass, &ast.CallExpr{Fun: f.X})
+	default:
+		return nil
+	}
+	fn, _ := pass.TypesInfo.Uses[id].(*types.Func)
+	return fn
+}
+
+// paramType returns the type of the parameter of sig receiving the
+// argument at index i, or nil if there is none.
+func paramType(sig *types.Signature, i int) types.Type {
+	n := sig.Params().Len()
+	switch {
+	case sig.Variadic() && i >= n-1:
+		if s, ok := sig.Params().At(n - 1).Type().(*types.Slice); ok {
+			return s.Elem()
+		}
+		return nil
+	case i < n:
+		return sig.Params().At(i).Type()
+	}
+	return nil
+}
+
+// isErrorsFunc reports whether fn is the function of the errors package
+// with the given name.
+func isErrorsFunc(fn *types.Func, name string) bool {
+	return fn.Pkg() != nil && fn.Pkg().Path() == errorsPath && fn.Name() == name &&
+		fn.Type().(*types.Signature).Recv() == nil
+}
+
+// isErrorsType reports whether t is the type of the errors package with
+// the given name.
+func isErrorsType(t types.Type, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && isNamed(n, errorsPath, name)
+}
+
+// isCode reports whether t is errors.Code.
+func isCode(t types.Type) bool {
+	return t != nil && isErrorsType(t, "Code")
+}
+
+// isNamed reports whether n is the type of package path with the given
+// name.
+func isNamed(n *types.Named, path, name string) bool {
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == path && obj.Name() == name
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// isError reports whether t implements error.
+func isError(t types.Type) bool {
+	return t != nil && types.Implements(t, errorType)
+}
+
+// isInt reports whether t is an integer type, as the status codes given
+// to RE.
+func isInt(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0
+}
+
+// isStringLit reports whether e is a string literal, or a
+// concatenation of string literals.
+func isStringLit(e ast.Expr) bool {
+	switch e := astutil.Unparen(e).(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.STRING
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && isStringLit(e.X) && isStringLit(e.Y)
+	}
+	return false
+}
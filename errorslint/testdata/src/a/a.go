@@ -0,0 +1,47 @@
+package a
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gilcrest/errors"
+)
+
+const UserNotFound errors.Code = "UserNotFound"
+
+func constructors(err error) {
+	_ = errors.E(errors.Op("users.Get"), errors.NotExist, errors.Str("no user"))
+	_ = errors.E(errors.Op("users.Get"), err)
+	_ = errors.E(errors.Op("users.Get"), errors.Str("no user")) // want `errors.E call has no Kind or Code`
+	_ = errors.RE(http.StatusNotFound, UserNotFound, errors.Str("no user"))
+	_ = errors.RE(http.StatusConflict)
+	_ = errors.RE(http.StatusNotFound, fmt.Errorf("no user %d", 42)) // want `errors.RE call has no Kind or Code`
+	_ = errors.RE(http.StatusNotFound, "user_not_found")             // want `string literal used as the Code of errors.RE`
+	args := []interface{}{errors.Str("no user")}
+	_ = errors.E(args...)
+}
+
+func codes() {
+	_ = errors.DocURL(UserNotFound)
+	_ = errors.DocURL("UserNotFound") // want `string literal used as an errors.Code`
+	errors.RegisterCode(UserNotFound, "the user does not exist")
+}
+
+func getUser(w http.ResponseWriter, r *http.Request) error {
+	if r.URL.Query().Get("id") == "" {
+		return fmt.Errorf("missing id") // want `HTTP handler returns an unclassified error of fmt.Errorf`
+	}
+	if r.Method != http.MethodGet {
+		return stderrors.New("bad method") // want `HTTP handler returns an unclassified error of errors.New`
+	}
+	return errors.E(errors.Op("users.Get"), errors.NotExist, errors.Str("no user"))
+}
+
+func notHandler() error {
+	return fmt.Errorf("not a handler")
+}
+
+var handler = func(w http.ResponseWriter, r *http.Request) error {
+	return fmt.Errorf("in a literal") // want `HTTP handler returns an unclassified error`
+}
@@ -0,0 +1,31 @@
+// Package errors is a stub of the errors package for the tests of the
+// analyzer.
+package errors
+
+type (
+	Kind      uint8
+	Code      string
+	Op        string
+	Parameter string
+)
+
+const (
+	Other Kind = iota
+	NotExist
+	Validation
+)
+
+type Error struct{ Err error }
+
+func (e *Error) Error() string { return "" }
+
+type errorString struct{ s string }
+
+func (e *errorString) Error() string { return e.s }
+
+func E(args ...interface{}) error                  { return &Error{} }
+func RE(args ...interface{}) error                 { return &Error{} }
+func Str(text string) error                        { return &errorString{text} }
+func Errorf(format string, a ...interface{}) error { return &errorString{format} }
+func DocURL(c Code) string                         { return "" }
+func RegisterCode(c Code, description string)      {}
@@ -0,0 +1,76 @@
+// Package errorstest provides assertions for tests of code using the
+// errors package:
+//
+//	err := svc.GetUser(ctx, "42")
+//	errorstest.AssertKind(t, err, errors.NotExist)
+//	errorstest.AssertStatus(t, err, http.StatusNotFound)
+package errorstest
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gilcrest/errors"
+)
+
+// AssertKind reports an error if the Kind of err is not want
+// (see errors.KindOf).
+func AssertKind(t testing.TB, err error, want errors.Kind) {
+	t.Helper()
+	if got := errors.KindOf(err); got != want {
+		t.Errorf("Kind of %v = %v; want %v", err, got, want)
+	}
+}
+
+// AssertCode reports an error if the Code of err is not want
+// (see errors.CodeOf).
+func AssertCode(t testing.TB, err error, want errors.Code) {
+	t.Helper()
+	if got := errors.CodeOf(err); got != want {
+		t.Errorf("Code of %v = %q; want %q", err, got, want)
+	}
+}
+
+// AssertStatus reports an error if err is not sent with the HTTP
+// status code want (see errors.StatusOf).
+func AssertStatus(t testing.TB, err error, want int) {
+	t.Helper()
+	if got := errors.StatusOf(err); got != want {
+		t.Errorf("status of %v = %d; want %d", err, got, want)
+	}
+}
+
+// AssertResponse reports an error if the response recorded by rec is
+// not an error response with the HTTP status code status and the
+// error want, as sent by errors.HTTPError. The request ID, error ID,
+// trace ID and documentation URL of the response are only compared if
+// they are set in want, as they usually depend on the request.
+func AssertResponse(t testing.TB, rec *httptest.ResponseRecorder, status int, want errors.ServiceError) {
+	t.Helper()
+	if rec.Code != status {
+		t.Errorf("response status = %d; want %d", rec.Code, status)
+	}
+	var resp errors.ErrResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Errorf("response body %q is not an error response: %v", rec.Body.String(), err)
+		return
+	}
+	got := resp.Error
+	if want.RequestID == "" {
+		got.RequestID = ""
+	}
+	if want.ErrorID == "" {
+		got.ErrorID = ""
+	}
+	if want.TraceID == "" {
+		got.TraceID = ""
+	}
+	if want.DocURL == "" {
+		got.DocURL = ""
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("response error = %+v; want %+v", got, want)
+	}
+}
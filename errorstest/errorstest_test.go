@@ -0,0 +1,80 @@
+package errorstest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilcrest/errors"
+)
+
+// fakeT records the errors reported by the assertions.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	err := errors.RE(http.StatusConflict, errors.Exist, errors.Code("duplicate_user"), errors.Str("user already exists"))
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		fails  bool
+	}{
+		{"Kind", func(t testing.TB) { AssertKind(t, err, errors.Exist) }, false},
+		{"Wrong Kind", func(t testing.TB) { AssertKind(t, err, errors.NotExist) }, true},
+		{"Code", func(t testing.TB) { AssertCode(t, err, "duplicate_user") }, false},
+		{"Wrong Code", func(t testing.TB) { AssertCode(t, err, "no_user") }, true},
+		{"Status", func(t testing.TB) { AssertStatus(t, err, http.StatusConflict) }, false},
+		{"Wrong Status", func(t testing.TB) { AssertStatus(t, err, http.StatusNotFound) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			tt.assert(ft)
+			if failed := len(ft.errors) > 0; failed != tt.fails {
+				t.Errorf("failed = %v (%q); want %v", failed, ft.errors, tt.fails)
+			}
+		})
+	}
+}
+
+func TestAssertResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := errors.WithRequestID(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "req-1")
+	errors.HTTPErrorCtx(ctx, rec, errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("no_user"), errors.Str("no such user")))
+
+	want := errors.ServiceError{
+		Kind:    errors.NotExist.String(),
+		Code:    "no_user",
+		Message: "no such user",
+	}
+	tests := []struct {
+		name   string
+		status int
+		want   errors.ServiceError
+		fails  bool
+	}{
+		{"Match", http.StatusNotFound, want, false},
+		{"Wrong status", http.StatusBadRequest, want, true},
+		{"Wrong Code", http.StatusNotFound, errors.ServiceError{Kind: want.Kind, Code: "other", Message: want.Message}, true},
+		{"Wrong request ID", http.StatusNotFound, errors.ServiceError{Kind: want.Kind, Code: want.Code, Message: want.Message, RequestID: "req-2"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+			AssertResponse(ft, rec, tt.status, tt.want)
+			if failed := len(ft.errors) > 0; failed != tt.fails {
+				t.Errorf("failed = %v (%q); want %v", failed, ft.errors, tt.fails)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventHeaderPrefix is the prefix of the names of the message headers
+// set by (*EventError).Inject, e.g. "error-kind" for the Kind.
+var EventHeaderPrefix = "error-"
+
+// EventError is the representation of an error in a message, e.g. a
+// message sent to a dead-letter queue after its processing failed, so
+// asynchronous pipelines keep the classification of their errors. It
+// can be sent in the headers of the message (see Inject and
+// ExtractEventError), or encoded as JSON in its payload, and turned
+// back into an error by the consumers with Err.
+type EventError struct {
+	Kind        string   `json:"kind,omitempty"`
+	Code        string   `json:"code,omitempty"`
+	Param       string   `json:"param,omitempty"`
+	Status      int      `json:"status,omitempty"`
+	Message     string   `json:"message,omitempty"`
+	Ops         []string `json:"ops,omitempty"`
+	Retryable   bool     `json:"retryable,omitempty"`
+	RetryAfter  string   `json:"retry_after,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+}
+
+// NewEventError returns the EventError of err: its Kind, Code and
+// Param (see KindOf), the HTTP status code it is sent with (see
+// StatusOf), its op chain (see Ops), whether it is retryable and its
+// Fingerprint. The message is the full message of err, as it is meant
+// for the services of the pipeline rather than for clients, with the
+// values of the MaskedParams masked and truncated to MaxMessageLength.
+// If err is nil, NewEventError returns nil.
+func NewEventError(err error) *EventError {
+	if err == nil {
+		return nil
+	}
+	ee := &EventError{
+		Code:        string(chainCode(err)),
+		Param:       string(chainParam(err)),
+		Status:      StatusOf(err),
+		Retryable:   IsRetryable(err),
+		Fingerprint: Fingerprint(err),
+	}
+	if k := KindOf(err); k != Other {
+		ee.Kind = k.String()
+	}
+	ee.Message, _ = truncateMessage(maskMessage(err, err.Error()))
+	for _, op := range Ops(err) {
+		ee.Ops = append(ee.Ops, string(op))
+	}
+	if d := RetryDelay(err); d > 0 {
+		ee.RetryAfter = d.String()
+	}
+	return ee
+}
+
+// Err returns the error represented by ee, an *HTTPErr built by RE
+// with its status code, Kind, Code, Param, op chain and message, which
+// is retryable if ee is. Its Fingerprint is the one of the original
+// error, unless FingerprintFrames is set. If ee is nil, Err returns
+// nil.
+func (ee *EventError) Err() error {
+	if ee == nil {
+		return nil
+	}
+	args := []interface{}{ee.Status, kindFromString(ee.Kind), Code(ee.Code), Parameter(ee.Param)}
+	if len(ee.Ops) > 0 {
+		ops := make([]Op, len(ee.Ops))
+		for i, op := range ee.Ops {
+			ops[i] = Op(op)
+		}
+		args = append(args, ops)
+	}
+	if ee.Retryable {
+		d, _ := time.ParseDuration(ee.RetryAfter)
+		args = append(args, Retry(d))
+	}
+	if ee.Message != "" {
+		args = append(args, Str(ee.Message))
+	}
+	return RE(args...)
+}
+
+// HeaderCarrier is the set of headers of a message, which EventErrors
+// are injected in and extracted from. Adapt the headers of the message
+// queue client to it, or use MapCarrier.
+type HeaderCarrier interface {
+	// Get returns the value of the header key, or "" if it is not set.
+	Get(key string) string
+	// Set sets the header key to value, replacing any value it has.
+	Set(key, value string)
+}
+
+// MapCarrier is a HeaderCarrier of the headers held in a map.
+type MapCarrier map[string]string
+
+// Get returns the value of the header key.
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set sets the header key to value.
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Inject sets the fields of ee in the headers of c, each in the header
+// named after the JSON name of the field, following EventHeaderPrefix,
+// e.g. "error-kind". The op chain is joined with " -> ". The fields
+// which are not set are not injected.
+func (ee *EventError) Inject(c HeaderCarrier) {
+	set := func(name, value string) {
+		if value != "" {
+			c.Set(EventHeaderPrefix+name, value)
+		}
+	}
+	set("kind", ee.Kind)
+	set("code", ee.Code)
+	set("param", ee.Param)
+	if ee.Status != 0 {
+		set("status", strconv.Itoa(ee.Status))
+	}
+	set("message", ee.Message)
+	set("ops", strings.Join(ee.Ops, " -> "))
+	if ee.Retryable {
+		set("retryable", "true")
+	}
+	set("retry_after", ee.RetryAfter)
+	set("fingerprint", ee.Fingerprint)
+}
+
+// ExtractEventError returns the EventError injected in the headers of
+// c by Inject, or nil if c has none, i.e. if c has neither a Kind, nor
+// a Code, nor a status code header.
+func ExtractEventError(c HeaderCarrier) *EventError {
+	get := func(name string) string {
+		return c.Get(EventHeaderPrefix + name)
+	}
+	ee := &EventError{
+		Kind:        get("kind"),
+		Code:        get("code"),
+		Param:       get("param"),
+		Message:     get("message"),
+		Retryable:   get("retryable") == "true",
+		RetryAfter:  get("retry_after"),
+		Fingerprint: get("fingerprint"),
+	}
+	ee.Status, _ = strconv.Atoi(get("status"))
+	if ops := get("ops"); ops != "" {
+		ee.Ops = strings.Split(ops, " -> ")
+	}
+	if ee.Kind == "" && ee.Code == "" && ee.Status == 0 {
+		return nil
+	}
+	return ee
+}
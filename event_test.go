@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventError(t *testing.T) {
+	dbErr := E(Op("consumer.Handle"), E(Op("db.Exec"), Database, Retry(5*time.Second), Str("connection reset")))
+
+	tests := []struct {
+		name string
+		err  error
+		want *EventError
+	}{
+		{
+			name: "HTTPErr",
+			err:  RE(http.StatusConflict, Op("orders.Create"), Exist, Code("DuplicateOrder"), Parameter("id"), Str("order exists")),
+			want: &EventError{Kind: "item_already_exists", Code: "DuplicateOrder", Param: "id", Status: http.StatusConflict, Message: "order exists", Ops: []string{"orders.Create"}},
+		},
+		{
+			name: "Retryable Error",
+			err:  dbErr,
+			want: &EventError{Kind: "database_error", Status: http.StatusInternalServerError, Message: dbErr.Error(), Ops: []string{"consumer.Handle", "db.Exec"}, Retryable: true, RetryAfter: "5s"},
+		},
+		{
+			name: "Other error",
+			err:  Str("boom"),
+			want: &EventError{Status: http.StatusInternalServerError, Message: "boom"},
+		},
+		{
+			name: "Nil",
+			err:  nil,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewEventError(tt.err)
+			if got != nil {
+				if got.Fingerprint != Fingerprint(tt.err) {
+					t.Errorf("Fingerprint = %q; want %q", got.Fingerprint, Fingerprint(tt.err))
+				}
+				got.Fingerprint = ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewEventError() = %#v; want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventErrorRoundTrip(t *testing.T) {
+	errs := []error{
+		RE(http.StatusConflict, Op("orders.Create"), Exist, Code("DuplicateOrder"), Parameter("id"), Str("order exists")),
+		E(Op("consumer.Handle"), E(Op("db.Exec"), Database, Retry(5*time.Second), Str("connection reset"))),
+		RE(http.StatusBadRequest, Validation, Code("TooLong"), Parameter("name")),
+	}
+	for _, err := range errs {
+		ee := NewEventError(err)
+
+		// Through the headers of a message
+		c := MapCarrier{}
+		ee.Inject(c)
+		fromHeaders := ExtractEventError(c)
+		if !reflect.DeepEqual(fromHeaders, ee) {
+			t.Errorf("ExtractEventError() = %#v; want %#v", fromHeaders, ee)
+		}
+
+		// Through the payload of a message
+		b, jerr := json.Marshal(ee)
+		if jerr != nil {
+			t.Fatal(jerr)
+		}
+		var fromPayload EventError
+		if jerr := json.Unmarshal(b, &fromPayload); jerr != nil {
+			t.Fatal(jerr)
+		}
+
+		got := fromPayload.Err()
+		if !Equal(got, err) {
+			t.Errorf("Err() = %v; want an error Equal to %v", got, err)
+		}
+		if StatusOf(got) != StatusOf(err) || IsRetryable(got) != IsRetryable(err) || RetryDelay(got) != RetryDelay(err) {
+			t.Errorf("Err() = %#v; want the status and retry delay of %#v", got, err)
+		}
+		if Fingerprint(got) != ee.Fingerprint {
+			t.Errorf("Fingerprint(Err()) = %q; want %q", Fingerprint(got), ee.Fingerprint)
+		}
+	}
+}
+
+func TestExtractEventErrorNone(t *testing.T) {
+	c := MapCarrier{"content-type": "application/json"}
+	if ee := ExtractEventError(c); ee != nil {
+		t.Errorf("ExtractEventError() = %#v; want nil", ee)
+	}
+	var ee *EventError
+	if err := ee.Err(); err != nil {
+		t.Errorf("Err() = %v; want nil", err)
+	}
+}
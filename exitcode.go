@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Exit codes of ExitCode, from the sysexits.h convention of BSD.
+const (
+	ExitUsage       = 64  // The command was used incorrectly.
+	ExitDataErr     = 65  // The input data was incorrect.
+	ExitNoInput     = 66  // An input file or item did not exist.
+	ExitUnavailable = 69  // A service was unavailable.
+	ExitSoftware    = 70  // Internal software error.
+	ExitCantCreate  = 73  // An output file or item could not be created.
+	ExitIOErr       = 74  // An error occurred doing I/O.
+	ExitTempFail    = 75  // Temporary failure; the command may be retried.
+	ExitNoPerm      = 77  // Insufficient permission.
+	ExitInterrupted = 130 // Canceled, as by SIGINT in shells.
+)
+
+var (
+	exitMu sync.RWMutex
+	// kindExit maps each Kind to its default exit code.
+	kindExit = map[Kind]int{
+		Other:          1,
+		Invalid:        ExitUsage,
+		Permission:     ExitNoPerm,
+		IO:             ExitIOErr,
+		Exist:          ExitCantCreate,
+		NotExist:       ExitNoInput,
+		Private:        ExitNoPerm,
+		Internal:       ExitSoftware,
+		BrokenLink:     ExitNoInput,
+		Database:       ExitUnavailable,
+		Validation:     ExitDataErr,
+		Unanticipated:  ExitSoftware,
+		InvalidRequest: ExitUsage,
+		Timeout:        ExitTempFail,
+		Canceled:       ExitInterrupted,
+		Unauthorized:   ExitNoPerm,
+	}
+)
+
+// RegisterExitCode sets the exit code returned by ExitCode for errors
+// of the given Kind. It overrides the default mapping for that Kind.
+func RegisterExitCode(k Kind, code int) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	kindExit[k] = code
+}
+
+// ExitCode returns the exit code of a command line program which fails
+// with err, as determined by its Kind (see KindOf and
+// RegisterExitCode). It returns 0 if err is nil, and 1 if its Kind has
+// no mapping.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	exitMu.RLock()
+	defer exitMu.RUnlock()
+	if code, ok := kindExit[KindOf(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// osExit and exitOutput are replaced in tests.
+var (
+	osExit               = os.Exit
+	exitOutput io.Writer = os.Stderr
+)
+
+// Exit ends a command line program which fails with err. It logs err
+// with its kind, code, op_chain and exit_code fields, prints its
+// message to standard error, prefixed with the name of the program,
+// and exits with the code of ExitCode. If err is nil, it exits with
+// code 0 and prints nothing. Deferred functions are not run.
+func Exit(err error) {
+	code := ExitCode(err)
+	if err != nil {
+		err = soundChain(err)
+		msg, _ := truncateMessage(maskMessage(err, err.Error()))
+		f := Fields{"exit_code": code}
+		if kind := KindOf(err); kind != Other {
+			addField(f, "kind", kind.String())
+		}
+		addField(f, "code", string(chainCode(err)))
+		addField(f, "op_chain", joinOps(Ops(err)))
+		logger.Log(ErrorLevel, msg, f)
+		fmt.Fprintf(exitOutput, "%s: %s\n", filepath.Base(os.Args[0]), msg)
+	}
+	osExit(code)
+}
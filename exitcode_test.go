@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"Unclassified", Str("boom"), 1},
+		{"NotExist", E(Op("cli.Open"), NotExist, Str("no such file")), ExitNoInput},
+		{"Permission", E(Op("cli.Open"), Permission, Str("access denied")), ExitNoPerm},
+		{"Validation", E(Op("cli.Parse"), Validation, Str("bad flag")), ExitDataErr},
+		{"Wrapped", E(Op("cli.Run"), E(Op("cli.Dial"), Timeout, Str("timed out"))), ExitTempFail},
+		{"HTTPErr", RE(Canceled, Str("interrupted")), ExitInterrupted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterExitCode(t *testing.T) {
+	defer RegisterExitCode(NotExist, ExitNoInput)
+	RegisterExitCode(NotExist, 2)
+	if got := ExitCode(E(NotExist, Str("no such file"))); got != 2 {
+		t.Errorf("ExitCode() = %d; want 2", got)
+	}
+}
+
+func TestExit(t *testing.T) {
+	defer func(exit func(int), w io.Writer) { osExit, exitOutput = exit, w }(osExit, exitOutput)
+	var code int
+	var out bytes.Buffer
+	osExit = func(c int) { code = c }
+	exitOutput = &out
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	Exit(E(Op("cli.Open"), NotExist, Code("NoFile"), Str("no such file")))
+	if code != ExitNoInput {
+		t.Errorf("exit code = %d; want %d", code, ExitNoInput)
+	}
+	if got := out.String(); !strings.HasSuffix(got, ": no such file\n") {
+		t.Errorf("output = %q; want the error message", got)
+	}
+	if len(tl.entries) != 1 {
+		t.Fatalf("%d log entries; want 1", len(tl.entries))
+	}
+	f := tl.entries[0].fields
+	if f["kind"] != NotExist.String() || f["code"] != "NoFile" || f["exit_code"] != ExitNoInput {
+		t.Errorf("fields = %v", f)
+	}
+
+	out.Reset()
+	Exit(nil)
+	if code != 0 || out.Len() != 0 {
+		t.Errorf("Exit(nil): code %d, output %q; want 0 and no output", code, out.String())
+	}
+}
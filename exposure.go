@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"strings"
+)
+
+// ClientSafeKinds lists the Kinds of errors whose message is safe to
+// send to the client. The message of an error of any other Kind may
+// contain internal details, such as SQL errors or file paths, so it
+// is only logged, and the client is sent the description of its Code
+// (see RegisterCode) instead, or the status text of its Kind if its
+// Code was not registered. For example, to only send the messages of
+// validation errors:
+//
+//	errors.ClientSafeKinds = []errors.Kind{errors.Validation, errors.InvalidRequest}
+//
+// By default, ClientSafeKinds is nil and the message of every error
+// is sent. The message of an error of a safe Kind is still masked as
+// determined by the Redaction policy.
+var ClientSafeKinds []Kind
+
+// clientSafe reports whether the message of an error with the given
+// Kind name can be sent to the client.
+func clientSafe(kind string) bool {
+	if ClientSafeKinds == nil {
+		return true
+	}
+	for _, k := range ClientSafeKinds {
+		if k.String() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// safeMessage returns the message sent instead of the message of se
+// when its Kind is not client-safe.
+func safeMessage(se *ServiceError) string {
+	if desc := codeDescription(Code(se.Code)); desc != "" {
+		return desc
+	}
+	return ReasonPhrase(KindStatus(kindFromString(se.Kind)))
+}
+
+// exposeMessages replaces the messages of se and of the errors it
+// lists whose Kind is not client-safe. If joined is true, the message
+// of se joins the messages of its errors, as for a ValidationErrors,
+// and is rebuilt from the messages which are sent.
+func exposeMessages(se *ServiceError, joined bool) {
+	if ClientSafeKinds == nil {
+		return
+	}
+	msgs := make([]string, len(se.Errors))
+	for i := range se.Errors {
+		exposeMessages(&se.Errors[i], false)
+		msgs[i] = se.Errors[i].Message
+	}
+	switch {
+	case !clientSafe(se.Kind):
+		se.Message = safeMessage(se)
+	case joined:
+		se.Message = strings.Join(msgs, "; ")
+	}
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClientSafeKinds(t *testing.T) {
+	defer func() {
+		ClientSafeKinds = nil
+		codeMu.Lock()
+		codes = map[Code]string{}
+		codeMu.Unlock()
+	}()
+	ClientSafeKinds = []Kind{Validation}
+	RegisterCode("db_unavailable", "The database is unavailable.")
+
+	tests := []struct {
+		name string
+		err  error
+		want ServiceError
+	}{
+		{
+			"Safe",
+			RE(Validation, Parameter("name"), Str("name is required")),
+			ServiceError{Kind: Validation.String(), Param: "name", Message: "name is required"},
+		},
+		{
+			"Registered Code",
+			RE(Database, Code("db_unavailable"), Str("dial tcp 10.0.0.1:5432: connection refused")),
+			ServiceError{Kind: Database.String(), Code: "db_unavailable", Message: "The database is unavailable."},
+		},
+		{
+			"Unregistered Code",
+			RE(NotExist, Code("no_user"), Str("open /var/lib/users/42: no such file")),
+			ServiceError{Kind: NotExist.String(), Code: "no_user", Message: http.StatusText(http.StatusNotFound)},
+		},
+		{
+			"Validation errors",
+			ValidationErrors{
+				RE(Validation, Parameter("name"), Str("name is required")),
+				RE(Database, Str("select * from users")),
+			},
+			ServiceError{
+				Kind:    Validation.String(),
+				Message: "name is required; Internal Server Error",
+				Errors: []ServiceError{
+					{Kind: Validation.String(), Param: "name", Message: "name is required"},
+					{Kind: Database.String(), Message: "Internal Server Error"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, se := serviceError(tt.err)
+			if !reflect.DeepEqual(*se, tt.want) {
+				t.Errorf("serviceError() = %+v; want %+v", *se, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFallbackResponse(t *testing.T) {
+	defer func() {
+		FallbackResponse = DefaultFallbackResponse
+	}()
+	errTimeout := Str("upstream timed out")
+	FallbackResponse = func(err error) (int, ServiceError) {
+		if stderrors.Is(err, errTimeout) {
+			return http.StatusGatewayTimeout, ServiceError{Kind: IO.String(), Code: "upstream_timeout", Message: err.Error()}
+		}
+		return DefaultFallbackResponse(err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		want     ServiceError
+	}{
+		{"Custom", E(Op("service.Get"), errTimeout), http.StatusGatewayTimeout,
+			ServiceError{Kind: IO.String(), Code: "upstream_timeout"}},
+		{"Default", Str("some error"), http.StatusInternalServerError,
+			ServiceError{Kind: Unanticipated.String(), Code: "Unanticipated", Message: "Unexpected error - contact support"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantCode)
+			}
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Kind != tt.want.Kind || er.Error.Code != tt.want.Code {
+				t.Errorf("body = %+v; want %+v", er.Error, tt.want)
+			}
+			if tt.want.Message != "" && er.Error.Message != tt.want.Message {
+				t.Errorf("Message = %q; want %q", er.Error.Message, tt.want.Message)
+			}
+		})
+	}
+}
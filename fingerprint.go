@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+)
+
+// FingerprintFrames is the number of innermost frames of the stack
+// trace of an error which Fingerprint hashes, by function name, in
+// addition to its op chain, Kind and Code. It is 0 by default, so
+// errors with the same classification raised from different places
+// share a fingerprint unless they have different ops.
+var FingerprintFrames = 0
+
+// FingerprintHeader is the header of the error responses which holds
+// the Fingerprint of the error, so clients can quote it in reports.
+// Set it to "" to not send fingerprints.
+var FingerprintHeader = "X-Error-Fingerprint"
+
+// Fingerprint returns a stable identifier of the class of err, so
+// alerting and error tracking tools can group its occurrences. It is
+// a hash of the op chain (see Ops), the Kind and the Code of err (see
+// KindOf), and of the top FingerprintFrames frames of its stack
+// trace. It does not depend on the message of err, which often holds
+// values such as IDs. HTTPError logs it with the error, and sends it
+// in the FingerprintHeader of the response. If err is nil, Fingerprint
+// returns "".
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := sha256.New()
+	io.WriteString(h, joinOps(Ops(err)))
+	io.WriteString(h, "\x00"+KindOf(err).String())
+	io.WriteString(h, "\x00"+string(chainCode(err)))
+	if FingerprintFrames > 0 {
+		for i, f := range stackOf(err).Frames() {
+			if i == FingerprintFrames {
+				break
+			}
+			io.WriteString(h, "\x00"+f.Function)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// setFingerprint sets the FingerprintHeader of the response to the
+// Fingerprint of err, unless FingerprintHeader is empty.
+func setFingerprint(w http.ResponseWriter, err error) {
+	if FingerprintHeader != "" {
+		w.Header().Set(FingerprintHeader, Fingerprint(err))
+	}
+}
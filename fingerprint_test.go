@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func notFoundUser(id string) error {
+	return E(Op("users.Get"), NotExist, Code("no_user"), Str("no user "+id))
+}
+
+func notFoundGroup(id string) error {
+	return E(Op("users.Get"), NotExist, Code("no_user"), Str("no group "+id))
+}
+
+func TestFingerprint(t *testing.T) {
+	defer func() { FingerprintFrames = 0 }()
+
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil) = %q; want \"\"", got)
+	}
+
+	tests := []struct {
+		name   string
+		a, b   error
+		frames int
+		same   bool
+	}{
+		{"Message", notFoundUser("1"), notFoundUser("2"), 0, true},
+		{"Code", notFoundUser("1"), E(Op("users.Get"), NotExist, Code("no_group")), 0, false},
+		{"Kind", RE(NotExist, Code("x")), RE(Exist, Code("x")), 0, false},
+		{"Op", E(Op("users.Get"), Code("x")), E(Op("users.Put"), Code("x")), 0, false},
+		{"Caller without frames", notFoundUser("1"), notFoundGroup("1"), 0, true},
+		{"Caller with frames", notFoundUser("1"), notFoundGroup("1"), 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			FingerprintFrames = tt.frames
+			a, b := Fingerprint(tt.a), Fingerprint(tt.b)
+			if len(a) != 16 {
+				t.Errorf("Fingerprint() = %q; want 16 hex digits", a)
+			}
+			if (a == b) != tt.same {
+				t.Errorf("Fingerprint() = %q and %q; want same = %t", a, b, tt.same)
+			}
+		})
+	}
+}
+
+func TestFingerprintHeader(t *testing.T) {
+	defer func() { FingerprintHeader = "X-Error-Fingerprint" }()
+	err := RE(http.StatusNotFound, NotExist, Code("no_user"))
+
+	rr := httptest.NewRecorder()
+	HTTPError(rr, err)
+	if got := rr.Header().Get("X-Error-Fingerprint"); got != Fingerprint(err) {
+		t.Errorf("X-Error-Fingerprint = %q; want %q", got, Fingerprint(err))
+	}
+
+	FingerprintHeader = ""
+	rr = httptest.NewRecorder()
+	HTTPError(rr, err)
+	if got := rr.Header().Get("X-Error-Fingerprint"); got != "" {
+		t.Errorf("X-Error-Fingerprint = %q; want none", got)
+	}
+}
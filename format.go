@@ -0,0 +1,189 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences of the colors used by FormatColor.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+	ansiDim     = "\x1b[2m"
+)
+
+// Format renders err as an indented tree, for command line programs
+// and test output. Each nested error is on its own line, indented one
+// level deeper than the error which wraps it: the errors of this
+// package with their op chain, Kind, Code and Parameter, and other
+// errors with their message. The innermost stack trace recorded for
+// err, if any, follows. For example:
+//
+//	users.Create: database_error code=db_down
+//	  store.Insert: I/O_error
+//	    connection refused
+//	stack:
+//	  main.createUser
+//	      /app/users.go:42
+//
+// Unlike the debug responses of HTTPError, nothing is redacted. If err
+// is nil, Format returns "".
+func Format(err error) string {
+	return format(err, false)
+}
+
+// FormatColor is like Format, but highlights the parts of the tree
+// with ANSI colors, for terminals.
+func FormatColor(err error) string {
+	return format(err, true)
+}
+
+// Format implements fmt.Formatter. The %s and %v verbs print the
+// message of e, %q prints it quoted, and %+v prints the tree of e
+// with its stack trace, as Format does.
+func (e *Error) Format(s fmt.State, verb rune) {
+	formatVerb(s, verb, e)
+}
+
+// Format implements fmt.Formatter, as (*Error).Format does.
+func (hse *HTTPErr) Format(s fmt.State, verb rune) {
+	formatVerb(s, verb, hse)
+}
+
+// formatVerb prints err for the verb of a fmt.Formatter.
+func formatVerb(s fmt.State, verb rune, err error) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, Format(err))
+			return
+		}
+		io.WriteString(s, err.Error())
+	case 's':
+		io.WriteString(s, err.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, err, err.Error())
+	}
+}
+
+// treeFormatter writes the tree of an error.
+type treeFormatter struct {
+	b     strings.Builder
+	color bool
+}
+
+func format(err error, color bool) string {
+	if err == nil || nilHTTPErr(err) {
+		return ""
+	}
+	f := &treeFormatter{color: color}
+	f.node(err, 0, nil)
+	if st := stackOf(err); len(st) > 0 {
+		f.b.WriteString(f.paint(ansiDim, "stack:"))
+		f.b.WriteString("\n")
+		for _, fr := range st.Frames() {
+			f.b.WriteString("  " + f.paint(ansiDim, fr.Function) + "\n")
+			f.b.WriteString("      " + f.paint(ansiDim, fr.File+":"+strconv.Itoa(fr.Line)) + "\n")
+		}
+	}
+	return strings.TrimSuffix(f.b.String(), "\n")
+}
+
+// node writes err at the given depth, then the errors it wraps. The
+// path holds the errors which wrap err; if err is one of them, or if
+// the path is MaxChainDepth errors long, node writes why the chain is
+// broken instead.
+func (f *treeFormatter) node(err error, depth int, path []error) {
+	var next []error
+	for err != nil && !nilHTTPErr(err) {
+		var reason error
+		switch {
+		case len(path) >= MaxChainDepth:
+			reason = ErrChainTooDeep
+		case inPath(err, path):
+			reason = ErrChainCycle
+		}
+		if reason != nil {
+			f.b.WriteString(strings.Repeat("  ", depth) + f.paint(ansiRed, "("+reason.Error()+")") + "\n")
+			return
+		}
+		path = append(path, err)
+		line, skip := f.line(err)
+		if !skip {
+			f.b.WriteString(strings.Repeat("  ", depth) + line + "\n")
+			depth++
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			next = u.Unwrap()
+			err = nil
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			err = nil
+		}
+	}
+	for _, e := range next {
+		f.node(e, depth, path[:len(path):len(path)])
+	}
+}
+
+// line returns the line describing err, without the errors it wraps,
+// or skip if err only changes the message of the error it wraps.
+func (f *treeFormatter) line(err error) (line string, skip bool) {
+	var (
+		ops   []Op
+		kind  Kind
+		code  Code
+		param Parameter
+	)
+	switch e := err.(type) {
+	case *strippedError:
+		return "", true
+	case *Error:
+		ops, kind, code, param = e.opChain(), e.Kind, e.Code, e.Param
+	case *HTTPErr:
+		ops, kind, code, param = e.ops, e.Kind, e.Code, e.Param
+		line = f.paint(ansiDim, "HTTP "+strconv.Itoa(e.Status())) + " "
+	case *annotation:
+		return f.paint(ansiRed, e.msg), false
+	case *BatchError, ValidationErrors:
+		return f.paint(ansiRed, fmt.Sprintf("%T: %v", err, err)), false
+	default:
+		return f.paint(ansiRed, err.Error()), false
+	}
+	var parts []string
+	if kind != Other {
+		parts = append(parts, f.paint(ansiYellow, kind.String()))
+	}
+	if code != "" {
+		parts = append(parts, f.paint(ansiMagenta, "code="+string(code)))
+	}
+	if param != "" {
+		parts = append(parts, f.paint(ansiMagenta, "param="+string(param)))
+	}
+	switch {
+	case len(ops) > 0 && len(parts) > 0:
+		line += f.paint(ansiCyan, joinOps(ops)) + ": "
+	case len(ops) > 0:
+		line += f.paint(ansiCyan, joinOps(ops))
+	case len(parts) == 0:
+		line += fmt.Sprintf("%T", err)
+	}
+	return line + strings.Join(parts, " "), false
+}
+
+// paint returns s in the given color, if colors are enabled.
+func (f *treeFormatter) paint(color, s string) string {
+	if !f.color {
+		return s
+	}
+	return color + s + ansiReset
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	defer func() { CaptureStack = true }()
+	CaptureStack = false
+
+	be := NewBatchError(2)
+	be.Add(0, NotFound("users.Create", "no group 7"))
+	be.Add(1, io.EOF)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"Error", E(Op("users.Create"), Code("db_down"), E(Op("store.Insert"), IO, io.ErrUnexpectedEOF)),
+			"users.Create: I/O_error code=db_down\n  store.Insert\n    unexpected EOF"},
+		{"HTTPErr", RE(http.StatusBadRequest, Validation, Parameter("id"), Str("id is not a number")),
+			"HTTP 400 input_validation_error param=id\n  id is not a number"},
+		{"Wrap", Wrap(io.EOF, "files.Read", "reading config"),
+			"files.Read\n  reading config\n    EOF"},
+		{"BatchError", be.Err(),
+			"*errors.BatchError: 2 of 2 items failed\n  HTTP 404 item_does_not_exist\n    users.Create: item_does_not_exist\n      no group 7\n  EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.err); got != tt.want {
+				t.Errorf("Format() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatColor(t *testing.T) {
+	got := FormatColor(E(Op("users.Get"), NotExist, Str("no user")))
+	for _, want := range []string{ansiCyan + "users.Get" + ansiReset, ansiYellow + "item_does_not_exist" + ansiReset, ansiRed + "no user" + ansiReset, "stack:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatColor() = %q; want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	defer func() { CaptureStack = true }()
+	CaptureStack = false
+
+	// The message of an Error may have its stack trace printed in
+	// debug builds, so the concise verbs are tested with an HTTPErr
+	e := E(Op("users.Get"), NotExist, Code("no_user"), Str("no user"))
+	hse := RE(http.StatusNotFound, NotExist, Str("no user"))
+	tests := []struct {
+		name   string
+		format string
+		err    error
+		want   string
+	}{
+		{"%v", "%v", hse, "no user"},
+		{"%s", "%s", hse, "no user"},
+		{"%q", "%q", hse, `"no user"`},
+		{"HTTPErr %+v", "%+v", hse, "HTTP 404 item_does_not_exist\n  no user"},
+		{"Error %+v", "%+v", e, "users.Get: item_does_not_exist code=no_user\n  no user"},
+		{"Wrapped %+v", "wrapped: %+v", fmt.Errorf("users: %w", hse), "wrapped: users: no user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+				t.Errorf("Sprintf(%q) =\n%s\nwant\n%s", tt.format, got, tt.want)
+			}
+		})
+	}
+}
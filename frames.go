@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Realtime endpoints, such as WebSocket and Server-Sent Events (SSE)
+// streams, and streamed responses cannot send an error response once
+// the connection is established or the response started. SSEError,
+// WebSocketClose and StreamError report errors on these connections
+// with the same Kind and Code structure as HTTPError.
+
+// ErrorFrame is the payload of the SSE error events sent by SSEError,
+// and the error record sent by StreamError.
+// As there is no HTTP response, the HTTP status code of the error is
+// part of the payload.
+type ErrorFrame struct {
+	Status int          `json:"status"`
+	Error  ServiceError `json:"error"`
+}
+
+// SSEError logs err and writes it to w as a Server-Sent Event of type
+// "error", whose data is the JSON encoding of an ErrorFrame, e.g.
+//
+//	event: error
+//	data: {"status":404,"error":{"kind":"item_does_not_exist","code":"no_user","message":"user not found"}}
+//
+// The ErrorFrame is built as the response body of HTTPErrorCtx, with
+// the request ID, language and debug setting of ctx. If w is an
+// http.Flusher, the event is flushed to the client. SSEError returns
+// the error of writing to w, if any.
+func SSEError(ctx context.Context, w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	err = soundChain(err)
+	ctx = withErrorID(ctx)
+	logHTTPError(ctx, err, requestFields(RequestIDFunc(ctx)))
+	status, se := errorResponse(ctx, err)
+	frame := ErrorFrame{Status: status}
+	if se != nil {
+		frame.Error = *se
+	} else {
+		frame.Error.Message = ReasonPhrase(status)
+	}
+	// SSE data cannot span lines unless each line is prefixed,
+	// so the payload is always compact
+	data, merr := json.Marshal(frame)
+	if merr != nil {
+		return merr
+	}
+	if _, werr := fmt.Fprintf(w, "event: error\ndata: %s\n\n", data); werr != nil {
+		return werr
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// StreamError reports err, which ended a streamed response, such as a
+// chunked or NDJSON (newline delimited JSON) response, before it was
+// complete, instead of silently truncating it. See StreamErrorCtx.
+func StreamError(w http.ResponseWriter, err error) error {
+	return StreamErrorCtx(context.Background(), w, err)
+}
+
+// StreamErrorCtx logs err and writes it to w as a final record of the
+// stream: the compact JSON encoding of an ErrorFrame on its own line,
+// e.g.
+//
+//	{"status":500,"error":{"kind":"unanticipated_error","code":"Unanticipated","message":"Unexpected error - contact support"}}
+//
+// The ErrorFrame is built as the response body of HTTPErrorCtx, with
+// the request ID, language and debug setting of ctx. The status, Kind
+// and Code of err are also set in the X-Error-Status, X-Error-Kind and
+// X-Error-Code trailers of the response, for clients which read them.
+// If w is an http.Flusher, the record is flushed to the client.
+// StreamErrorCtx returns the error of writing to w, if any.
+func StreamErrorCtx(ctx context.Context, w http.ResponseWriter, err error) error {
+	if err == nil {
+		return nil
+	}
+	err = soundChain(err)
+	ctx = withErrorID(ctx)
+	logHTTPError(ctx, err, requestFields(RequestIDFunc(ctx)))
+	status, se := errorResponse(ctx, err)
+	kind, code := classify(err)
+	setErrorTrailers(w, status, kind, code)
+	frame := ErrorFrame{Status: status}
+	if se != nil {
+		frame.Error = *se
+	} else {
+		frame.Error.Message = ReasonPhrase(status)
+	}
+	// Each record of the stream is on a single line
+	data, merr := json.Marshal(frame)
+	if merr != nil {
+		return merr
+	}
+	if _, werr := fmt.Fprintf(w, "%s\n", data); werr != nil {
+		return werr
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// maxCloseReason is the maximum length in bytes of the reason of a
+// WebSocket close frame (RFC 6455, section 5.5).
+const maxCloseReason = 123
+
+// closeReason is the reason of the close frames of WebSocketClose.
+type closeReason struct {
+	Kind string `json:"kind,omitempty"`
+	Code string `json:"code,omitempty"`
+}
+
+// WebSocketClose logs err and returns the status code and the reason
+// with which to close a WebSocket connection because of err, e.g. with
+// the WriteControl method of a gorilla/websocket Conn:
+//
+//	code, reason := errors.WebSocketClose(ctx, err)
+//	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+//
+// The status code is 4000 plus the HTTP status code of the error, e.g.
+// 4404, in the range of codes reserved for applications. The reason is
+// the JSON encoding of the Kind and Code of the error, e.g.
+// {"kind":"item_does_not_exist","code":"no_user"}. The message is not
+// included, as the reason is limited to 123 bytes. If the Kind and Code
+// do not fit, the Code is dropped, then the reason is left empty.
+func WebSocketClose(ctx context.Context, err error) (code int, reason string) {
+	if err == nil {
+		return 1000, "" // Normal closure
+	}
+	err = soundChain(err)
+	ctx = withErrorID(ctx)
+	logHTTPError(ctx, err, requestFields(RequestIDFunc(ctx)))
+	status, se := errorResponse(ctx, err)
+	code = 4000 + status
+	if se == nil {
+		return code, ""
+	}
+	for _, cr := range []closeReason{{se.Kind, se.Code}, {Kind: se.Kind}} {
+		if b, merr := json.Marshal(cr); merr == nil && len(b) <= maxCloseReason && cr != (closeReason{}) {
+			return code, string(b)
+		}
+	}
+	return code, ""
+}
@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSEError(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"HTTPErr", RE(http.StatusNotFound, NotExist, Code("no_user"), Str("user not found")),
+			"event: error\ndata: {\"status\":404,\"error\":{\"kind\":\"item_does_not_exist\",\"code\":\"no_user\",\"message\":\"user not found\",\"request_id\":\"req-1\"}}\n\n"},
+		{"Status Only", RE(http.StatusUnauthorized),
+			"event: error\ndata: {\"status\":401,\"error\":{\"message\":\"Unauthorized\"}}\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			if err := SSEError(WithRequestID(context.Background(), "req-1"), rr, tt.err); err != nil {
+				t.Fatalf("SSEError() error = %v", err)
+			}
+			if got := rr.Body.String(); got != tt.want {
+				t.Errorf("frame = %q; want %q", got, tt.want)
+			}
+			if !rr.Flushed {
+				t.Error("frame was not flushed")
+			}
+		})
+	}
+	if len(tl.entries) != len(tests) {
+		t.Errorf("got %d log entries; want %d", len(tl.entries), len(tests))
+	}
+}
+
+func TestWebSocketClose(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int
+		wantReason string
+	}{
+		{"nil", nil, 1000, ""},
+		{"HTTPErr", RE(http.StatusNotFound, NotExist, Code("no_user"), Str("user not found")),
+			4404, `{"kind":"item_does_not_exist","code":"no_user"}`},
+		{"Long Code", RE(http.StatusConflict, Exist, Code(strings.Repeat("x", 120))),
+			4409, `{"kind":"item_already_exists"}`},
+		{"Error", E(Op("ws.Read"), Str("connection reset")),
+			4500, `{"kind":"unanticipated_error","code":"Unanticipated"}`},
+		{"Status Only", RE(http.StatusUnauthorized), 4401, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason := WebSocketClose(context.Background(), tt.err)
+			if code != tt.wantCode || reason != tt.wantReason {
+				t.Errorf("WebSocketClose() = %d, %q; want %d, %q", code, reason, tt.wantCode, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	defer SetLogger(nil)
+	SetLogger(&testLogger{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		io.WriteString(w, `{"id":1}`+"\n")
+		w.(http.Flusher).Flush()
+		err := RE(http.StatusServiceUnavailable, IO, Code("db_down"), Str("database unavailable"))
+		if serr := StreamError(w, err); serr != nil {
+			t.Errorf("StreamError() error = %v", serr)
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("http.Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+
+	want := `{"id":1}` + "\n" +
+		`{"status":503,"error":{"kind":"I/O_error","code":"db_down","message":"database unavailable"}}` + "\n"
+	if string(body) != want {
+		t.Errorf("body = %q; want %q", body, want)
+	}
+	// Trailers are only known once the body is read
+	for k, v := range map[string]string{"X-Error-Status": "503", "X-Error-Kind": "I/O_error", "X-Error-Code": "db_down"} {
+		if got := resp.Trailer.Get(k); got != v {
+			t.Errorf("trailer %s = %q; want %q", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package ginerrors sends errors built with the errors package as
+// error responses from Gin handlers, in the same format as
+// errors.HTTPError.
+package ginerrors
+
+import (
+	"github.com/gilcrest/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Error sends err to the client as errors.HTTPErrorCtx does, using the
+// context of the request, and aborts the remaining handlers of c. If
+// err is nil, Error does nothing.
+func Error(c *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	c.Abort()
+	errors.HTTPErrorCtx(errors.RequestContext(c.Request), c.Writer, err)
+}
+
+// Middleware returns a gin.HandlerFunc which, once the remaining
+// handlers have run, sends the last error added to the context with
+// c.Error, unless a response was already written. It lets handlers
+// report errors with c.Error instead of calling Error.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Written() {
+			return
+		}
+		if ge := c.Errors.Last(); ge != nil {
+			Error(c, ge.Err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ginerrors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/error", func(c *gin.Context) {
+		Error(c, errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("user_not_found"), errors.Str("no such user")))
+	})
+	r.GET("/c.Error", func(c *gin.Context) {
+		_ = c.Error(errors.RE(http.StatusConflict, errors.Exist, errors.Str("user exists")))
+	})
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantKind string
+	}{
+		{"/error", http.StatusNotFound, errors.NotExist.String()},
+		{"/c.Error", http.StatusConflict, errors.Exist.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantCode)
+			}
+			var er errors.ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Kind != tt.wantKind {
+				t.Errorf("Kind = %q; want %q", er.Error.Kind, tt.wantKind)
+			}
+		})
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/gilcrest/errors
 
+go 1.21
+
 require github.com/rs/zerolog v1.14.0
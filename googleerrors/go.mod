@@ -0,0 +1,12 @@
+module github.com/gilcrest/errors/googleerrors
+
+go 1.21
+
+require (
+	github.com/gilcrest/errors v0.0.0
+	google.golang.org/api v0.170.0
+)
+
+require github.com/rs/zerolog v1.14.0 // indirect
+
+replace github.com/gilcrest/errors => ../
@@ -0,0 +1,77 @@
+// Package googleerrors translates the errors of the Google API client
+// libraries (google.golang.org/api) into errors of the errors package,
+// so the outages of Google APIs are sent as an HTTP 503 rather than as
+// unanticipated errors:
+//
+//	errors.RegisterTranslator(googleerrors.Translate)
+//
+// The errors of the gRPC-based Cloud client libraries are gRPC status
+// errors, which are not translated.
+package googleerrors
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gilcrest/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// The messages sent to the client for translated errors. The message
+// of the API error may name projects or resources, so it is only
+// logged, as the error wrapped by the translated error.
+const (
+	unavailableMsg = "Service temporarily unavailable - try again later"
+	notFoundMsg    = "Not found"
+)
+
+// unavailableReasons are the reasons of the Google API errors for
+// requests which failed or were throttled by the API.
+var unavailableReasons = map[string]bool{
+	"backendError":          true,
+	"internalError":         true,
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+}
+
+// FromGoogleAPIErr translates err, an error returned by a Google API
+// client, as Translate does. Other errors are returned unchanged.
+func FromGoogleAPIErr(err error) error {
+	if te := Translate(err); te != nil {
+		return te
+	}
+	return err
+}
+
+// Translate is an errors.TranslateFunc for the errors of the Google
+// API client libraries. 5xx and 429 responses, and errors with the
+// reason backendError, internalError, rateLimitExceeded or
+// userRateLimitExceeded, are translated into retryable errors of Kind
+// IO with Code Unavailable, sent as an HTTP 503, and 404 responses
+// into errors of Kind NotExist with Code NotFound, sent as an HTTP
+// 404. The client is sent a generic message, and the API error is only
+// logged. Other errors are not translated, so Translate returns nil.
+func Translate(err error) error {
+	var ge *googleapi.Error
+	if !stderrors.As(err, &ge) {
+		return nil
+	}
+	switch {
+	case ge.Code >= http.StatusInternalServerError, ge.Code == http.StatusTooManyRequests, unavailable(ge):
+		return errors.RE(http.StatusServiceUnavailable, errors.IO, errors.Code("Unavailable"), errors.Retry(0), errors.UserMsg(unavailableMsg), err)
+	case ge.Code == http.StatusNotFound:
+		return errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("NotFound"), errors.UserMsg(notFoundMsg), err)
+	}
+	return nil
+}
+
+// unavailable reports whether an item of ge has one of the
+// unavailableReasons.
+func unavailable(ge *googleapi.Error) bool {
+	for _, item := range ge.Errors {
+		if unavailableReasons[item.Reason] {
+			return true
+		}
+	}
+	return false
+}
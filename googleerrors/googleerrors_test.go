@@ -0,0 +1,61 @@
+package googleerrors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"google.golang.org/api/googleapi"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantKind   errors.Kind
+		wantCode   errors.Code
+	}{
+		{"Server error", &googleapi.Error{Code: http.StatusBadGateway}, http.StatusServiceUnavailable, errors.IO, "Unavailable"},
+		{"Too many requests", &googleapi.Error{Code: http.StatusTooManyRequests}, http.StatusServiceUnavailable, errors.IO, "Unavailable"},
+		{
+			"Rate limit reason",
+			&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}},
+			http.StatusServiceUnavailable, errors.IO, "Unavailable",
+		},
+		{"Not found", &googleapi.Error{Code: http.StatusNotFound}, http.StatusNotFound, errors.NotExist, "NotFound"},
+		{"Wrapped", fmt.Errorf("get bucket: %w", &googleapi.Error{Code: http.StatusServiceUnavailable}), http.StatusServiceUnavailable, errors.IO, "Unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := Translate(tt.err).(*errors.HTTPErr)
+			if !ok {
+				t.Fatalf("Translate() = %v; want an *errors.HTTPErr", Translate(tt.err))
+			}
+			if e.Status() != tt.wantStatus || e.Kind != tt.wantKind || e.Code != tt.wantCode {
+				t.Errorf("Translate() = %d %v %q; want %d %v %q", e.Status(), e.Kind, e.Code, tt.wantStatus, tt.wantKind, tt.wantCode)
+			}
+			if e.Unwrap() != tt.err {
+				t.Errorf("Unwrap() = %v; want the Google API error", e.Unwrap())
+			}
+			rr := httptest.NewRecorder()
+			errors.HTTPError(rr, e)
+			if strings.Contains(rr.Body.String(), tt.err.Error()) {
+				t.Errorf("body = %s; want no message of the Google API error", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestFromGoogleAPIErr(t *testing.T) {
+	err := &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "invalid"}}}
+	if got := FromGoogleAPIErr(err); got != error(err) {
+		t.Errorf("FromGoogleAPIErr() = %v; want the error unchanged", got)
+	}
+	if got := errors.StatusOf(FromGoogleAPIErr(&googleapi.Error{Code: http.StatusInternalServerError})); got != http.StatusServiceUnavailable {
+		t.Errorf("StatusOf(FromGoogleAPIErr()) = %d; want %d", got, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/gilcrest/errors/gqlerrors
+
+go 1.21
+
+require (
+	github.com/99designs/gqlgen v0.17.40
+	github.com/gilcrest/errors v0.0.0
+	github.com/vektah/gqlparser/v2 v2.5.10
+)
+
+require (
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/rs/zerolog v1.14.0 // indirect
+	github.com/sosodev/duration v1.1.0 // indirect
+)
+
+replace github.com/gilcrest/errors => ../
@@ -0,0 +1,78 @@
+// Package gqlerrors converts errors built with the errors package into
+// GraphQL errors for gqlgen servers, so services that serve GraphQL
+// alongside REST classify errors the same way on either API.
+package gqlerrors
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gilcrest/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// GraphQLError converts err into a GraphQL error at the path of the
+// field being resolved in ctx, if any. The message is the one
+// errors.HTTPError would send, and the Kind, Code and Param of the
+// error, if any, and the HTTP status code errors.HTTPError would send,
+// are added to the extensions under the kind, code, param and status
+// keys:
+//
+//	{"message": "user not found", "path": ["user"], "extensions": {"kind": "item_does_not_exist", "code": "NotFound", "status": 404}}
+//
+// The returned error wraps err. The errors of gqlgen itself, such as
+// the errors of parsing and validating the query, are returned as is.
+// If err is nil, GraphQLError returns nil.
+func GraphQLError(ctx context.Context, err error) *gqlerror.Error {
+	if err == nil {
+		return nil
+	}
+	ge := graphql.DefaultErrorPresenter(ctx, err)
+	if ge.Err == nil {
+		// Built by gqlgen or the resolver
+		return ge
+	}
+
+	pr := errors.NewProblemResponse(ge.Err)
+	c := *ge
+	c.Message = pr.Detail
+	if c.Message == "" {
+		c.Message = pr.Title
+	}
+	c.Extensions = make(map[string]interface{}, len(ge.Extensions)+4)
+	for k, v := range ge.Extensions {
+		c.Extensions[k] = v
+	}
+	if pr.Kind != "" {
+		c.Extensions["kind"] = pr.Kind
+	}
+	if pr.Code != "" {
+		c.Extensions["code"] = pr.Code
+	}
+	if pr.Param != "" {
+		c.Extensions["param"] = pr.Param
+	}
+	c.Extensions["status"] = pr.Status
+	return &c
+}
+
+// ErrorPresenter is a graphql.ErrorPresenterFunc which logs and counts
+// each error returned by a resolver with errors.ReportError, with the
+// path of its field in the graphql_path field, as errors.HTTPError
+// does for HTTP handlers, and converts it with GraphQLError:
+//
+//	srv := handler.NewDefaultServer(generated.NewExecutableSchema(cfg))
+//	srv.SetErrorPresenter(gqlerrors.ErrorPresenter)
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	ge := GraphQLError(ctx, err)
+	if ge != nil && ge.Err != nil {
+		f := errors.Fields{}
+		if len(ge.Path) > 0 {
+			f["graphql_path"] = ge.Path.String()
+		}
+		errors.ReportError(ctx, ge.Err, f)
+	}
+	return ge
+}
+
+var _ graphql.ErrorPresenterFunc = ErrorPresenter
@@ -0,0 +1,83 @@
+package gqlerrors
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gilcrest/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestGraphQLError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+		wantExt map[string]interface{}
+	}{
+		{"Kind", errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("NotFound"), errors.Str("user not found")),
+			"user not found", map[string]interface{}{"kind": "item_does_not_exist", "code": "NotFound", "status": 404}},
+		{"Param", errors.RE(errors.Validation, errors.Parameter("name"), errors.Str("name is required")),
+			"name is required", map[string]interface{}{"kind": "input_validation_error", "param": "name", "status": 400}},
+		{"Unknown", errors.Str("pq: connection refused"),
+			"Unexpected error - contact support", map[string]interface{}{"kind": "unanticipated_error", "code": "Unanticipated", "status": 500}},
+	}
+	ctx := graphql.WithPathContext(context.Background(), graphql.NewPathWithField("user"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ge := GraphQLError(ctx, tt.err)
+			if ge.Message != tt.wantMsg {
+				t.Errorf("Message = %q; want %q", ge.Message, tt.wantMsg)
+			}
+			if !reflect.DeepEqual(ge.Extensions, tt.wantExt) {
+				t.Errorf("Extensions = %v; want %v", ge.Extensions, tt.wantExt)
+			}
+			if want := (ast.Path{ast.PathName("user")}); !reflect.DeepEqual(ge.Path, want) {
+				t.Errorf("Path = %v; want %v", ge.Path, want)
+			}
+			if !stderrors.Is(ge, tt.err) {
+				t.Error("GraphQLError() does not wrap err")
+			}
+		})
+	}
+
+	if ge := GraphQLError(ctx, nil); ge != nil {
+		t.Errorf("GraphQLError(nil) = %v; want nil", ge)
+	}
+	// Errors of gqlgen are returned as is
+	parseErr := gqlerror.Errorf("syntax error")
+	if ge := GraphQLError(ctx, parseErr); ge != parseErr {
+		t.Errorf("GraphQLError(gqlerror) = %v; want it unchanged", ge)
+	}
+}
+
+// testLogger records the fields of the entries logged.
+type testLogger struct {
+	fields []errors.Fields
+}
+
+func (l *testLogger) Log(_ errors.Level, _ string, f errors.Fields) {
+	l.fields = append(l.fields, f)
+}
+
+func TestErrorPresenter(t *testing.T) {
+	defer errors.SetLogger(nil)
+	tl := &testLogger{}
+	errors.SetLogger(tl)
+
+	ctx := graphql.WithPathContext(context.Background(), graphql.NewPathWithField("user"))
+	// gqlgen wraps the errors of resolvers with their path
+	err := graphql.ErrorOnPath(ctx, errors.RE(http.StatusNotFound, errors.NotExist, errors.Str("user not found")))
+	ge := ErrorPresenter(ctx, err)
+	if ge.Message != "user not found" || ge.Extensions["kind"] != "item_does_not_exist" {
+		t.Errorf("ErrorPresenter() = %+v", ge)
+	}
+	if len(tl.fields) != 1 || tl.fields[0]["graphql_path"] != "user" {
+		t.Errorf("logged %v; want one entry with graphql_path user", tl.fields)
+	}
+}
@@ -0,0 +1,145 @@
+package errors
+
+import (
+	stderrors "errors"
+	"strings"
+	"sync"
+)
+
+// Group runs functions concurrently, like errgroup.Group, but collects
+// the errors of all of them rather than only the first. It is safe for
+// concurrent use. The zero value is ready to use. For example:
+//
+//	var g errors.Group
+//	for _, id := range ids {
+//		id := id
+//		g.Go(func() error { return fetch(ctx, id) })
+//	}
+//	if err := g.Wait(); err != nil {
+//		errors.HTTPError(w, err)
+//	}
+type Group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go calls f in a new goroutine and collects the error it returns,
+// if any.
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.Add(f())
+	}()
+}
+
+// Add collects err. A nil err is ignored.
+func (g *Group) Add(err error) {
+	if err == nil {
+		return
+	}
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+}
+
+// Wait waits for the functions called with Go to return, then returns
+// the collected errors as a *GroupError, or nil if there are none.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	errs := make([]error, len(g.errs))
+	copy(errs, g.errs)
+	return &GroupError{Errors: errs}
+}
+
+// GroupError is the aggregate of the errors collected by a Group, in
+// the order they were collected. Errors joined with errors.Join are
+// handled as a GroupError of their members too. Its Kind is the most
+// severe Kind of its errors (see SetKindPrecedence), and HTTPError
+// sends it with the status code of the error of that Kind, listing each
+// error of the group.
+type GroupError struct {
+	Errors []error
+}
+
+func (ge *GroupError) Error() string {
+	s := make([]string, len(ge.Errors))
+	for i, err := range ge.Errors {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Unwrap returns the errors of the group, so they can be inspected
+// with errors.Is and errors.As.
+func (ge *GroupError) Unwrap() []error {
+	return ge.Errors
+}
+
+// Kind returns the most severe Kind of the errors of the group, as
+// HTTPError sends them. If several errors have this Kind, the first
+// one determines the response.
+func (ge *GroupError) Kind() Kind {
+	k, _ := classify(ge.dominant())
+	return k
+}
+
+// dominant returns the first error of the group with the most severe
+// Kind (see SetKindPrecedence), or nil if the group is empty.
+func (ge *GroupError) dominant() error {
+	return mostSevere(ge.Errors)
+}
+
+// serviceError returns the status code and ServiceError of the
+// dominant error of the group, listing each error of the group.
+func (ge *GroupError) serviceError() (int, *ServiceError) {
+	status, se := serviceError(ge.dominant())
+	if se == nil {
+		se = &ServiceError{Message: ReasonPhrase(status)}
+	}
+	se.Errors = make([]ServiceError, len(ge.Errors))
+	for i, err := range ge.Errors {
+		s, ise := serviceError(err)
+		if ise == nil {
+			ise = &ServiceError{Message: ReasonPhrase(s)}
+		}
+		se.Errors[i] = *ise
+	}
+	return status, se
+}
+
+// groupOf returns the GroupError in the chain of err, if any. Errors
+// joined with errors.Join, or by any other type with an Unwrap() []error
+// method, are handled as a GroupError of their members, except for the
+// BatchError and ValidationErrors, which have a format of their own.
+func groupOf(err error) *GroupError {
+	var ge *GroupError
+	if stderrors.As(err, &ge) {
+		return ge
+	}
+	for _, err := range chainOf(err) {
+		if errs := joinedOf(err); errs != nil {
+			return &GroupError{Errors: errs}
+		}
+	}
+	return nil
+}
+
+// joinedOf returns the members of err if it joins several errors, as
+// errors.Join does, and is not a BatchError or a ValidationErrors.
+func joinedOf(err error) []error {
+	switch err.(type) {
+	case *BatchError, ValidationErrors:
+		return nil
+	}
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
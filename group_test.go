@@ -0,0 +1,83 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v; want nil", err)
+	}
+	notFound := E(Op("users.Get"), NotExist, Str("user not found"))
+	for i := 0; i < 10; i++ {
+		i := i
+		g.Go(func() error {
+			if i%2 == 0 {
+				return nil
+			}
+			return notFound
+		})
+	}
+	g.Add(nil)
+	err := g.Wait()
+	var ge *GroupError
+	if !stderrors.As(err, &ge) {
+		t.Fatalf("Wait() = %T; want *GroupError", err)
+	}
+	if len(ge.Errors) != 5 {
+		t.Errorf("len(Errors) = %d; want 5", len(ge.Errors))
+	}
+	if !stderrors.Is(err, notFound) {
+		t.Error("errors.Is(Wait(), notFound) = false; want true")
+	}
+}
+
+func TestGroupError(t *testing.T) {
+	validation := RE(http.StatusBadRequest, Validation, Str("name is required"))
+	permission := RE(http.StatusForbidden, Permission, Str("access denied"))
+	unavailable := RE(http.StatusServiceUnavailable, IO, Str("connection refused"))
+	tests := []struct {
+		name       string
+		errs       []error
+		wantKind   Kind
+		wantStatus int
+	}{
+		{"One", []error{validation}, Validation, http.StatusBadRequest},
+		{"Permission over Validation", []error{validation, permission}, Permission, http.StatusForbidden},
+		{"IO over Permission", []error{permission, unavailable, validation}, IO, http.StatusServiceUnavailable},
+		{"Unclassified", []error{validation, Str("boom")}, Unanticipated, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &GroupError{Errors: tt.errs}
+			if got := KindOf(err); got != tt.wantKind {
+				t.Errorf("KindOf() = %v; want %v", got, tt.wantKind)
+			}
+			status, se := serviceError(err)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d; want %d", status, tt.wantStatus)
+			}
+			if se == nil || len(se.Errors) != len(tt.errs) {
+				t.Fatalf("ServiceError = %+v; want %d errors", se, len(tt.errs))
+			}
+			if se.Kind != tt.wantKind.String() {
+				t.Errorf("ServiceError.Kind = %q; want %q", se.Kind, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorGroup(t *testing.T) {
+	var g Group
+	g.Go(func() error { return RE(http.StatusBadRequest, Validation, Str("name is required")) })
+	g.Go(func() error { return RE(http.StatusConflict, Exist, Str("user exists")) })
+	rr := httptest.NewRecorder()
+	HTTPError(rr, g.Wait())
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/gilcrest/errors/grpcerrors
+
+go 1.21
+
+require (
+	github.com/gilcrest/errors v0.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.34.1
+)
+
+require (
+	github.com/rs/zerolog v1.14.0 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/gilcrest/errors => ../
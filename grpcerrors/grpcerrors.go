@@ -0,0 +1,201 @@
+// Package grpcerrors converts errors built with the errors package
+// into gRPC status errors, so services that speak both HTTP and gRPC
+// classify errors the same way on either transport.
+package grpcerrors
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gilcrest/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// unexpectedMsg is the message sent for errors which are not safe to
+// send to the client as is.
+const unexpectedMsg = "Unexpected error - contact support"
+
+// Domain is sent as the domain of the ErrorInfo detail attached to
+// errors which have a Code. It is typically the DNS name of the service.
+var Domain = ""
+
+// GRPCError converts err into a gRPC status error. The gRPC code is
+// determined from the Kind of the error or, for an *errors.HTTPErr
+// with no Kind, from its HTTP status code. If the error has a Param,
+// a BadRequest detail is attached with a field violation for it. If
+// the error has a Code, an ErrorInfo detail is attached with the Code
+// as its reason.
+//
+// The message of an *errors.Error with no Kind, or of Kind Internal,
+// Database or Unanticipated, is not sent, so internal details are not
+// leaked to the client. The message of an *errors.HTTPErr is the one
+// errors.HTTPError would send: its UserMsg, if any, and never its
+// DevMsg.
+//
+// Errors which are already gRPC status errors are returned as is.
+// Any other error types are sent as codes.Unknown with a generic
+// message, as is done by errors.HTTPError. If err is nil, GRPCError
+// returns nil.
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var (
+		code  codes.Code
+		kind  errors.Kind
+		ecode errors.Code
+		param errors.Parameter
+		msg   string
+	)
+	var he *errors.HTTPErr
+	var e *errors.Error
+	switch {
+	case stderrors.As(err, &he):
+		kind, ecode, param = he.Kind, he.Code, he.Param
+		// The message is the one errors.HTTPError would send, so
+		// the DevMsg is never sent and the UserMsg and
+		// errors.ClientSafeKinds are honored.
+		msg = errors.NewProblemResponse(he).Detail
+		code = HTTPStatusCode(he.Status())
+		if kind != errors.Other {
+			code = KindCode(kind)
+		}
+	case stderrors.As(err, &e):
+		kind, ecode, param = kindOf(e), e.Code, e.Param
+		msg = message(e, kind)
+		code = KindCode(kind)
+	default:
+		return status.Error(codes.Unknown, unexpectedMsg)
+	}
+
+	st := status.New(code, msg)
+	var details []protoadapt.MessageV1
+	if param != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: string(param), Description: msg},
+			},
+		})
+	}
+	if ecode != "" {
+		info := &errdetails.ErrorInfo{Reason: string(ecode), Domain: Domain}
+		if kind != errors.Other {
+			info.Metadata = map[string]string{"kind": kind.String()}
+		}
+		details = append(details, info)
+	}
+	if len(details) == 0 {
+		return st.Err()
+	}
+	// WithDetails only fails if a detail cannot be marshaled, in
+	// which case the status is sent without details.
+	if dst, derr := st.WithDetails(details...); derr == nil {
+		st = dst
+	}
+	return st.Err()
+}
+
+// message returns the message sent for e. It is the message of the
+// innermost error wrapped by e which is not an *errors.Error, so the
+// Ops are not sent to the client, or the Kind if there is no such error.
+//
+// Errors with no Kind, or of a Kind which means something went wrong
+// on the server, may carry internal details (a database driver error,
+// for example), so they are sent with a generic message instead, as
+// errors.HTTPError does.
+func message(e *errors.Error, kind errors.Kind) string {
+	switch kind {
+	case errors.Other, errors.Internal, errors.Database, errors.Unanticipated:
+		return unexpectedMsg
+	}
+	for {
+		next, ok := e.Err.(*errors.Error)
+		if !ok {
+			break
+		}
+		e = next
+	}
+	if e.Err == nil {
+		return kind.String()
+	}
+	return e.Err.Error()
+}
+
+// kindOf returns the Kind of e, or of the first nested *errors.Error
+// with a Kind if e has none.
+func kindOf(e *errors.Error) errors.Kind {
+	for e != nil {
+		if e.Kind != errors.Other {
+			return e.Kind
+		}
+		e, _ = e.Err.(*errors.Error)
+	}
+	return errors.Other
+}
+
+// KindCode returns the gRPC code for the given Kind.
+func KindCode(k errors.Kind) codes.Code {
+	switch k {
+	case errors.Invalid, errors.Validation, errors.InvalidRequest:
+		return codes.InvalidArgument
+	case errors.Permission, errors.Private:
+		return codes.PermissionDenied
+	case errors.IO:
+		return codes.Unavailable
+	case errors.Exist:
+		return codes.AlreadyExists
+	case errors.NotExist, errors.BrokenLink:
+		return codes.NotFound
+	case errors.Internal, errors.Database:
+		return codes.Internal
+	case errors.Timeout:
+		return codes.DeadlineExceeded
+	case errors.Canceled:
+		return codes.Canceled
+	case errors.Unauthorized:
+		return codes.Unauthenticated
+	}
+	return codes.Unknown
+}
+
+// HTTPStatusCode returns the gRPC code for the given HTTP status code.
+func HTTPStatusCode(sc int) codes.Code {
+	switch sc {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // Client Closed Request
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case sc >= 400 && sc < 500:
+		return codes.FailedPrecondition
+	case sc >= 500:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
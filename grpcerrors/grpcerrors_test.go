@@ -0,0 +1,98 @@
+package grpcerrors
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCError(t *testing.T) {
+	const op errors.Op = "grpcerrors/TestGRPCError"
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{"Error", errors.E(op, errors.NotExist, "no such user"), codes.NotFound, "no such user"},
+		{"Nested Error", errors.E(op, errors.E(errors.Op("inner"), errors.Exist, "user exists")), codes.AlreadyExists, "user exists"},
+		{"Error with no message", errors.E(errors.Op("svc.Get"), errors.NotExist), codes.NotFound, "item_does_not_exist"},
+		{"Error with Op only", errors.E(errors.Op("x"), errors.Other), codes.Unknown, "Unexpected error - contact support"},
+		{"Database Error", errors.E(op, errors.Database, "pq: password authentication failed"), codes.Internal, "Unexpected error - contact support"},
+		{"Internal Error", errors.E(op, errors.E(errors.Op("inner"), errors.Internal, "nil pointer")), codes.Internal, "Unexpected error - contact support"},
+		{"Unanticipated Error", errors.E(op, errors.Unanticipated, "boom"), codes.Unknown, "Unexpected error - contact support"},
+		{"HTTPErr Kind", errors.RE(http.StatusBadRequest, errors.Permission, errors.Str("denied")), codes.PermissionDenied, "denied"},
+		{"HTTPErr Status", errors.RE(http.StatusServiceUnavailable, errors.Str("try later")), codes.Unavailable, "try later"},
+		{"Unknown", errors.Str("some error"), codes.Unknown, "Unexpected error - contact support"},
+		{"Status", status.Error(codes.Aborted, "aborted"), codes.Aborted, "aborted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(GRPCError(tt.err))
+			if !ok {
+				t.Fatal("GRPCError() did not return a status error")
+			}
+			if st.Code() != tt.wantCode {
+				t.Errorf("Code() = %v; want %v", st.Code(), tt.wantCode)
+			}
+			if st.Message() != tt.wantMsg {
+				t.Errorf("Message() = %q; want %q", st.Message(), tt.wantMsg)
+			}
+		})
+	}
+	if GRPCError(nil) != nil {
+		t.Error("GRPCError(nil) != nil")
+	}
+}
+
+func TestGRPCErrorDevMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+	}{
+		{"DevMsg", errors.RE(http.StatusNotFound, errors.NotExist, errors.DevMsg("select * from users: no rows"), errors.Str("no such user")), "no such user"},
+		{"DevMsg and UserMsg", errors.RE(http.StatusNotFound, errors.NotExist, errors.DevMsg("select * from users: no rows"), errors.UserMsg("User not found"), errors.Str("no such user")), "User not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _ := status.FromError(GRPCError(tt.err))
+			if st.Message() != tt.wantMsg {
+				t.Errorf("Message() = %q; want %q", st.Message(), tt.wantMsg)
+			}
+			if strings.Contains(st.Message(), "select") {
+				t.Errorf("Message() = %q; DevMsg sent to the client", st.Message())
+			}
+		})
+	}
+}
+
+func TestGRPCErrorDetails(t *testing.T) {
+	err := errors.RE(http.StatusBadRequest, errors.Validation, errors.Code("missing_name"), errors.Parameter("name"), errors.MissingField("name"))
+
+	st, _ := status.FromError(GRPCError(err))
+	var (
+		br   *errdetails.BadRequest
+		info *errdetails.ErrorInfo
+	)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			br = d
+		case *errdetails.ErrorInfo:
+			info = d
+		}
+	}
+	if br == nil || len(br.FieldViolations) != 1 || br.FieldViolations[0].Field != "name" {
+		t.Errorf("BadRequest detail = %v; want field violation for name", br)
+	}
+	if info == nil || info.Reason != "missing_name" || info.Metadata["kind"] != errors.Validation.String() {
+		t.Errorf("ErrorInfo detail = %v; want reason missing_name", info)
+	}
+}
@@ -0,0 +1,47 @@
+package grpcerrors
+
+import (
+	"context"
+
+	"github.com/gilcrest/errors"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor which
+// converts the errors returned by handlers with GRPCError. Each error
+// is logged and counted with errors.ReportError, with the full name of
+// the gRPC method in the grpc_method field, as errors.HTTPError does
+// for HTTP handlers:
+//
+//	s := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpcerrors.UnaryServerInterceptor()),
+//		grpc.ChainStreamInterceptor(grpcerrors.StreamServerInterceptor()),
+//	)
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			report(ctx, err, info.FullMethod)
+			return resp, GRPCError(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor which
+// converts, logs and counts the errors returned by stream handlers, as
+// UnaryServerInterceptor does.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			report(ss.Context(), err, info.FullMethod)
+			return GRPCError(err)
+		}
+		return nil
+	}
+}
+
+// report logs and counts err, returned by the given gRPC method.
+func report(ctx context.Context, err error, method string) {
+	errors.ReportError(ctx, err, errors.Fields{"grpc_method": method})
+}
@@ -0,0 +1,66 @@
+package grpcerrors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testLogger records the entries logged.
+type testLogger struct {
+	fields []errors.Fields
+}
+
+func (l *testLogger) Log(_ errors.Level, _ string, f errors.Fields) {
+	l.fields = append(l.fields, f)
+}
+
+// testStream is a grpc.ServerStream with a context.
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestInterceptors(t *testing.T) {
+	defer errors.SetLogger(nil)
+	tl := &testLogger{}
+	errors.SetLogger(tl)
+
+	notFound := errors.E(errors.Op("users.Get"), errors.NotExist, "no such user")
+	ctx := errors.WithRequestID(context.Background(), "req-1")
+
+	_, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"},
+		func(context.Context, interface{}) (interface{}, error) { return nil, notFound })
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("unary error code = %v; want %v", status.Code(err), codes.NotFound)
+	}
+	resp, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"},
+		func(context.Context, interface{}) (interface{}, error) { return "user", nil })
+	if err != nil || resp != "user" {
+		t.Errorf("unary = %v, %v; want user, nil", resp, err)
+	}
+
+	err = StreamServerInterceptor()(nil, testStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/users.Users/List"},
+		func(interface{}, grpc.ServerStream) error { return notFound })
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("stream error code = %v; want %v", status.Code(err), codes.NotFound)
+	}
+
+	if len(tl.fields) != 2 {
+		t.Fatalf("got %d log entries; want 2", len(tl.fields))
+	}
+	for i, method := range []string{"/users.Users/Get", "/users.Users/List"} {
+		f := tl.fields[i]
+		if f["grpc_method"] != method || f["request_id"] != "req-1" || f["op_chain"] != "users.Get" {
+			t.Errorf("fields = %v; want grpc_method %s, request_id req-1 and op_chain users.Get", f, method)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+)
+
+// HandlerFunc is an HTTP handler which returns an error instead of
+// writing an error response itself. HandlerFunc implements
+// http.Handler: if the function returns an error, it is sent to the
+// client with HTTPErrorCtx, using the context of the request.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f(w, r) and sends any error it returns. Error
+// messages are localized using the Accept-Language header of the
+// request, unless a locale was already set with WithLocale. The
+// context of r collects warnings (see WithWarnings), unless it
+// already did.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w = TrackWrites(w)
+	if r.Context().Value(warningsKey) == nil {
+		r = r.WithContext(WithWarnings(r.Context()))
+	}
+	if err := f(w, r); err != nil {
+		HTTPErrorCtx(RequestContext(r), w, err)
+	}
+}
+
+// RequestContext returns the context of r to give to HTTPErrorCtx.
+// Unless a locale was already set with WithLocale, the locale of the
+// returned context is the Accept-Language header of the request, so
+// error messages are localized. If DebugHeader is set and the request
+// has that header, debug responses are enabled (see WithDebug). If the
+// Accept header of the request has a version parameter, it sets the
+// version of the format of the error responses (see ResponseVersion),
+// unless one was already set with WithResponseVersion. If the Accept
+// header prefers a media type with a registered ResponseEncoder, error
+// responses are encoded with it (see RegisterEncoder), unless one was
+// already set with WithResponseEncoder. The resource of the audit
+// events of the request is its method and path (see WithResource). The
+// Origin header of the request is kept for the CORS headers of the
+// error responses (see CORS), and r itself for the hooks registered
+// with OnKind.
+func RequestContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if ctx.Value(resourceKey) == nil {
+		ctx = WithResource(ctx, requestResource(r))
+	}
+	if enc := acceptEncoder(r.Header.Get("Accept")); enc != nil && ctx.Value(encoderKey) == nil {
+		ctx = WithResponseEncoder(ctx, enc)
+	}
+	if v := acceptVersion(r.Header.Get("Accept")); v != 0 && ctx.Value(versionKey) == nil {
+		ctx = WithResponseVersion(ctx, v)
+	}
+	if al := r.Header.Get("Accept-Language"); al != "" && LocaleFromContext(ctx) == "" {
+		ctx = WithLocale(ctx, al)
+	}
+	if DebugHeader != "" && r.Header.Get(DebugHeader) != "" {
+		ctx = WithDebug(ctx)
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		ctx = context.WithValue(ctx, originKey, origin)
+	}
+	if ctx.Value(requestKey) == nil {
+		ctx = context.WithValue(ctx, requestKey, r)
+	}
+	return ctx
+}
+
+// Handler adapts fn, an HTTP handler which returns an error, to an
+// http.Handler. Any error returned by fn is sent to the client with
+// HTTPErrorCtx, so error responses are written in a single place.
+func Handler(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return HandlerFunc(fn)
+}
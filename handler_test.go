@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         func(w http.ResponseWriter, r *http.Request) error
+		wantStatus int
+	}{
+		{"No error", func(w http.ResponseWriter, r *http.Request) error {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}, http.StatusNoContent},
+		{"HTTPErr", func(w http.ResponseWriter, r *http.Request) error {
+			return RE(http.StatusNotFound, NotExist, Str("no such user"))
+		}, http.StatusNotFound},
+		{"Unknown error", func(w http.ResponseWriter, r *http.Request) error {
+			return Str("some error")
+		}, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			Handler(tt.fn).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"net/http"
+)
+
+// ResponseHeader is a header sent with the error response of an
+// HTTPErr, such as WWW-Authenticate for a 401 or Allow for a 405.
+// Build one with Header and give it to RE:
+//
+//	errors.RE(http.StatusUnauthorized, errors.Unauthorized, errors.Header("WWW-Authenticate", `Bearer realm="api"`))
+type ResponseHeader struct {
+	Name, Value string
+}
+
+// Header returns the ResponseHeader name with the given value.
+func Header(name, value string) ResponseHeader {
+	return ResponseHeader{Name: name, Value: value}
+}
+
+// addHeader adds h to the headers of the response of hse.
+func (hse *HTTPErr) addHeader(h ResponseHeader) {
+	if hse.Headers == nil {
+		hse.Headers = make(http.Header)
+	}
+	hse.Headers.Add(h.Name, h.Value)
+}
+
+// setHeaders sets the Headers of the HTTPErrs in the chain of err in
+// the response. A header of an HTTPErr replaces the same header of the
+// errors it wraps, and the headers set by HTTPError, e.g. Retry-After.
+func setHeaders(w http.ResponseWriter, err error) {
+	var hs []http.Header
+	for _, err := range chainOf(err) {
+		if e, ok := err.(*HTTPErr); ok && len(e.Headers) > 0 {
+			hs = append(hs, e.Headers)
+		}
+	}
+	// Innermost first, so the outer errors take precedence
+	for i := len(hs) - 1; i >= 0; i-- {
+		for k, v := range hs[i] {
+			w.Header()[k] = append([]string(nil), v...)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		want       http.Header
+	}{
+		{"WWW-Authenticate",
+			RE(http.StatusUnauthorized, Unauthorized, Header("WWW-Authenticate", `Bearer realm="api"`)),
+			http.StatusUnauthorized,
+			http.Header{"Www-Authenticate": {`Bearer realm="api"`}}},
+		{"Several values",
+			RE(http.StatusMethodNotAllowed, Header("Allow", "GET"), Header("Allow", "HEAD")),
+			http.StatusMethodNotAllowed,
+			http.Header{"Allow": {"GET", "HEAD"}}},
+		{"Outer error wins",
+			RE(http.StatusSeeOther, Header("Location", "/v2/users/1"),
+				RE(http.StatusNotFound, Header("Location", "/users/1"), Header("X-Inner", "1"))),
+			http.StatusSeeOther,
+			http.Header{"Location": {"/v2/users/1"}, "X-Inner": {"1"}}},
+		{"Replaces Retry-After",
+			RE(http.StatusServiceUnavailable, Retry(5*time.Second), Header("Retry-After", "120")),
+			http.StatusServiceUnavailable,
+			http.Header{"Retry-After": {"120"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+			for k, v := range tt.want {
+				if got := rr.Header().Values(k); !reflect.DeepEqual(got, v) {
+					t.Errorf("header %s = %q; want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestHeaderJSON(t *testing.T) {
+	err := RE(http.StatusUnauthorized, Unauthorized, Header("WWW-Authenticate", "Bearer")).(*HTTPErr)
+	b, merr := json.Marshal(err)
+	if merr != nil {
+		t.Fatalf("json.Marshal() error = %v", merr)
+	}
+	var got HTTPErr
+	if uerr := json.Unmarshal(b, &got); uerr != nil {
+		t.Fatalf("json.Unmarshal() error = %v", uerr)
+	}
+	if !reflect.DeepEqual(got.Headers, err.Headers) {
+		t.Errorf("Headers = %v; want %v", got.Headers, err.Headers)
+	}
+}
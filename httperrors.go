@@ -1,13 +1,13 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	stderrors "errors"
 	"net/http"
 	"runtime"
 	"strings"
-
-	"github.com/rs/zerolog/log"
+	"time"
 )
 
 // hError represents an HTTP handler error. It provides methods for a HTTP status
@@ -22,21 +22,89 @@ type hError interface {
 }
 
 // HTTPErr represents an error with an associated HTTP status code.
+// The methods of *HTTPErr which are not methods of HTTPErr, such as
+// Timeout and MarshalJSON, may be called on a nil *HTTPErr, which
+// behaves as a zero HTTPErr. The methods of HTTPErr, such as Error,
+// Status and Unwrap, have value receivers, so an HTTPErr value is an
+// error too, and they panic when called on a nil *HTTPErr. The
+// functions of this package do not call them on a nil *HTTPErr: it
+// ends the chain of an error, and HTTPError sends an error with one in
+// its chain as an error of Kind Internal with Code
+// InvalidErrorConstruction.
 type HTTPErr struct {
 	HTTPStatusCode int
 	Kind           Kind
 	Param          Parameter
 	Code           Code
-	Err            error
+	Retryable      bool
+	RetryAfter     time.Duration
+	Severity       Severity
+	// Elapsed is the time spent by the operation before it failed,
+	// and Deadline its deadline, if known (see Elapsed and Deadline).
+	Elapsed  time.Duration
+	Deadline time.Time
+	// RateLimit is the rate limit exceeded, for HTTP 429 errors.
+	RateLimit *RateLimit
+	// Headers are sent with the error response (see Header).
+	Headers http.Header
+	// UserMsg, if set, is the message sent to the client instead of
+	// the message of Err.
+	UserMsg string
+	// DevMsg, if set, is a message for developers, which is logged
+	// before the message of Err but never sent to the client.
+	DevMsg string
+	Err    error
+	// The operations given to RE, if any, outermost first.
+	ops []Op
+	// The call stack recorded when the error was constructed,
+	// if CaptureStack is true.
+	trace StackTrace
+	// The location where the error was constructed,
+	// if CaptureLocation is true.
+	pc uintptr
 }
 
-// Allows HTTPErr to satisfy the error interface.
+// UserMsg is the message of an HTTPErr for the users of the service.
+// Given to RE, it is sent to the client as the message of the error,
+// instead of the message of the wrapped error.
+type UserMsg string
+
+// DevMsg is the message of an HTTPErr for the developers of the
+// service. Given to RE, it is logged, before the message of the wrapped
+// error, but never sent to the client.
+type DevMsg string
+
+// Allows HTTPErr to satisfy the error interface. The message is the
+// DevMsg, if any, followed by the message of the wrapped error.
 func (hse HTTPErr) Error() string {
+	switch {
+	case hse.DevMsg != "" && hse.Err != nil:
+		return hse.DevMsg + ": " + hse.Err.Error()
+	case hse.DevMsg != "":
+		return hse.DevMsg
+	case hse.Err != nil:
+		return hse.Err.Error()
+	}
 	// In case user forgets to add an error type to HTTPErr
-	if hse.Err == nil {
-		return ""
+	return ""
+}
+
+// userMessage returns the message of hse sent to the client: the
+// UserMsg, if any, or else the message of the wrapped error.
+func (hse HTTPErr) userMessage() string {
+	switch {
+	case hse.UserMsg != "":
+		return hse.UserMsg
+	case hse.Err != nil:
+		return hse.Err.Error()
 	}
-	return hse.Err.Error()
+	return ""
+}
+
+// Unwrap returns the underlying error, if any. It allows HTTPErr to be
+// used with errors.Is and errors.As from the standard library.
+func (hse HTTPErr) Unwrap() error {
+	return hse.Err
 }
 
 // SetErr creates an error type and adds it to the struct
@@ -62,29 +130,64 @@ func (hse HTTPErr) ErrCode() string {
 	return string(hse.Code)
 }
 
-// Status Returns an HTTP Status Code.
+// Status Returns an HTTP Status Code. If no status code was set,
+// the status code mapped to the Kind is returned (see KindStatus).
 func (hse HTTPErr) Status() int {
+	if hse.HTTPStatusCode == 0 {
+		return KindStatus(hse.Kind)
+	}
 	return hse.HTTPStatusCode
 }
 
+// ReasonPhrase returns the reason phrase of the status code of the
+// error (see ReasonPhrase and Status).
+func (hse HTTPErr) ReasonPhrase() string {
+	return ReasonPhrase(hse.Status())
+}
+
 // StatusOnly determines if the only field populated is the HTTP Status Code
 // If so, the error response body should not be populated
-func (hse *HTTPErr) StatusOnly() bool {
-	return hse.HTTPStatusCode != 0 && hse.Kind == 0 && hse.Param == "" && hse.Code == "" && hse.Err == nil
+func (hse HTTPErr) StatusOnly() bool {
+	return hse.HTTPStatusCode != 0 && hse.Kind == 0 && hse.Param == "" && hse.Code == "" && hse.Err == nil && hse.RateLimit == nil &&
+		hse.UserMsg == "" && hse.DevMsg == ""
 }
 
 // ErrResponse is used as the Response Body
 type ErrResponse struct {
-	Error ServiceError `json:"error"`
+	Error ServiceError `json:"error" xml:"error"`
 }
 
 // ServiceError has fields for Service errors. All fields with no data will
 // be omitted
 type ServiceError struct {
-	Kind    string `json:"kind,omitempty"`
-	Code    string `json:"code,omitempty"`
-	Param   string `json:"param,omitempty"`
-	Message string `json:"message,omitempty"`
+	Kind    string `json:"kind,omitempty" xml:"kind,omitempty"`
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Param   string `json:"param,omitempty" xml:"param,omitempty"`
+	Message string `json:"message,omitempty" xml:"message,omitempty"`
+	// Reason is the reason phrase of the status code of the response,
+	// e.g. "Not Found", if IncludeReason is true
+	Reason string `json:"reason,omitempty" xml:"reason,omitempty"`
+	// Got is the invalid value of the parameter and Want the
+	// constraint it does not satisfy, if known (see Param)
+	Got  interface{} `json:"got,omitempty" xml:"got,omitempty"`
+	Want string      `json:"want,omitempty" xml:"want,omitempty"`
+	// Errors lists each error of a ValidationErrors
+	Errors []ServiceError `json:"errors,omitempty" xml:"errors>error"`
+	// Fields lists the errors of a ValidationErrors which relate
+	// to a parameter
+	Fields []FieldViolation `json:"fields,omitempty" xml:"fields>field"`
+	// RequestID is the ID of the request which failed, if known
+	RequestID string `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	// ErrorID is the ID of the error response (see ErrorIDFunc)
+	ErrorID string `json:"error_id,omitempty" xml:"error_id,omitempty"`
+	// TraceID is the ID of the trace of the request, if known
+	// (see SetTracer)
+	TraceID string `json:"trace_id,omitempty" xml:"trace_id,omitempty"`
+	// DocURL is the URL of the documentation of the error Code,
+	// if registered (see RegisterDocURL)
+	DocURL string `json:"doc_url,omitempty" xml:"doc_url,omitempty"`
+	// Chain lists the nested errors of the error in debug responses
+	Chain []ChainLink `json:"chain,omitempty" xml:"chain>link"`
 }
 
 // HTTPError takes a writer and an error, performs a type switch to
@@ -93,60 +196,387 @@ type ServiceError struct {
 // client. If the type does not meet the Error interface as defined in this
 // package, then a proper error is still formed and sent to the client,
 // however, the Kind and Code will be Unanticipated.
+//
+// If ProblemDetails is true, the response is sent in the RFC 7807
+// format instead (see HTTPProblem). Sensitive data is masked from the
+// response as determined by the Redaction policy, but the error is
+// logged in full. If ClientSafeKinds is set, the messages of errors of
+// other Kinds are not sent either. Messages longer than
+// MaxMessageLength are truncated. The fields of the response may be
+// renamed with Schema.
 func HTTPError(w http.ResponseWriter, err error) {
+	HTTPErrorCtx(context.Background(), w, err)
+}
+
+// HTTPErrorCtx is like HTTPError, but also takes the context of the
+// request. If a request ID can be found in ctx (see RequestIDFunc),
+// it is added to the log entry and to the response body, so clients
+// can quote it when contacting support. If debug responses are enabled
+// for the request (see DebugResponses and WithDebug), the chain of
+// nested errors is added to the response body.
+//
+// If the response was already started, e.g. by a handler which failed
+// while streaming, HTTPErrorCtx cannot send the error response. When
+// this can be detected (see TrackWrites), the error is logged and its
+// status, Kind and Code are sent in the X-Error-Status, X-Error-Kind
+// and X-Error-Code trailers instead. It is still recorded by the
+// Metrics, Tracer and Reporter as any other error sent.
+func HTTPErrorCtx(ctx context.Context, w http.ResponseWriter, err error) {
 	const op Op = "errors.httpError"
 
-	if err != nil {
-		// We perform a "type switch" https://tour.golang.org/methods/16
-		// to determine the interface value type
-		switch e := err.(type) {
-		// If the interface value is of type Error (not a typical error, but
-		// the Error interface defined above), then
-		case hError:
-			// We can retrieve the status here and write out a specific
-			// HTTP status code.
-			if e.StatusOnly() {
-				log.Error().Int("HTTP Error StatusCode", e.Status()).Msg("")
-			} else {
-				log.Error().Msgf("HTTP %d - %s", e.Status(), e)
-			}
-			if e.StatusOnly() {
-				sendError(w, "", e.Status())
-			} else {
-				er := ErrResponse{
-					Error: ServiceError{
-						Kind:    e.ErrKind(),
-						Code:    e.ErrCode(),
-						Param:   e.ErrParam(),
-						Message: e.Error(),
-					},
-				}
-
-				// Marshal errResponse struct to JSON for the response body
-				errJSON, _ := json.MarshalIndent(er, "", "    ")
-
-				sendError(w, string(errJSON), e.Status())
-			}
+	if err == nil {
+		return
+	}
 
-		default:
-			// Any error types we don't specifically look out for default
-			// to serving a HTTP 500
-			cd := http.StatusInternalServerError
-			er := ErrResponse{
-				Error: ServiceError{
-					Kind:    Unanticipated.String(),
-					Code:    "Unanticipated",
-					Message: "Unexpected error - contact support",
-				},
-			}
+	err = soundChain(err)
+	ctx = withErrorID(ctx)
+	logHTTPError(ctx, err, requestFields(RequestIDFunc(ctx)))
+	writeHTTPError(ctx, w, err)
+}
 
-			log.Error().Msgf("Unknown Error - HTTP %d - %s", cd, err.Error())
+// nilHTTPErrError returns the error sent instead of a nil *HTTPErr.
+func nilHTTPErrError() error {
+	return &HTTPErr{Kind: Internal, Code: "InvalidErrorConstruction", Err: Str("nil *errors.HTTPErr used as an error")}
+}
+
+// nilHTTPErr reports whether err is a nil *HTTPErr, on which the
+// methods of HTTPErr panic.
+func nilHTTPErr(err error) bool {
+	hse, ok := err.(*HTTPErr)
+	return ok && hse == nil
+}
 
-			// Marshal errResponse struct to JSON for the response body
-			errJSON, _ := json.MarshalIndent(er, "", "    ")
+// writeHTTPError sends err to the client, as HTTPErrorCtx does,
+// without logging it.
+func writeHTTPError(ctx context.Context, w http.ResponseWriter, err error) {
+	if nilHTTPErr(err) {
+		err = nilHTTPErrError()
+	}
+	if responseStarted(w) {
+		// The status code was already sent, so the error can only
+		// be reported in the trailers
+		status, _ := serviceError(err)
+		errorSent(ctx, err, status)
+		kind, code := classify(err)
+		setErrorTrailers(w, status, kind, code)
+		logger.Log(WarnLevel, "response already started, error sent in trailers only", requestFields(RequestIDFunc(ctx)))
+		return
+	}
+	setCORS(ctx, w)
+	runKindHooks(ctx, w, err)
+	if be := batchOf(err); be != nil {
+		// Batches have a format of their own
+		status, _ := errorResponse(ctx, err)
+		sendBatch(ctx, w, status, be)
+		return
+	}
+	if ProblemDetails {
+		httpProblem(ctx, w, err)
+		return
+	}
 
-			sendError(w, string(errJSON), cd)
+	setRetryAfter(w, err)
+	setRateLimit(w, err)
+	setFingerprint(w, err)
+	setHeaders(w, err)
+
+	status, se := errorResponse(ctx, err)
+	if responseVersion(ctx) == ResponseV2 {
+		sendResponseV2(ctx, w, status, se)
+		return
+	}
+	// If only the HTTP Status Code is populated, the response
+	// body should be empty
+	if se == nil {
+		sendError(ctx, w, "", status)
+		return
+	}
+
+	if enc := responseEncoder(ctx); enc != nil {
+		sendEncoded(ctx, w, enc, status, ErrResponse{Error: *se})
+		return
+	}
+
+	// Marshal errResponse struct to JSON for the response body
+	errJSON, merr := marshalResponse(ErrResponse{Error: *se})
+	if merr == nil {
+		errJSON, merr = Schema.apply(errJSON, false)
+	}
+	if merr != nil {
+		body := staticResponse
+		if b, err := Schema.apply([]byte(staticResponse), false); err == nil {
+			body = string(b)
+		}
+		sendMarshalFailure(w, status, merr, "application/json", body)
+		return
+	}
+
+	sendError(ctx, w, string(errJSON), status)
+}
+
+// ResponseIndent is the indentation of the JSON error responses sent by
+// HTTPError. It is four spaces by default, which is easier to read in
+// development. Set it to "" to send compact JSON, e.g. in production,
+// to reduce the size of the responses.
+var ResponseIndent = "    "
+
+// marshalResponse returns the JSON encoding of the error response v,
+// indented with ResponseIndent.
+func marshalResponse(v interface{}) ([]byte, error) {
+	if ResponseIndent == "" {
+		return json.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", ResponseIndent)
+}
+
+// staticResponse is the body sent instead of an error response which
+// cannot be marshaled, e.g. because the invalid value of a parameter
+// (see Param) has no JSON encoding. It is built by hand, so it can
+// always be sent.
+const staticResponse = `{"error":{"kind":"unanticipated_error","code":"Unanticipated","message":"Unexpected error - contact support"}}`
+
+// sendMarshalFailure logs merr, the error which occurred when the
+// error response was marshaled, and sends the static body, of the
+// given content type, with the status code of the error response
+// instead, so the client is never sent an empty body.
+func sendMarshalFailure(w http.ResponseWriter, status int, merr error, contentType, body string) {
+	logger.Log(ErrorLevel, "errors: cannot marshal error response, static response sent", Fields{"error": merr.Error(), "status": status})
+	writeBody(context.Background(), w, status, contentType, []byte(body+"\n"))
+}
+
+// errorResponse returns the HTTP status code and the response fields
+// sent for err, with the request ID, the trace ID, the language and the
+// debug setting of ctx, and notifies the Metrics and the Tracer that it
+// is sent. The ServiceError is nil when only the HTTP status code is
+// sent.
+func errorResponse(ctx context.Context, err error) (int, *ServiceError) {
+	status, se := serviceError(err)
+	errorSent(ctx, err, status)
+	if se == nil {
+		return status, nil
+	}
+	if IncludeReason {
+		se.Reason = ReasonPhrase(status)
+	}
+	se.RequestID = RequestIDFunc(ctx)
+	se.ErrorID = errorIDFromContext(ctx)
+	se.TraceID = tracer.TraceID(ctx)
+	se.DocURL = DocURL(Code(se.Code))
+	Redaction.redactServiceError(se)
+	localize(ctx, se)
+	truncateServiceError(se)
+	se.Fields = fieldViolations(se.Errors)
+	if debugEnabled(ctx) {
+		se.Chain = chain(err)
+	}
+	return status, se
+}
+
+// FallbackResponse determines the HTTP status code and the response
+// fields sent by HTTPError for errors which are not an HTTPErr or a
+// ValidationErrors, such as an *Error or an error from another package,
+// and are not translated by a TranslateFunc (see RegisterTranslator).
+// By default, it is DefaultFallbackResponse. Set it to change the
+// message, the status code, or to send the message of some errors.
+var FallbackResponse = DefaultFallbackResponse
+
+// DefaultFallbackResponse is the default FallbackResponse. It sends
+// an HTTP 500 with Kind and Code Unanticipated and a generic message,
+// so the message of err, which may contain internal details, is only
+// logged. Timeouts are sent as an HTTP 504 with Code Timeout, and
+// temporary errors as an HTTP 503 with Code Unavailable (see
+// (*Error).Timeout and (*Error).Temporary).
+func DefaultFallbackResponse(err error) (int, ServiceError) {
+	switch {
+	case isTimeout(err):
+		return http.StatusGatewayTimeout, ServiceError{
+			Kind:    IO.String(),
+			Code:    "Timeout",
+			Message: "Request timed out - try again later",
 		}
+	case isTemporary(err):
+		return http.StatusServiceUnavailable, ServiceError{
+			Kind:    IO.String(),
+			Code:    "Unavailable",
+			Message: "Service temporarily unavailable - try again later",
+		}
+	}
+	return http.StatusInternalServerError, ServiceError{
+		Kind:    Unanticipated.String(),
+		Code:    "Unanticipated",
+		Message: "Unexpected error - contact support",
+	}
+}
+
+// httpErrOf returns err if it is an *HTTPErr, or a pointer to it if it
+// is an HTTPErr, or else nil.
+func httpErrOf(err error) *HTTPErr {
+	switch e := err.(type) {
+	case *HTTPErr:
+		return e
+	case HTTPErr:
+		return &e
+	}
+	return nil
+}
+
+// serviceError determines the HTTP status code and the ServiceError
+// response fields for err. The returned ServiceError is nil when the
+// error only carries an HTTP Status Code.
+func serviceError(err error) (int, *ServiceError) {
+	if nilHTTPErr(err) {
+		err = nilHTTPErrError()
+	}
+	// We perform a "type switch" https://tour.golang.org/methods/16
+	// to determine the interface value type
+	switch e := err.(type) {
+	// If the interface value is of type Error (not a typical error, but
+	// the Error interface defined above), then
+	case hError:
+		// We can retrieve the status here and write out a specific
+		// HTTP status code.
+		if e.StatusOnly() {
+			return e.Status(), nil
+		}
+		se := &ServiceError{
+			Kind:    e.ErrKind(),
+			Code:    e.ErrCode(),
+			Param:   e.ErrParam(),
+			Message: e.Error(),
+		}
+		hse := httpErrOf(err)
+		if hse != nil {
+			// The DevMsg is only logged
+			se.Message = hse.userMessage()
+		}
+		if iv := invalidValueOf(err); iv != nil {
+			se.Got, se.Want = iv.Got, iv.Want
+		}
+		var ve ValidationErrors
+		if stderrors.As(err, &ve) {
+			se.Errors = ve.serviceErrors()
+		}
+		exposeMessages(se, false)
+		// The message meant for the users is sent whatever its Kind
+		if hse != nil && hse.UserMsg != "" {
+			se.Message = hse.UserMsg
+		}
+		return e.Status(), se
+	default:
+		// A group of errors is sent as its most severe error,
+		// listing each error of the group
+		if ge := groupOf(err); ge != nil {
+			return ge.serviceError()
+		}
+		// A batch with failed items is sent as an HTTP 207, listing
+		// the error of each item
+		if be := batchOf(err); be != nil {
+			return http.StatusMultiStatus, &ServiceError{
+				Message: be.Error(),
+				Errors:  be.serviceErrors(),
+			}
+		}
+		// A collection of validation errors is sent as an HTTP 400,
+		// listing each error
+		var ve ValidationErrors
+		if stderrors.As(err, &ve) {
+			se := &ServiceError{
+				Kind:    Validation.String(),
+				Message: ve.Error(),
+				Errors:  ve.serviceErrors(),
+			}
+			exposeMessages(se, true)
+			return http.StatusBadRequest, se
+		}
+		// Errors of third-party packages may be translated into
+		// errors of this package (see RegisterTranslator)
+		if te := translate(err); te != nil {
+			return serviceError(te)
+		}
+		// Any error types we don't specifically look out for are
+		// sent as determined by FallbackResponse
+		status, se := FallbackResponse(err)
+		return status, &se
+	}
+}
+
+// logHTTPError logs err before it is sent as an HTTP response. The
+// message of the entry is the error message, and the classification
+// sent to the client is added as structured fields (status, kind, code
+// and param), along with the chain of operations of the error
+// (op_chain), so logs can be queried by error class. The given fields
+// are added to the log entry. Errors sent with a 5xx status code are
+// logged with their stack trace, if any. The innermost location where
+// an error of the chain was constructed is added as the location field
+// (see (*Error).Location), and the message of the original cause of a
+// wrapped error as the root_cause field (see Root). Messages longer
+// than MaxMessageLength are truncated. The error is logged at the level
+// of its Severity, or else at the level of its status code in the
+// StatusLevels of ctx (see LogLevels). Identical errors may not all be
+// logged if log sampling is enabled (see SetLogSampling). The
+// RequestInfo of ctx, if any, is added to the fields, as are the
+// elapsed time and the deadline of the error (see Elapsed and
+// Deadline).
+func logHTTPError(ctx context.Context, err error, f Fields) {
+	status, se := serviceError(err)
+	f["status"] = status
+	if se != nil {
+		addField(f, "kind", se.Kind)
+		addField(f, "code", se.Code)
+		addField(f, "param", se.Param)
+	}
+	opChain := joinOps(Ops(err))
+	addField(f, "op_chain", opChain)
+	addField(f, "fingerprint", Fingerprint(err))
+	addField(f, "error_id", errorIDFromContext(ctx))
+	addRequestInfo(ctx, f)
+	addTiming(f, err)
+	if logSampler != nil {
+		k := sampleKey{status: status, ops: opChain}
+		if se != nil {
+			k.kind, k.code = se.Kind, se.Code
+		}
+		ok, suppressed := logSampler.allow(k)
+		if !ok {
+			return
+		}
+		if suppressed > 0 {
+			f["suppressed"] = suppressed
+		}
+	}
+	// Client errors are expected, so only server errors
+	// are logged with their stack trace
+	if status >= http.StatusInternalServerError {
+		addStack(f, err)
+	}
+	addField(f, "location", locationOf(err))
+	if stderrors.Unwrap(err) != nil {
+		rootCause, _ := truncateMessage(maskMessage(err, Root(err).Error()))
+		addField(f, "root_cause", rootCause)
+	}
+	msg, truncated := truncateMessage(maskMessage(err, err.Error()))
+	if truncated {
+		f["truncated"] = true
+	}
+	if msg == "" {
+		msg = ReasonPhrase(status)
+	}
+	logger.Log(logLevel(ctx, err, status), msg, f)
+}
+
+// addField adds the field with the given key to f, unless value
+// is empty.
+func addField(f Fields, key, value string) {
+	if value != "" {
+		f[key] = value
+	}
+}
+
+// addStack adds the innermost stack trace recorded for err, if any,
+// to f.
+func addStack(f Fields, err error) {
+	if st := stackOf(err); len(st) > 0 {
+		f["stack"] = st.String()
 	}
 }
 
@@ -154,29 +584,86 @@ func HTTPError(w http.ResponseWriter, err error) {
 // Error replies to the request with the specified error message and HTTP code.
 // It does not otherwise end the request; the caller should ensure no further
 // writes are done to w.
-// The error message should be json.
-func sendError(w http.ResponseWriter, error string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(statusCode)
+// The error message should be json. It is sent with its Content-Length,
+// and compressed if the request of ctx accepts it (see GzipMinLength).
+func sendError(ctx context.Context, w http.ResponseWriter, error string, statusCode int) {
 	// Only write response body if there is an error string populated
-	if error != "" {
-		fmt.Fprintln(w, error)
+	if error == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(statusCode)
+		return
 	}
+	writeBody(ctx, w, statusCode, "application/json", []byte(error+"\n"))
 }
 
 // RE builds an HTTP Response error value from its arguments.
-// There must be at least one argument or RE panics.
+// If there are no arguments, RE does not panic, as it is usually
+// called in error paths, but returns an error of Kind Internal with
+// Code InvalidErrorConstruction, which is sent as an HTTP 500.
 // The type of each argument determines its meaning.
 // If more than one argument of a given type is presented,
 // only the last one is recorded.
 //
 // The types are:
+//
+//	int
+//		The HTTP status code of the response. If not given, the
+//		status code mapped to the Kind is used (see KindStatus).
+//		An unknown status code, such as a typo like 442, is
+//		logged and replaced by 500.
+//	errors.Kind
+//		The class of error, such as permission failure.
+//	string, errors.Code
+//		A human-readable, short representation of the error.
+//	errors.Op, []errors.Op
+//		The operations being performed, from the outermost to
+//		the innermost. They come first in the op chain of the
+//		error (see Ops).
+//	errors.Parameter
+//		The parameter related to the error.
+//	errors.Retry
+//		Marks the error as retryable, with the suggested delay
+//		before retrying. The delay is sent in a Retry-After header.
+//	errors.Severity
+//		The level at which the error is logged.
+//	errors.Elapsed, errors.Deadline
+//		The time spent by the operation before it failed, and its
+//		deadline, which are logged.
+//	errors.RateLimit
+//		The rate limit exceeded. The error is retryable and, unless
+//		given, its status code is 429, its Code is RateLimited and
+//		its message is generic. The rate limit is sent in the
+//		X-RateLimit-* headers (see HTTPError).
+//	errors.ResponseHeader
+//		A header sent with the response, e.g. WWW-Authenticate
+//		(see Header). Unlike the other types, all the headers
+//		given are recorded.
+//	errors.TemplateData
+//		The data of the message template of the Code, used when
+//		no message or error is given (see RegisterTemplate).
+//	errors.UserMsg
+//		The message sent to the client, instead of the message of
+//		the underlying error.
+//	errors.DevMsg
+//		A message for developers, logged before the message of
+//		the underlying error but never sent to the client.
+//	error
+//		The underlying error that triggered this one.
+//
+// If Kind, Code or Param is not specified, it is set to the one of the
+// outermost Error or HTTPErr in the chain of the underlying error
+// which has it, so the HTTPErr carries the full classification. Then,
+// if the Kind or the status code is still not specified, it is set to
+// the default of the Code (see RegisterCodeDefaults). As with E, the
+// underlying error is never changed.
 func RE(args ...interface{}) error {
 	if len(args) == 0 {
-		panic("call to errors.RE with no arguments")
+		args = []interface{}{Internal, Code("InvalidErrorConstruction"), Str("call to errors.RE with no arguments")}
 	}
-	e := &HTTPErr{}
+	e := newHTTPErr()
+	var rl *RateLimit
+	var data TemplateData
 	for _, arg := range args {
 		switch arg := arg.(type) {
 		case int:
@@ -187,8 +674,31 @@ func RE(args ...interface{}) error {
 			e.Code = Code(arg)
 		case Code:
 			e.Code = arg
+		case Op:
+			e.ops = append(e.ops, arg)
+		case []Op:
+			e.ops = append(e.ops, arg...)
 		case Parameter:
 			e.Param = arg
+		case Retry:
+			e.Retryable = true
+			e.RetryAfter = time.Duration(arg)
+		case Severity:
+			e.Severity = arg
+		case Elapsed:
+			e.Elapsed = time.Duration(arg)
+		case Deadline:
+			e.Deadline = time.Time(arg)
+		case RateLimit:
+			rl = &arg
+		case ResponseHeader:
+			e.addHeader(arg)
+		case TemplateData:
+			data = arg
+		case UserMsg:
+			e.UserMsg = string(arg)
+		case DevMsg:
+			e.DevMsg = string(arg)
 		case *Error:
 			// For API response errors, don't show full recursion details,
 			// just the error message
@@ -197,16 +707,60 @@ func RE(args ...interface{}) error {
 			e.Err = arg
 		default:
 			_, file, line, _ := runtime.Caller(1)
-			log.Error().Msgf("errors.E: bad call from %s:%d: %v", file, line, args)
+			// Log a copy, so args does not escape to the heap
+			logf(ErrorLevel, "errors.E: bad call from %s:%d: %v", file, line, append([]interface{}(nil), args...))
 			return Errorf("unknown type %T, value %v in error call", arg, arg)
 		}
 	}
+	if e.HTTPStatusCode != 0 && !validStatus(e.HTTPStatusCode) {
+		// A typo must not reach the clients
+		_, file, line, _ := runtime.Caller(1)
+		logf(WarnLevel, "errors.RE: unknown HTTP status code %d from %s:%d, %d sent instead", e.HTTPStatusCode, file, line, http.StatusInternalServerError)
+		e.HTTPStatusCode = http.StatusInternalServerError
+	}
+	if rl != nil {
+		e.setRateLimit(*rl)
+	}
+	// Inherit the classification of the wrapped error, if not given
+	if e.Err != nil {
+		if e.Kind == Other {
+			e.Kind = KindOf(e.Err)
+		}
+		if e.Code == "" {
+			e.Code = chainCode(e.Err)
+		}
+		if e.Param == "" {
+			e.Param = chainParam(e.Err)
+		}
+	}
+	if d, ok := defaultsOf(e.Code); ok {
+		if e.Kind == Other {
+			e.Kind = d.kind
+		}
+		if e.HTTPStatusCode == 0 {
+			e.HTTPStatusCode = d.status
+		}
+	}
+	if e.Err == nil {
+		if msg, ok := templateMessage(e.Code, e.Kind, e.Param, data); ok {
+			e.Err = Str(msg)
+		}
+	}
+	// Prefer the stack recorded when the wrapped error was built,
+	// as it is closer to where the error occurred
+	if e.trace = stackOf(e.Err); e.trace == nil {
+		e.trace = captureStack(1)
+	}
+	e.pc = captureLocation(0)
+	e.recordCreated()
 
 	return e
 }
 
 // StripStack takes an Error type (Error defined in this module) and
-// removes the leading stack information
+// removes the leading stack information. The returned error still
+// unwraps to the original Error, so the chain can be inspected with
+// errors.Is and errors.As.
 func StripStack(e error) error {
 	err, ok := e.(*Error)
 	if ok {
@@ -214,11 +768,32 @@ func StripStack(e error) error {
 		errStr := err.Error()
 		// get position where |: character lands in string
 		idx := strings.Index(errStr, "|:")
+		// if there is no |: character, there is no underlying
+		// error message to strip down to
+		if idx < 0 {
+			return e
+		}
 		// substring from after the |: character
 		substring := errStr[idx+3:]
 		// put substring back into error
-		return Str(substring)
+		return &strippedError{s: substring, err: err}
 	}
 	// If it's not an Error type, don't strip anything
 	return e
 }
+
+// strippedError is the error returned by StripStack and Redact. It
+// formats as the given message, but keeps the original error for
+// unwrapping.
+type strippedError struct {
+	s   string
+	err error
+}
+
+func (e *strippedError) Error() string {
+	return e.s
+}
+
+func (e *strippedError) Unwrap() error {
+	return e.err
+}
@@ -1,6 +1,13 @@
 package errors
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -53,3 +60,300 @@ func layer1() error {
 	const op Op = "errors/layer1"
 	return E(op, Validation, "Actual error message")
 }
+
+var errSentinel = Str("sentinel error")
+
+func TestRENoArguments(t *testing.T) {
+	e, ok := RE().(*HTTPErr)
+	if !ok {
+		t.Fatalf("RE() = %T; want *HTTPErr", RE())
+	}
+	if e.Kind != Internal || e.Code != "InvalidErrorConstruction" || e.Status() != http.StatusInternalServerError {
+		t.Errorf("RE() = %+v; want an Internal error with Code InvalidErrorConstruction", e)
+	}
+	if e.Location() == "" {
+		t.Error("RE() Location() is empty; want the caller of RE")
+	}
+}
+
+func TestNilReceivers(t *testing.T) {
+	var hse *HTTPErr
+	if hse.Timeout() || hse.Temporary() {
+		t.Error("nil *HTTPErr Timeout or Temporary do not return zero values")
+	}
+
+	var e *Error
+	if e.Error() != "no error" || e.Unwrap() != nil || e.Location() != "" || e.StackTrace() != nil || e.Timeout() || e.Temporary() {
+		t.Error("nil *Error accessors do not return zero values")
+	}
+	for _, m := range []json.Marshaler{hse, e} {
+		if b, err := m.MarshalJSON(); err != nil || string(b) != "null" {
+			t.Errorf("%T MarshalJSON() = %s, %v; want null", m, b, err)
+		}
+	}
+}
+
+func TestNilHTTPErr(t *testing.T) {
+	defer SetLogger(nil)
+	SetLogger(&testLogger{})
+	var err error = (*HTTPErr)(nil)
+	if k := KindOf(err); k != Other {
+		t.Errorf("KindOf() = %v; want %v", k, Other)
+	}
+	if Match(err, err) || Ops(err) != nil || CheckChain(err) != nil {
+		t.Error("Match, Ops or CheckChain of a nil *HTTPErr do not return zero values")
+	}
+	_ = Format(err)
+	_ = SeverityOf(err)
+
+	rr := httptest.NewRecorder()
+	HTTPError(rr, err)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusInternalServerError)
+	}
+	var got ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got.Error.Kind != Internal.String() || got.Error.Code != "InvalidErrorConstruction" {
+		t.Errorf("body = %+v; want an Internal error with Code InvalidErrorConstruction", got.Error)
+	}
+
+	// A nil *HTTPErr wrapped by another error is replaced too
+	for _, err := range []error{E(Op("users.Get"), err), fmt.Errorf("users.Get: %w", err)} {
+		rr := httptest.NewRecorder()
+		HTTPError(rr, err)
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d for %T; want %d", rr.Code, err, http.StatusInternalServerError)
+		}
+	}
+
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		var hse *HTTPErr
+		return hse
+	})
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Handler status = %d; want %d", rr.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestHTTPErrValue(t *testing.T) {
+	var err error = HTTPErr{HTTPStatusCode: http.StatusNotFound, Kind: NotExist, Severity: WarnSeverity, DevMsg: "no rows", Err: Str("no such user"), ops: []Op{"users.Get"}}
+	if _, ok := err.(hError); !ok {
+		t.Errorf("HTTPErr does not implement hError")
+	}
+	if err.Error() != "no rows: no such user" {
+		t.Errorf("Error() = %q; want %q", err.Error(), "no rows: no such user")
+	}
+	if _, se := serviceError(err); se == nil || se.Message != "no such user" {
+		t.Errorf("serviceError() = %+v; want the message of the wrapped error only", se)
+	}
+	if ops := Ops(err); len(ops) != 1 || ops[0] != "users.Get" {
+		t.Errorf("Ops() = %v; want [users.Get]", ops)
+	}
+	if s := SeverityOf(err); s != WarnSeverity {
+		t.Errorf("SeverityOf() = %v; want %v", s, WarnSeverity)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	const op Op = "errors/TestUnwrap"
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"Error", E(op, Validation, errSentinel)},
+		{"Nested Error", E(op, E(Op("errors/inner"), errSentinel))},
+		{"HTTPErr", RE(400, errSentinel)},
+		{"HTTPErr wrapping Error", RE(400, E(op, E(Op("errors/inner"), errSentinel)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !stderrors.Is(tt.err, errSentinel) {
+				t.Errorf("errors.Is(%q, errSentinel) = false; want true", tt.err)
+			}
+		})
+	}
+
+	var e *Error
+	if !stderrors.As(RE(400, E(op, Validation, "message")), &e) {
+		t.Fatal("errors.As(HTTPErr, *Error) = false; want true")
+	}
+	if e.Kind != Validation {
+		t.Errorf("Kind = %v; want %v", e.Kind, Validation)
+	}
+}
+
+func TestStripStack(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Error", E(Op("svc.Get"), NotExist, "no such item"), "no such item"},
+		{"No underlying error", E(Op("svc.Get"), NotExist), "svc.Get: item_does_not_exist"},
+		{"Op only", E(Op("x"), Other), "x"},
+		{"Not an Error", Str("plain"), "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// In debug mode, an Error with no underlying error
+			// still has its stack information as a prefix.
+			if got := StripStack(tt.err).Error(); !strings.HasSuffix(got, tt.want) {
+				t.Errorf("StripStack().Error() = %q; want suffix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorCtx(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	rr := httptest.NewRecorder()
+	HTTPErrorCtx(ctx, rr, RE(http.StatusNotFound, NotExist, Str("no such user")))
+
+	var er ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if er.Error.RequestID != "req-123" {
+		t.Errorf("RequestID = %q; want %q", er.Error.RequestID, "req-123")
+	}
+	if len(tl.entries) != 1 || tl.entries[0].fields["request_id"] != "req-123" {
+		t.Errorf("log entries = %v; want request_id field", tl.entries)
+	}
+
+	// A custom extractor
+	defer func(prev func(context.Context) string) {
+		RequestIDFunc = prev
+	}(RequestIDFunc)
+	type traceKey struct{}
+	RequestIDFunc = func(ctx context.Context) string {
+		id, _ := ctx.Value(traceKey{}).(string)
+		return id
+	}
+	rr = httptest.NewRecorder()
+	HTTPErrorCtx(context.WithValue(context.Background(), traceKey{}, "trace-456"), rr, Str("some error"))
+	if !strings.Contains(rr.Body.String(), `"request_id": "trace-456"`) {
+		t.Errorf("body = %s; want request_id trace-456", rr.Body.String())
+	}
+}
+
+func TestResponseIndent(t *testing.T) {
+	defer func() { ResponseIndent = "    " }()
+	err := RE(http.StatusNotFound, NotExist, Code("no_user"), Str("user not found"))
+
+	tests := []struct {
+		name   string
+		indent string
+		want   string
+	}{
+		{"Indented", "    ", "{\n    \"error\": {\n        \"kind\": \"item_does_not_exist\","},
+		{"Compact", "", `{"error":{"kind":"item_does_not_exist","code":"no_user","message":"user not found"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResponseIndent = tt.indent
+			rr := httptest.NewRecorder()
+			HTTPError(rr, err)
+			if got := rr.Body.String(); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("body = %q; want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalFailure(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+	defer func() { ProblemDetails = false }()
+
+	// A channel has no JSON encoding
+	err := ValidationErrors{Param("ch", make(chan int), "a value")}
+	tests := []struct {
+		name            string
+		problem         bool
+		ctx             context.Context
+		wantContentType string
+		wantBody        string
+	}{
+		{"ErrResponse", false, context.Background(), "application/json", staticResponse},
+		{"ResponseV2", false, WithResponseVersion(context.Background(), ResponseV2), "application/json; version=2", `{"status":400,"message":"Bad Request","fields":[]}`},
+		{"Problem", true, context.Background(), "application/problem+json", `{"type":"about:blank","title":"Bad Request","status":400}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ProblemDetails = tt.problem
+			tl.entries = nil
+			w := httptest.NewRecorder()
+			HTTPErrorCtx(tt.ctx, w, err)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d; want %d", w.Code, http.StatusBadRequest)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q; want %q", got, tt.wantContentType)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+				t.Errorf("body = %s; want %s", got, tt.wantBody)
+			}
+			if !json.Valid(w.Body.Bytes()) {
+				t.Errorf("body %s is not valid JSON", w.Body.String())
+			}
+			last := tl.entries[len(tl.entries)-1]
+			if last.level != ErrorLevel || last.fields["error"] == nil {
+				t.Errorf("last log entry = %+v; want the marshal failure", last)
+			}
+		})
+	}
+}
+
+func TestUserDevMsg(t *testing.T) {
+	defer SetLogger(nil)
+	cause := Str("pq: connection refused")
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+		wantLog string
+	}{
+		{"Both", RE(http.StatusServiceUnavailable, IO, UserMsg("try again later"), DevMsg("users db down"), cause),
+			"try again later", "users db down: pq: connection refused"},
+		{"UserMsg", RE(http.StatusServiceUnavailable, IO, UserMsg("try again later"), cause),
+			"try again later", "pq: connection refused"},
+		{"DevMsg", RE(http.StatusServiceUnavailable, IO, DevMsg("users db down"), cause),
+			"pq: connection refused", "users db down: pq: connection refused"},
+		{"DevMsg only", RE(http.StatusServiceUnavailable, IO, DevMsg("users db down")),
+			"", "users db down"},
+		{"Builder", NewHTTP().Kind(IO).UserMsg("try again later").DevMsg("users db down").Wrap(cause).Err(),
+			"try again later", "users db down: pq: connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Message != tt.wantMsg {
+				t.Errorf("Message = %q; want %q", er.Error.Message, tt.wantMsg)
+			}
+			if strings.Contains(rr.Body.String(), "users db down") {
+				t.Errorf("body = %s; want no DevMsg", rr.Body.String())
+			}
+			if len(tl.entries) != 1 || tl.entries[0].msg != tt.wantLog {
+				t.Errorf("log entries = %v; want message %q", tl.entries, tt.wantLog)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJoined(t *testing.T) {
+	validation := RE(http.StatusBadRequest, Validation, Code("NameRequired"), Parameter("name"), Str("name is required"))
+	conflict := RE(http.StatusConflict, Exist, Code("UserExists"), Str("user exists"))
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   Kind
+		wantCode   Code
+		wantStatus int
+	}{
+		{"Join", stderrors.Join(validation, conflict), Exist, "UserExists", http.StatusConflict},
+		{"Join with nil", stderrors.Join(nil, validation), Validation, "NameRequired", http.StatusBadRequest},
+		{"Wrapped join", E(Op("svc.Create"), stderrors.Join(validation, conflict)), Exist, "UserExists", http.StatusConflict},
+		{"Unclassified", stderrors.Join(Str("a"), Str("b")), Unanticipated, "Unanticipated", http.StatusInternalServerError},
+		{"ValidationErrors", ValidationErrors{validation}, Validation, "NameRequired", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.wantKind {
+				t.Errorf("KindOf() = %v; want %v", got, tt.wantKind)
+			}
+			if tt.wantCode != "Unanticipated" {
+				if got := CodeOf(tt.err); got != tt.wantCode {
+					t.Errorf("CodeOf() = %q; want %q", got, tt.wantCode)
+				}
+			}
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("HTTPError() status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMatchJoined(t *testing.T) {
+	err := E(Op("svc.Create"), stderrors.Join(
+		E(Op("db.Insert"), Database, Str("connection lost")),
+		RE(http.StatusConflict, Exist, Code("UserExists"), Str("user exists")),
+	))
+	tests := []struct {
+		name     string
+		template error
+		want     bool
+	}{
+		{"First member", E(Database), true},
+		{"Second member", &HTTPErr{Code: "UserExists"}, true},
+		{"No member", E(Permission), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.template, err); got != tt.want {
+				t.Errorf("Match() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorsUnwrap(t *testing.T) {
+	required := MissingField("name")
+	ve := ValidationErrors{required}
+	if !stderrors.Is(ve, required) {
+		t.Error("errors.Is(ValidationErrors, member) = false; want true")
+	}
+}
@@ -0,0 +1,279 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// jsonError is the JSON representation of an Error or an HTTPErr. The
+// Kind is encoded by name, so the JSON is readable in audit logs. Only
+// an HTTPErr has a status. The underlying error is encoded as a nested
+// object if it is an Error or an HTTPErr, and by its message otherwise.
+type jsonError struct {
+	Path       string      `json:"path,omitempty"`
+	User       string      `json:"user,omitempty"`
+	Op         string      `json:"op,omitempty"`
+	Ops        []string    `json:"ops,omitempty"`
+	Status     int         `json:"status,omitempty"`
+	Kind       string      `json:"kind,omitempty"`
+	Param      string      `json:"param,omitempty"`
+	Code       string      `json:"code,omitempty"`
+	Retryable  bool        `json:"retryable,omitempty"`
+	RetryAfter string      `json:"retry_after,omitempty"`
+	Severity   string      `json:"severity,omitempty"`
+	Elapsed    string      `json:"elapsed,omitempty"`
+	Deadline   *time.Time  `json:"deadline,omitempty"`
+	RateLimit  *RateLimit  `json:"rate_limit,omitempty"`
+	Headers    http.Header `json:"headers,omitempty"`
+	UserMsg    string      `json:"user_msg,omitempty"`
+	DevMsg     string      `json:"dev_msg,omitempty"`
+	Err        *jsonError  `json:"err,omitempty"`
+	Message    string      `json:"message,omitempty"`
+}
+
+// MarshalJSON encodes the Error as JSON, so it can be persisted, e.g.
+// to a job queue or an audit log, and restored with UnmarshalJSON with
+// its Path, User, Op, Kind, Param and Code intact, as well as its
+// elapsed time and deadline, if any. Underlying errors which are not an
+// *Error or an *HTTPErr are only encoded by their message. The stack
+// trace is not encoded.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(toJSONError(e))
+}
+
+// UnmarshalJSON decodes JSON encoded by MarshalJSON into the receiver,
+// which must be non-nil.
+func (e *Error) UnmarshalJSON(b []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(b, &je); err != nil {
+		return err
+	}
+	*e = *je.toError()
+	return nil
+}
+
+// MarshalJSON encodes the HTTPErr as JSON, as (*Error).MarshalJSON
+// does, with its HTTP status code.
+func (hse *HTTPErr) MarshalJSON() ([]byte, error) {
+	if hse == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(toJSONError(hse))
+}
+
+// UnmarshalJSON decodes JSON encoded by MarshalJSON into the receiver,
+// which must be non-nil. As is done by RE, an underlying *Error is
+// restored without its leading Op and Kind information in the message
+// (see StripStack).
+func (hse *HTTPErr) UnmarshalJSON(b []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(b, &je); err != nil {
+		return err
+	}
+	*hse = *je.httpErr()
+	return nil
+}
+
+// toJSONError returns the JSON representation of err.
+func toJSONError(err error) *jsonError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *strippedError:
+		return toJSONError(e.err)
+	case *Error:
+		je := &jsonError{
+			Path:      string(e.Path),
+			User:      string(e.User),
+			Param:     string(e.Param),
+			Code:      string(e.Code),
+			Retryable: e.Retryable,
+			Err:       toJSONError(e.Err),
+		}
+		je.setOps(e.opChain())
+		je.setKind(e.Kind)
+		je.setRetryAfter(e.RetryAfter)
+		je.setSeverity(e.Severity)
+		je.setTiming(e.Elapsed, e.Deadline)
+		je.maskCause(e.Param)
+		return je
+	case *HTTPErr:
+		je := &jsonError{
+			Status:    e.Status(),
+			Param:     string(e.Param),
+			Code:      string(e.Code),
+			Retryable: e.Retryable,
+			RateLimit: e.RateLimit,
+			Headers:   e.Headers,
+			UserMsg:   e.UserMsg,
+			DevMsg:    e.DevMsg,
+			Err:       toJSONError(e.Err),
+		}
+		je.setOps(e.ops)
+		je.setKind(e.Kind)
+		je.setRetryAfter(e.RetryAfter)
+		je.setSeverity(e.Severity)
+		je.setTiming(e.Elapsed, e.Deadline)
+		je.maskCause(e.Param)
+		return je
+	}
+	return &jsonError{Message: maskPairs(err.Error())}
+}
+
+// maskCause masks the message of the cause of je, the encoding of an
+// error for param, if param is one of the MaskedParams.
+func (je *jsonError) maskCause(param Parameter) {
+	if !masked(param) {
+		return
+	}
+	if je.Err != nil && je.Err.isMessage() && je.Err.Message != "" {
+		je.Err.Message = Redaction.mask()
+	}
+}
+
+// setOps sets the op of je to the first of ops, and the ops of je
+// to the others.
+func (je *jsonError) setOps(ops []Op) {
+	for i, op := range ops {
+		if i == 0 {
+			je.Op = string(op)
+			continue
+		}
+		je.Ops = append(je.Ops, string(op))
+	}
+}
+
+// ops returns the op and the ops of je, or nil if it has no op.
+func (je *jsonError) ops() []Op {
+	if je.Op == "" {
+		return nil
+	}
+	ops := []Op{Op(je.Op)}
+	for _, op := range je.Ops {
+		ops = append(ops, Op(op))
+	}
+	return ops
+}
+
+func (je *jsonError) setKind(k Kind) {
+	if k != Other {
+		je.Kind = k.String()
+	}
+}
+
+func (je *jsonError) setRetryAfter(d time.Duration) {
+	if d > 0 {
+		je.RetryAfter = d.String()
+	}
+}
+
+func (je *jsonError) setSeverity(s Severity) {
+	if s != DefaultSeverity {
+		je.Severity = s.String()
+	}
+}
+
+func (je *jsonError) setTiming(elapsed time.Duration, deadline time.Time) {
+	if elapsed > 0 {
+		je.Elapsed = elapsed.String()
+	}
+	if !deadline.IsZero() {
+		je.Deadline = &deadline
+	}
+}
+
+func (je *jsonError) elapsed() time.Duration {
+	d, _ := time.ParseDuration(je.Elapsed)
+	return d
+}
+
+func (je *jsonError) deadline() time.Time {
+	if je.Deadline == nil {
+		return time.Time{}
+	}
+	return *je.Deadline
+}
+
+func (je *jsonError) retryAfter() time.Duration {
+	d, _ := time.ParseDuration(je.RetryAfter)
+	return d
+}
+
+// isMessage reports whether je only has a message, i.e. whether it
+// represents an error which is not an Error or an HTTPErr.
+func (je *jsonError) isMessage() bool {
+	m := *je
+	m.Message = ""
+	return reflect.ValueOf(m).IsZero()
+}
+
+// err returns the error represented by je, or nil if je is nil.
+func (je *jsonError) err() error {
+	switch {
+	case je == nil:
+		return nil
+	case je.Status != 0:
+		return je.httpErr()
+	case je.isMessage():
+		// An error which is not an Error or an HTTPErr
+		return Str(je.Message)
+	}
+	return je.toError()
+}
+
+// toError returns the Error represented by je.
+func (je *jsonError) toError() *Error {
+	e := &Error{
+		Path:       PathName(je.Path),
+		User:       UserName(je.User),
+		Kind:       kindFromString(je.Kind),
+		Param:      Parameter(je.Param),
+		Code:       Code(je.Code),
+		Retryable:  je.Retryable,
+		RetryAfter: je.retryAfter(),
+		Severity:   severityFromString(je.Severity),
+		Elapsed:    je.elapsed(),
+		Deadline:   je.deadline(),
+		Err:        je.Err.err(),
+	}
+	for _, op := range je.ops() {
+		e.addOp(op)
+	}
+	if e.Err == nil && je.Message != "" {
+		e.Err = Str(je.Message)
+	}
+	return e
+}
+
+// httpErr returns the HTTPErr represented by je.
+func (je *jsonError) httpErr() *HTTPErr {
+	hse := &HTTPErr{
+		HTTPStatusCode: je.Status,
+		Kind:           kindFromString(je.Kind),
+		Param:          Parameter(je.Param),
+		Code:           Code(je.Code),
+		Retryable:      je.Retryable,
+		RetryAfter:     je.retryAfter(),
+		Severity:       severityFromString(je.Severity),
+		Elapsed:        je.elapsed(),
+		Deadline:       je.deadline(),
+		RateLimit:      je.RateLimit,
+		Headers:        je.Headers,
+		UserMsg:        je.UserMsg,
+		DevMsg:         je.DevMsg,
+		Err:            je.Err.err(),
+		ops:            je.ops(),
+	}
+	if e, ok := hse.Err.(*Error); ok {
+		hse.Err = StripStack(e)
+	}
+	if hse.Err == nil && je.Message != "" {
+		hse.Err = Str(je.Message)
+	}
+	return hse
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestErrorJSON(t *testing.T) {
+	inner := E(Op("db.Exec"), Database, Code("db_down"), "connection reset")
+	in := E(Op("service.Insert"), PathName("jane@doe.com/file"), UserName("joe@blow.com"), Parameter("id"), Retry(2*time.Second), inner).(*Error)
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var out Error
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if out.Path != in.Path || out.User != in.User || out.Op != in.Op || out.Kind != in.Kind || out.Param != in.Param {
+		t.Errorf("Unmarshal() = %+v; want %+v", out, *in)
+	}
+	if !out.Retryable || out.RetryAfter != 2*time.Second {
+		t.Errorf("Retryable, RetryAfter = %t, %v; want true, 2s", out.Retryable, out.RetryAfter)
+	}
+	oi, ok := out.Err.(*Error)
+	if !ok {
+		t.Fatalf("Err = %T; want *Error", out.Err)
+	}
+	if oi.Op != "db.Exec" || oi.Code != "db_down" {
+		t.Errorf("Err = %+v; want Op db.Exec and Code db_down", oi)
+	}
+	if oi.Err == nil || oi.Err.Error() != "connection reset" {
+		t.Errorf("Err.Err = %v; want %q", oi.Err, "connection reset")
+	}
+}
+
+func TestHTTPErrJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *HTTPErr
+	}{
+		{"Message", RE(http.StatusConflict, Exist, Code("user_exists"), Parameter("email"), Str("user exists")).(*HTTPErr)},
+		{"Error", RE(http.StatusNotFound, E(Op("service.Get"), NotExist, "no such user")).(*HTTPErr)},
+		{"Status Only", RE(http.StatusUnauthorized).(*HTTPErr)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var out HTTPErr
+			if err := json.Unmarshal(b, &out); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if out.Status() != tt.in.Status() || out.Kind != tt.in.Kind || out.Code != tt.in.Code || out.Param != tt.in.Param {
+				t.Errorf("Unmarshal() = %+v; want %+v", out, *tt.in)
+			}
+			if out.Error() != tt.in.Error() {
+				t.Errorf("Error() = %q; want %q", out.Error(), tt.in.Error())
+			}
+			if out.StatusOnly() != tt.in.StatusOnly() {
+				t.Errorf("StatusOnly() = %t; want %t", out.StatusOnly(), tt.in.StatusOnly())
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/gilcrest/errors/kafkaerrors
+
+go 1.21
+
+require (
+	github.com/gilcrest/errors v0.0.0
+	github.com/segmentio/kafka-go v0.4.47
+)
+
+require (
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/rs/zerolog v1.14.0 // indirect
+)
+
+replace github.com/gilcrest/errors => ../
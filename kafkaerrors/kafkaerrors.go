@@ -0,0 +1,75 @@
+// Package kafkaerrors sends errors built with the errors package in the
+// headers of Kafka messages of github.com/segmentio/kafka-go, e.g. of
+// the messages sent to a dead-letter topic after their processing
+// failed, so consumers get the same Kind, Code and status code as
+// HTTP clients (see errors.EventError):
+//
+//	if err := process(ctx, msg); err != nil {
+//		dlq.WriteMessages(ctx, kafkaerrors.DeadLetter(msg, err))
+//	}
+//
+// and, in the consumer of the dead-letter topic:
+//
+//	err := kafkaerrors.ErrorOf(msg)
+package kafkaerrors
+
+import (
+	"github.com/gilcrest/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+// HeaderCarrier is the errors.HeaderCarrier of the headers of a Kafka
+// message.
+type HeaderCarrier struct {
+	msg *kafka.Message
+}
+
+// Carrier returns the HeaderCarrier of the headers of msg.
+func Carrier(msg *kafka.Message) HeaderCarrier {
+	return HeaderCarrier{msg: msg}
+}
+
+// Get returns the value of the first header of the message named key,
+// or "" if there is none.
+func (c HeaderCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set sets the header of the message named key to value, replacing the
+// headers of the same name.
+func (c HeaderCarrier) Set(key, value string) {
+	headers := c.msg.Headers[:0:0]
+	for _, h := range c.msg.Headers {
+		if h.Key != key {
+			headers = append(headers, h)
+		}
+	}
+	c.msg.Headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// DeadLetter returns a copy of msg for a dead-letter topic, with the
+// EventError of err injected in its headers. The key, value and
+// headers of msg are kept, but not its topic, partition and offset, so
+// it can be sent by a kafka.Writer of the dead-letter topic.
+func DeadLetter(msg kafka.Message, err error) kafka.Message {
+	dl := kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: append([]kafka.Header(nil), msg.Headers...),
+	}
+	if ee := errors.NewEventError(err); ee != nil {
+		ee.Inject(Carrier(&dl))
+	}
+	return dl
+}
+
+// ErrorOf returns the error injected in the headers of msg, e.g. by
+// DeadLetter, or nil if there is none (see errors.ExtractEventError).
+func ErrorOf(msg kafka.Message) error {
+	return errors.ExtractEventError(Carrier(&msg)).Err()
+}
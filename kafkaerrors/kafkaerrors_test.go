@@ -0,0 +1,63 @@
+package kafkaerrors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gilcrest/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestDeadLetter(t *testing.T) {
+	msg := kafka.Message{
+		Topic:     "orders",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("order-1"),
+		Value:     []byte(`{"id":"order-1"}`),
+		Headers:   []kafka.Header{{Key: "trace-id", Value: []byte("abc")}},
+	}
+	err := errors.RE(http.StatusServiceUnavailable, errors.Op("orders.Process"), errors.IO, errors.Code("Unavailable"),
+		errors.Retry(30*time.Second), errors.Str("payment service unavailable"))
+
+	dl := DeadLetter(msg, err)
+	if dl.Topic != "" || dl.Partition != 0 || dl.Offset != 0 {
+		t.Errorf("DeadLetter() topic, partition, offset = %q, %d, %d; want them unset", dl.Topic, dl.Partition, dl.Offset)
+	}
+	if string(dl.Key) != "order-1" || string(dl.Value) != `{"id":"order-1"}` {
+		t.Errorf("DeadLetter() key, value = %q, %q; want those of the message", dl.Key, dl.Value)
+	}
+	if got := Carrier(&dl).Get("trace-id"); got != "abc" {
+		t.Errorf("header trace-id = %q; want %q", got, "abc")
+	}
+	if got := Carrier(&dl).Get("error-code"); got != "Unavailable" {
+		t.Errorf("header error-code = %q; want %q", got, "Unavailable")
+	}
+	if len(msg.Headers) != 1 {
+		t.Errorf("headers of the message = %v; want them unchanged", msg.Headers)
+	}
+
+	got := ErrorOf(dl)
+	if !errors.Equal(got, err) {
+		t.Errorf("ErrorOf() = %v; want an error Equal to %v", got, err)
+	}
+	if errors.StatusOf(got) != http.StatusServiceUnavailable || errors.RetryDelay(got) != 30*time.Second {
+		t.Errorf("ErrorOf() = %#v; want a 503 retryable after 30s", got)
+	}
+}
+
+func TestErrorOfNone(t *testing.T) {
+	if err := ErrorOf(kafka.Message{Value: []byte("ok")}); err != nil {
+		t.Errorf("ErrorOf() = %v; want nil", err)
+	}
+}
+
+func TestHeaderCarrierSet(t *testing.T) {
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "error-kind", Value: []byte("io_error")}}}
+	c := Carrier(&msg)
+	c.Set("error-kind", "database_error")
+	if len(msg.Headers) != 1 || c.Get("error-kind") != "database_error" {
+		t.Errorf("headers = %v; want error-kind replaced", msg.Headers)
+	}
+}
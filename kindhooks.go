@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// KindHook is called by HTTPError for the errors of the Kind it was
+// registered for with OnKind. r is the request the error is sent for,
+// or nil if it is not known.
+type KindHook func(w http.ResponseWriter, r *http.Request, err error)
+
+var (
+	kindHookMu sync.RWMutex
+	// kindHooks maps Kinds to the hooks registered for them.
+	kindHooks = map[Kind][]KindHook{}
+)
+
+// OnKind registers hook to be called when HTTPError sends an error of
+// the given Kind, as reported in the response, e.g. to clear a session
+// cookie on Unauthorized. Hooks are called in the order they were
+// registered, before the response is written, so they can set headers
+// and cookies. They are not called if the response was already started
+// (see TrackWrites). The request is known when the context given to
+// HTTPErrorCtx comes from RequestContext, as with HandlerFunc. OnKind
+// should be called at program start, before any errors are sent.
+func OnKind(kind Kind, hook KindHook) {
+	kindHookMu.Lock()
+	defer kindHookMu.Unlock()
+	kindHooks[kind] = append(kindHooks[kind], hook)
+}
+
+// runKindHooks calls the hooks registered for the Kind of err.
+func runKindHooks(ctx context.Context, w http.ResponseWriter, err error) {
+	kind, _ := classify(err)
+	kindHookMu.RLock()
+	hooks := kindHooks[kind]
+	kindHookMu.RUnlock()
+	if len(hooks) == 0 {
+		return
+	}
+	r, _ := ctx.Value(requestKey).(*http.Request)
+	for _, hook := range hooks {
+		hook(w, r, err)
+	}
+}
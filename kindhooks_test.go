@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOnKind(t *testing.T) {
+	defer func() { kindHooks = map[Kind][]KindHook{} }()
+
+	var calls []string
+	OnKind(Unauthorized, func(w http.ResponseWriter, r *http.Request, err error) {
+		if r == nil {
+			t.Error("hook called with a nil request")
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", MaxAge: -1})
+		calls = append(calls, "clear session")
+	})
+	OnKind(Unauthorized, func(w http.ResponseWriter, r *http.Request, err error) {
+		calls = append(calls, "second")
+	})
+
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{"Unauthorized", RE(http.StatusUnauthorized, Unauthorized, Str("session expired")), 2},
+		{"Other Kind", RE(http.StatusNotFound, NotExist, Str("not found")), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error { return tt.err })
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("hooks called %d times; want %d", len(calls), tt.wantCalls)
+			}
+			if tt.wantCalls > 0 {
+				if calls[0] != "clear session" {
+					t.Errorf("first hook = %q; want %q", calls[0], "clear session")
+				}
+				if got := rr.Header().Get("Set-Cookie"); got == "" {
+					t.Error("Set-Cookie header not sent")
+				}
+			}
+		})
+	}
+}
+
+func TestOnKindNoRequest(t *testing.T) {
+	defer func() { kindHooks = map[Kind][]KindHook{} }()
+
+	called := false
+	OnKind(NotExist, func(w http.ResponseWriter, r *http.Request, err error) {
+		called = true
+		if r != nil {
+			t.Errorf("request = %v; want nil", r)
+		}
+	})
+	HTTPError(httptest.NewRecorder(), RE(http.StatusNotFound, NotExist, Str("not found")))
+	if !called {
+		t.Error("hook not called")
+	}
+}
@@ -0,0 +1,111 @@
+package errors
+
+// KindOf returns the Kind of err, i.e. the Kind of the outermost Error
+// or HTTPErr in its chain with a Kind other than Other, or of the
+// outermost GroupError, BatchError or ValidationErrors. Errors joined
+// with errors.Join have the Kind of a GroupError of their members. If
+// there is none, or if err is nil, it returns Other.
+func KindOf(err error) Kind {
+	for _, err := range chainOf(err) {
+		switch e := err.(type) {
+		case *Error:
+			if e.Kind != Other {
+				return e.Kind
+			}
+		case *HTTPErr:
+			if e.Kind != Other {
+				return e.Kind
+			}
+		case *GroupError:
+			return e.Kind()
+		case *BatchError:
+			return e.Kind()
+		case ValidationErrors:
+			return Validation
+		case interface{ Unwrap() []error }:
+			return (&GroupError{Errors: e.Unwrap()}).Kind()
+		}
+	}
+	return Other
+}
+
+// Root returns the innermost error of the chain of err, i.e. the
+// original cause of err, however many times it was wrapped. If err
+// does not wrap another error, Root returns err.
+func Root(err error) error {
+	chain := chainOf(err)
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain[len(chain)-1]
+}
+
+// RootKind returns the Kind of the innermost Error or HTTPErr in the
+// chain of err with a Kind other than Other, i.e. the Kind given where
+// the error originated. If there is none, or if err is nil, it returns
+// Other. Unlike KindOf, it is not changed by the Kinds of the errors
+// which wrap it.
+func RootKind(err error) Kind {
+	k := Other
+	for _, err := range chainOf(err) {
+		switch e := err.(type) {
+		case *Error:
+			if e.Kind != Other {
+				k = e.Kind
+			}
+		case *HTTPErr:
+			if e.Kind != Other {
+				k = e.Kind
+			}
+		}
+	}
+	return k
+}
+
+// CodeOf returns the Code of err, i.e. the Code of the outermost Error
+// or HTTPErr in its chain which has one. If there is none, or if err
+// is nil, it returns "".
+func CodeOf(err error) Code {
+	return chainCode(err)
+}
+
+// chainCode returns the Code of the outermost Error or HTTPErr in the
+// chain of err which has one, or "" if there is none. The Code of
+// joined errors is the one of their most severe member (see
+// SetKindPrecedence).
+func chainCode(err error) Code {
+	var v Code
+	eachOf(err, func(err error) bool {
+		switch e := err.(type) {
+		case *Error:
+			v = e.Code
+		case *HTTPErr:
+			v = e.Code
+		case interface{ Unwrap() []error }:
+			v = chainCode(mostSevere(e.Unwrap()))
+			return false
+		}
+		return v == ""
+	})
+	return v
+}
+
+// chainParam returns the Param of the outermost Error or HTTPErr in the
+// chain of err which has one, or "" if there is none. As for chainCode,
+// the Param of joined errors is the one of their most severe member.
+func chainParam(err error) Parameter {
+	var v Parameter
+	eachOf(err, func(err error) bool {
+		switch e := err.(type) {
+		case *Error:
+			v = e.Param
+		case *HTTPErr:
+			v = e.Param
+		case interface{ Unwrap() []error }:
+			v = chainParam(mostSevere(e.Unwrap()))
+			return false
+		}
+		return v == ""
+	})
+	return v
+}
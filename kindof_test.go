@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"Error", E(Op("db.Get"), NotExist), NotExist},
+		{"Pulled up", E(Op("service.Get"), E(Op("db.Get"), NotExist)), NotExist},
+		{"HTTPErr", RE(http.StatusConflict, Exist), Exist},
+		{"Wrapped", fmt.Errorf("wrapped: %w", E(Op("db.Get"), Permission)), Permission},
+		{"No Kind", E(Op("db.Get"), Str("some error")), Other},
+		{"Other error", Str("some error"), Other},
+		{"nil", nil, Other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"Error", E(Op("db.Get"), Code("no_user")), "no_user"},
+		{"HTTPErr", RE(http.StatusConflict, Code("duplicate")), "duplicate"},
+		{"Wrapped", fmt.Errorf("wrapped: %w", E(Op("db.Get"), Code("no_user"))), "no_user"},
+		{"No Code", E(Op("db.Get"), NotExist), ""},
+		{"nil", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInheritClassification(t *testing.T) {
+	inner := E(Op("db.Get"), NotExist, Code("no_user"), Parameter("id"), Str("no rows"))
+
+	e := E(Op("service.Get"), inner).(*Error)
+	if e.Kind != NotExist || e.Code != "no_user" || e.Param != "id" {
+		t.Errorf("E() Kind, Code, Param = %v, %q, %q; want item_does_not_exist, no_user, id", e.Kind, e.Code, e.Param)
+	}
+	e = E(Op("service.Get"), Code("lookup_failed"), inner).(*Error)
+	if e.Code != "lookup_failed" || e.Param != "id" {
+		t.Errorf("E() Code, Param = %q, %q; want lookup_failed, id", e.Code, e.Param)
+	}
+
+	hse := RE(inner).(*HTTPErr)
+	if hse.Kind != NotExist || hse.Code != "no_user" || hse.Param != "id" {
+		t.Errorf("RE() Kind, Code, Param = %v, %q, %q; want item_does_not_exist, no_user, id", hse.Kind, hse.Code, hse.Param)
+	}
+	if hse.Status() != http.StatusNotFound {
+		t.Errorf("RE() Status() = %d; want %d", hse.Status(), http.StatusNotFound)
+	}
+	hse = RE(http.StatusBadRequest, Validation, inner).(*HTTPErr)
+	if hse.Kind != Validation || hse.Status() != http.StatusBadRequest {
+		t.Errorf("RE() Kind, Status() = %v, %d; want input_validation_error, 400", hse.Kind, hse.Status())
+	}
+}
+
+func TestRoot(t *testing.T) {
+	cause := io.ErrUnexpectedEOF
+	tests := []struct {
+		name     string
+		err      error
+		wantErr  error
+		wantKind Kind
+	}{
+		{"Not wrapped", cause, cause, Other},
+		{"Error", E(Op("db.Get"), Database, cause), cause, Database},
+		{
+			"Layers",
+			RE(http.StatusServiceUnavailable, IO, E(Op("users.Get"), E(Op("users.load"), E(Op("db.Get"), Database, cause)))),
+			cause,
+			Database,
+		},
+		{"Wrapped", fmt.Errorf("wrapped: %w", E(Op("db.Get"), NotExist, cause)), cause, NotExist},
+		{"nil", nil, nil, Other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Root(tt.err); got != tt.wantErr {
+				t.Errorf("Root() = %v; want %v", got, tt.wantErr)
+			}
+			if got := RootKind(tt.err); got != tt.wantKind {
+				t.Errorf("RootKind() = %v; want %v", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestRootCauseLogged(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	HTTPError(httptest.NewRecorder(), RE(http.StatusNotFound, E(Op("users.Get"), E(Op("db.Get"), NotExist, io.ErrUnexpectedEOF))))
+	if got := tl.entries[0].fields["root_cause"]; got != io.ErrUnexpectedEOF.Error() {
+		t.Errorf("fields[root_cause] = %v; want %q", got, io.ErrUnexpectedEOF.Error())
+	}
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+)
+
+// firstCustomKind is the value of the first Kind registered with
+// RegisterKind. The values below it are reserved for the Kinds of
+// this package.
+const firstCustomKind Kind = 128
+
+var (
+	kindMu sync.RWMutex
+	// kindNames maps each Kind registered with RegisterKind to its
+	// name, and kindsByName the names to the Kinds.
+	kindNames   = map[Kind]string{}
+	kindsByName = map[string]Kind{}
+	// nextKind is the Kind returned by the next call to RegisterKind.
+	nextKind = firstCustomKind
+)
+
+// RegisterKind registers a Kind of error of the application, such as
+// a payment failure, with its name, which is returned by its String
+// method, and the HTTP status code it is mapped to (see KindStatus),
+// and returns it, so Kinds can be declared as variables:
+//
+//	var PaymentRequired = errors.RegisterKind("payment_required", http.StatusPaymentRequired)
+//
+// RegisterKind panics if name is empty or is the name of another Kind,
+// or if too many Kinds were registered (128). It is meant to be called
+// during program initialization. As the values of registered Kinds
+// depend on the order of registration, they should not be persisted;
+// the JSON encoding of errors uses the name of their Kind.
+func RegisterKind(name string, status int) Kind {
+	if name == "" {
+		panic("errors: RegisterKind called with an empty name")
+	}
+	if k := kindFromString(name); k != Other || name == Other.String() {
+		panic(fmt.Sprintf("errors: Kind %q registered twice", name))
+	}
+	kindMu.Lock()
+	// The name is looked up again with the lock held, in case the
+	// Kind is being registered concurrently
+	if _, ok := kindsByName[name]; ok {
+		kindMu.Unlock()
+		panic(fmt.Sprintf("errors: Kind %q registered twice", name))
+	}
+	if nextKind == 0 {
+		kindMu.Unlock()
+		panic("errors: too many Kinds registered")
+	}
+	k := nextKind
+	nextKind++ // wraps to 0 after the last Kind
+	kindNames[k] = name
+	kindsByName[name] = k
+	kindMu.Unlock()
+
+	RegisterStatusMapping(k, status)
+	return k
+}
+
+// registeredKindName returns the name of the registered Kind k, if it
+// was registered with RegisterKind.
+func registeredKindName(k Kind) (string, bool) {
+	kindMu.RLock()
+	defer kindMu.RUnlock()
+	name, ok := kindNames[k]
+	return name, ok
+}
+
+// registeredKind returns the Kind registered with RegisterKind with the
+// given name, if any.
+func registeredKind(name string) (Kind, bool) {
+	kindMu.RLock()
+	defer kindMu.RUnlock()
+	k, ok := kindsByName[name]
+	return k, ok
+}
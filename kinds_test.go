@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegisterKind(t *testing.T) {
+	defer func() {
+		kindMu.Lock()
+		for k := range kindNames {
+			statusMu.Lock()
+			delete(kindStatus, k)
+			statusMu.Unlock()
+		}
+		kindNames = map[Kind]string{}
+		kindsByName = map[string]Kind{}
+		nextKind = firstCustomKind
+		kindMu.Unlock()
+	}()
+
+	conflict := RegisterKind("conflict", http.StatusConflict)
+	gone := RegisterKind("gone", http.StatusGone)
+	if conflict == gone || conflict <= Unauthorized {
+		t.Fatalf("RegisterKind() = %d, %d; want distinct custom Kinds", conflict, gone)
+	}
+
+	tests := []struct {
+		kind   Kind
+		name   string
+		status int
+	}{
+		{conflict, "conflict", http.StatusConflict},
+		{gone, "gone", http.StatusGone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.kind.String(); got != tt.name {
+				t.Errorf("String() = %q; want %q", got, tt.name)
+			}
+			if got := kindFromString(tt.name); got != tt.kind {
+				t.Errorf("kindFromString(%q) = %d; want %d", tt.name, got, tt.kind)
+			}
+			if got := KindStatus(tt.kind); got != tt.status {
+				t.Errorf("KindStatus() = %d; want %d", got, tt.status)
+			}
+			if got := KindOf(E(tt.kind, Str("boom"))); got != tt.kind {
+				t.Errorf("KindOf() = %v; want %v", got, tt.kind)
+			}
+		})
+	}
+
+	panics := []struct {
+		name string
+		kind string
+	}{
+		{"Empty", ""},
+		{"Duplicate", "conflict"},
+		{"BuiltIn", "input_validation_error"},
+		{"Other", "other_error"},
+	}
+	for _, tt := range panics {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterKind(%q) did not panic", tt.kind)
+				}
+			}()
+			RegisterKind(tt.kind, http.StatusTeapot)
+		})
+	}
+
+	// Only one of concurrent registrations of a name succeeds
+	var (
+		wg         sync.WaitGroup
+		registered atomic.Int32
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { recover() }()
+			RegisterKind("locked", http.StatusLocked)
+			registered.Add(1)
+		}()
+	}
+	wg.Wait()
+	if n := registered.Load(); n != 1 {
+		t.Errorf("registered %q %d times concurrently; want 1", "locked", n)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/gilcrest/errors/lambdaerrors
+
+go 1.21
+
+require (
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/gilcrest/errors v0.0.0
+)
+
+require github.com/rs/zerolog v1.14.0 // indirect
+
+replace github.com/gilcrest/errors => ../
@@ -0,0 +1,73 @@
+// Package lambdaerrors sends errors built with the errors package from
+// AWS Lambda functions behind API Gateway, with the same status code,
+// body and headers as errors.HTTPErrorCtx:
+//
+//	func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+//		user, err := getUser(ctx, req.PathParameters["id"])
+//		if err != nil {
+//			return lambdaerrors.Response(ctx, err), nil
+//		}
+//		...
+//	}
+package lambdaerrors
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/gilcrest/errors"
+)
+
+// Response logs err and returns the API Gateway proxy response which
+// sends it, as errors.HTTPErrorCtx does. If ctx has no request ID (see
+// errors.RequestIDFunc), the AWS request ID of the Lambda context is
+// used, so it is sent and logged with the error.
+func Response(ctx context.Context, err error) events.APIGatewayProxyResponse {
+	if errors.RequestIDFunc(ctx) == "" {
+		if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+			ctx = errors.WithRequestID(ctx, lc.AwsRequestID)
+		}
+	}
+	w := &responseWriter{header: http.Header{}}
+	errors.HTTPErrorCtx(ctx, w, err)
+
+	resp := events.APIGatewayProxyResponse{
+		StatusCode:        w.status,
+		Headers:           make(map[string]string, len(w.header)),
+		MultiValueHeaders: make(map[string][]string, len(w.header)),
+		Body:              w.body.String(),
+	}
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+	for name, values := range w.header {
+		resp.Headers[name] = values[0]
+		resp.MultiValueHeaders[name] = values
+	}
+	return resp
+}
+
+// responseWriter records the response written by HTTPErrorCtx.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
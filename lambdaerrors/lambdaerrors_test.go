@@ -0,0 +1,55 @@
+package lambdaerrors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/gilcrest/errors"
+)
+
+func TestResponse(t *testing.T) {
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "aws-req-1"})
+	err := errors.RE(http.StatusServiceUnavailable, errors.IO, errors.Code("Unavailable"), errors.Retry(30*time.Second), errors.Str("try again later"))
+
+	resp := Response(ctx, err)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d; want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if got := resp.Headers["Content-Type"]; got != "application/json" {
+		t.Errorf("Content-Type = %q; want application/json", got)
+	}
+	if got := resp.Headers["Retry-After"]; got != "30" {
+		t.Errorf("Retry-After = %q; want 30", got)
+	}
+	if got := resp.MultiValueHeaders["Retry-After"]; len(got) != 1 || got[0] != "30" {
+		t.Errorf("MultiValueHeaders[Retry-After] = %q; want [30]", got)
+	}
+
+	var er errors.ErrResponse
+	if err := json.Unmarshal([]byte(resp.Body), &er); err != nil {
+		t.Fatalf("Body = %q: %v", resp.Body, err)
+	}
+	want := errors.ServiceError{Kind: errors.IO.String(), Code: "Unavailable", Message: "try again later", RequestID: "aws-req-1"}
+	er.Error.ErrorID = ""
+	if !reflect.DeepEqual(er.Error, want) {
+		t.Errorf("Body error = %+v; want %+v", er.Error, want)
+	}
+}
+
+func TestResponseRequestID(t *testing.T) {
+	ctx := errors.WithRequestID(context.Background(), "req-1")
+	ctx = lambdacontext.NewContext(ctx, &lambdacontext.LambdaContext{AwsRequestID: "aws-req-1"})
+
+	var er errors.ErrResponse
+	if err := json.Unmarshal([]byte(Response(ctx, errors.RE(errors.NotExist)).Body), &er); err != nil {
+		t.Fatal(err)
+	}
+	if er.Error.RequestID != "req-1" {
+		t.Errorf("RequestID = %q; want req-1", er.Error.RequestID)
+	}
+}
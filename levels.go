@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+)
+
+// StatusLevel sets the Level at which the errors sent with an HTTP
+// status code from Min to Max, inclusive, are logged.
+type StatusLevel struct {
+	Min, Max int
+	Level    Level
+}
+
+// StatusLevels maps ranges of HTTP status codes to the Level at which
+// HTTPError logs the errors sent with them. The first range which
+// holds the status code of an error applies.
+type StatusLevels []StatusLevel
+
+// level returns the Level of the errors sent with the HTTP status
+// code status, and whether a range holds it.
+func (sl StatusLevels) level(status int) (Level, bool) {
+	for _, l := range sl {
+		if status >= l.Min && status <= l.Max {
+			return l.Level, true
+		}
+	}
+	return ErrorLevel, false
+}
+
+// LogLevels is the StatusLevels applied to the errors logged by
+// HTTPError, unless overridden for a request with WithLogLevels. By
+// default, it is empty, so errors are logged at error level. For
+// example, so expected client errors do not trigger the alerts on
+// error level logs:
+//
+//	errors.LogLevels = errors.StatusLevels{
+//		{Min: 400, Max: 499, Level: errors.WarnLevel},
+//		{Min: 500, Max: 599, Level: errors.ErrorLevel},
+//	}
+//
+// The Severity of an error, if set, takes precedence (see SeverityOf).
+var LogLevels StatusLevels
+
+// WithLogLevels returns a copy of ctx which sets the StatusLevels of
+// the errors logged by HTTPErrorCtx, instead of LogLevels. Status
+// codes which are not in any range of levels are logged at error
+// level.
+func WithLogLevels(ctx context.Context, levels StatusLevels) context.Context {
+	return context.WithValue(ctx, logLevelsKey, levels)
+}
+
+// LogLevelsHandler is middleware which sets the StatusLevels of the
+// errors logged for the requests of next, e.g. for a handler whose
+// client errors are expected:
+//
+//	mux.Handle("/search", errors.LogLevelsHandler(errors.StatusLevels{
+//		{Min: 400, Max: 499, Level: errors.DebugLevel},
+//	}, searchHandler))
+func LogLevelsHandler(levels StatusLevels, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithLogLevels(r.Context(), levels)))
+	})
+}
+
+// logLevel returns the Level at which err, sent with the HTTP status
+// code status for the request of ctx, is logged: the level of its
+// Severity if set, or else the level of status in the StatusLevels of
+// ctx, or else in LogLevels.
+func logLevel(ctx context.Context, err error, status int) Level {
+	if s := SeverityOf(err); s != DefaultSeverity {
+		return s.level()
+	}
+	levels, ok := ctx.Value(logLevelsKey).(StatusLevels)
+	if !ok {
+		levels = LogLevels
+	}
+	l, _ := levels.level(status)
+	return l
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogLevels(t *testing.T) {
+	defer SetLogger(nil)
+	defer func() { LogLevels = nil }()
+	LogLevels = StatusLevels{
+		{Min: 400, Max: 499, Level: WarnLevel},
+		{Min: 500, Max: 599, Level: ErrorLevel},
+	}
+	handlerLevels := StatusLevels{{Min: 400, Max: 499, Level: DebugLevel}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want Level
+	}{
+		{"Client error", context.Background(), RE(http.StatusNotFound, NotExist), WarnLevel},
+		{"Server error", context.Background(), RE(http.StatusBadGateway, IO), ErrorLevel},
+		{"Not in a range", context.Background(), RE(http.StatusSwitchingProtocols, Other), ErrorLevel},
+		{"Severity", context.Background(), RE(http.StatusNotFound, NotExist, CriticalSeverity), CriticalLevel},
+		{"Context", WithLogLevels(context.Background(), handlerLevels), RE(http.StatusNotFound, NotExist), DebugLevel},
+		{"Context without range", WithLogLevels(context.Background(), handlerLevels), RE(http.StatusBadGateway, IO), ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+			HTTPErrorCtx(tt.ctx, httptest.NewRecorder(), tt.err)
+			if len(tl.entries) != 1 || tl.entries[0].level != tt.want {
+				t.Errorf("logged %+v; want one entry at level %v", tl.entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLevelsHandler(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	h := LogLevelsHandler(StatusLevels{{Min: 400, Max: 499, Level: InfoLevel}},
+		HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return NotFound("users.Get", "no such user")
+		}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if len(tl.entries) != 1 || tl.entries[0].level != InfoLevel {
+		t.Errorf("logged %+v; want one entry at level %v", tl.entries, InfoLevel)
+	}
+}
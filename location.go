@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// CaptureLocation determines whether E, RE and the other constructors
+// of this package record the file and line where each error is
+// constructed (see (*Error).Location). Recording a location is much
+// cheaper than capturing a stack (see CaptureStack), but it may still
+// be disabled in performance-sensitive programs by setting
+// CaptureLocation to false.
+var CaptureLocation = true
+
+// captureLocation returns the program counter of the caller of the
+// function calling captureLocation, skipping skip more frames. It
+// returns 0 if CaptureLocation is false.
+func captureLocation(skip int) uintptr {
+	if !CaptureLocation {
+		return 0
+	}
+	var pc [1]uintptr
+	if runtime.Callers(skip+3, pc[:]) == 0 {
+		return 0
+	}
+	return pc[0]
+}
+
+// location formats the location of pc as "file:line", or returns ""
+// if pc is 0.
+func location(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if f.File == "" {
+		return ""
+	}
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// Location returns the file and line where the Error was constructed,
+// as "file:line", or "" if it was not recorded.
+func (e *Error) Location() string {
+	if e == nil {
+		return ""
+	}
+	return location(e.pc)
+}
+
+// Location returns the file and line where the HTTPErr was
+// constructed, as "file:line", or "" if it was not recorded.
+func (hse HTTPErr) Location() string {
+	return location(hse.pc)
+}
+
+// locationOf returns the innermost location recorded in the chain of
+// errors wrapped by err, as it is the closest to where the error
+// occurred, or "" if none was recorded.
+func locationOf(err error) string {
+	var loc string
+	for _, err := range chainOf(err) {
+		if l, ok := err.(interface{ Location() string }); ok && l.Location() != "" {
+			loc = l.Location()
+		}
+	}
+	return loc
+}
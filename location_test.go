@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+// here returns the location of its caller.
+func here() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func TestLocation(t *testing.T) {
+	type locator interface{ Location() string }
+	// Each error is constructed on the same line as the call to here
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"E", E(Op("db.Get"), io.EOF), here()},
+		{"RE", RE(http.StatusNotFound, NotExist), here()},
+		{"NotFound", NotFound("users.Get", "no such user"), here()},
+		{"InternalError", InternalError("users.Get", io.EOF), here()},
+		{"Wrap", Wrap(io.EOF, "users.Get"), here()},
+		{"WithStack", WithStack(io.EOF), here()},
+		{"HTTPBuilder", NewHTTP().Kind(NotExist).Err(), here()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.(locator).Location(); got != tt.want {
+				t.Errorf("Location() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationLogged(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	inner, want := E(Op("db.Get"), NotExist, io.EOF), here()
+	HTTPError(httptest.NewRecorder(), RE(http.StatusNotFound, E(Op("users.Get"), inner)))
+	if got := tl.entries[0].fields["location"]; got != want {
+		t.Errorf("fields[location] = %v; want %q", got, want)
+	}
+}
+
+func TestCaptureLocationDisabled(t *testing.T) {
+	defer func() { CaptureLocation = true }()
+	CaptureLocation = false
+
+	if got := E(Op("db.Get"), io.EOF).(*Error).Location(); got != "" {
+		t.Errorf("Location() = %q; want none", got)
+	}
+	if got := locationOf(RE(http.StatusNotFound)); got != "" {
+		t.Errorf("locationOf() = %q; want none", got)
+	}
+}
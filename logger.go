@@ -0,0 +1,145 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Level defines the level a log entry is written at.
+type Level int8
+
+// Log levels, from least to most severe.
+const (
+	DebugLevel    Level = iota // Debug information.
+	InfoLevel                  // Informational messages.
+	WarnLevel                  // Conditions that should be looked at.
+	ErrorLevel                 // Errors.
+	CriticalLevel              // Errors which need immediate attention.
+)
+
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case CriticalLevel:
+		return "critical"
+	}
+	return "unknown_level"
+}
+
+// Fields are the structured key/value pairs attached to a log entry.
+type Fields map[string]interface{}
+
+// Logger is the interface through which this package writes its logs.
+// Implement it to route error logs to the logging system of your choice
+// and register the implementation with SetLogger.
+type Logger interface {
+	Log(level Level, msg string, fields Fields)
+}
+
+// logger is the Logger used by the package. By default, logs are
+// written to the zerolog global logger.
+var logger Logger = zerologLogger{}
+
+// SetLogger sets the Logger used by this package. If l is nil, the
+// default zerolog global logger is restored. SetLogger should be
+// called at program start, before any errors are logged.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = zerologLogger{}
+	}
+	logger = l
+}
+
+// logf logs a formatted message with no fields at the given level.
+func logf(level Level, format string, args ...interface{}) {
+	logger.Log(level, fmt.Sprintf(format, args...), nil)
+}
+
+// NewZerologLogger returns a Logger which writes to zl.
+func NewZerologLogger(zl zerolog.Logger) Logger {
+	return zerologLogger{zl: &zl}
+}
+
+// zerologLogger writes to a zerolog.Logger. When zl is nil,
+// it writes to the zerolog global logger.
+type zerologLogger struct {
+	zl *zerolog.Logger
+}
+
+func (z zerologLogger) Log(level Level, msg string, fields Fields) {
+	zl := z.zl
+	if zl == nil {
+		zl = &log.Logger
+	}
+	var ev *zerolog.Event
+	switch level {
+	case DebugLevel:
+		ev = zl.Debug()
+	case InfoLevel:
+		ev = zl.Info()
+	case WarnLevel:
+		ev = zl.Warn()
+	case CriticalLevel:
+		// zerolog has no level between error and fatal, and fatal
+		// implies that the program exits
+		ev = zl.Error().Str("severity", CriticalLevel.String())
+	default:
+		ev = zl.Error()
+	}
+	ev.Fields(fields).Msg(msg)
+}
+
+// NewSlogLogger returns a Logger which writes to sl. If sl is nil,
+// it writes to slog.Default().
+func NewSlogLogger(sl *slog.Logger) Logger {
+	return slogLogger{sl: sl}
+}
+
+// slogLogger writes to a *slog.Logger. When sl is nil,
+// it writes to slog.Default().
+type slogLogger struct {
+	sl *slog.Logger
+}
+
+func (s slogLogger) Log(level Level, msg string, fields Fields) {
+	var lvl slog.Level
+	switch level {
+	case DebugLevel:
+		lvl = slog.LevelDebug
+	case InfoLevel:
+		lvl = slog.LevelInfo
+	case WarnLevel:
+		lvl = slog.LevelWarn
+	case CriticalLevel:
+		lvl = slog.LevelError + 4
+	default:
+		lvl = slog.LevelError
+	}
+	// Sort the keys so attributes are always in the same order
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	sl := s.sl
+	if sl == nil {
+		sl = slog.Default()
+	}
+	sl.LogAttrs(context.Background(), lvl, msg, attrs...)
+}
@@ -0,0 +1,123 @@
+package errors
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// testLogger records the entries it is asked to log.
+type testLogger struct {
+	entries []testEntry
+}
+
+type testEntry struct {
+	level  Level
+	msg    string
+	fields Fields
+}
+
+func (l *testLogger) Log(level Level, msg string, fields Fields) {
+	l.entries = append(l.entries, testEntry{level, msg, fields})
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	HTTPError(httptest.NewRecorder(), RE(400, Validation, Str("bad input")))
+
+	if len(tl.entries) != 1 {
+		t.Fatalf("got %d log entries; want 1", len(tl.entries))
+	}
+	if got := tl.entries[0]; got.level != ErrorLevel || got.msg != "bad input" {
+		t.Errorf("got entry %v %q; want %v %q", got.level, got.msg, ErrorLevel, "bad input")
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	defer SetLogger(nil)
+
+	inner := E(Op("db.Exec"), Database, "connection reset")
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+		want    Fields
+	}{
+		{"HTTPErr", RE(409, Exist, Code("user_exists"), Parameter("email"), E(Op("service.Insert"), Str("user exists"))), "user exists",
+			Fields{"status": 409, "kind": "item_already_exists", "code": "user_exists", "param": "email", "op_chain": "service.Insert"}},
+		{"Status Only", RE(404), "Not Found",
+			Fields{"status": 404}},
+		{"Error", E(Op("handler.Create"), E(Op("service.Insert"), inner)), "",
+			Fields{"status": 500, "kind": "unanticipated_error", "code": "Unanticipated", "op_chain": "handler.Create -> service.Insert -> db.Exec"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+
+			HTTPError(httptest.NewRecorder(), tt.err)
+			if len(tl.entries) != 1 {
+				t.Fatalf("got %d log entries; want 1", len(tl.entries))
+			}
+			got := tl.entries[0]
+			if tt.wantMsg != "" && got.msg != tt.wantMsg {
+				t.Errorf("msg = %q; want %q", got.msg, tt.wantMsg)
+			}
+			for k, v := range tt.want {
+				if got.fields[k] != v {
+					t.Errorf("fields[%q] = %v; want %v", k, got.fields[k], v)
+				}
+			}
+			for _, k := range []string{"kind", "code", "param", "op_chain"} {
+				if _, ok := tt.want[k]; !ok && got.fields[k] != nil {
+					t.Errorf("fields[%q] = %v; want none", k, got.fields[k])
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerAdapters(t *testing.T) {
+	var zbuf, sbuf bytes.Buffer
+	tests := []struct {
+		name string
+		l    Logger
+		buf  *bytes.Buffer
+	}{
+		{"zerolog", NewZerologLogger(zerolog.New(&zbuf)), &zbuf},
+		{"slog", NewSlogLogger(slog.New(slog.NewJSONHandler(&sbuf, nil))), &sbuf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.l.Log(WarnLevel, "some message", Fields{"code": "some_code"})
+			out := strings.ToLower(tt.buf.String())
+			for _, want := range []string{`"warn"`, `"some message"`, `"code":"some_code"`} {
+				if !strings.Contains(out, want) {
+					t.Errorf("output %q does not contain %s", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSlogLoggerNil(t *testing.T) {
+	defer func(prev *slog.Logger) {
+		slog.SetDefault(prev)
+	}(slog.Default())
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	NewSlogLogger(nil).Log(ErrorLevel, "some message", nil)
+	if !strings.Contains(buf.String(), `"some message"`) {
+		t.Errorf("output %q does not contain the message", buf.String())
+	}
+}
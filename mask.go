@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// MaskedParams are the parameters whose values are masked wherever
+// errors are logged or serialized: in the log entries of HTTPError and
+// ReportError, the slog attributes of errors, their JSON encoding and
+// their Snapshots, as well as in the responses, as for the Params of
+// the Redaction policy. For an error about one of these parameters,
+// its message and its invalid value (see Param) are masked, as the
+// message may contain the value. Anywhere else in a message, a value
+// given as a key/value pair with the name of one of these parameters,
+// e.g. "password=hunter2" or "token:abc123", is masked too, in case it
+// was embedded in the message by accident, e.g. with Errorf. The mask
+// is the Mask of the Redaction policy.
+var MaskedParams = []Parameter{"password", "ssn", "token"}
+
+// masked reports whether param is one of the MaskedParams.
+func masked(param Parameter) bool {
+	if param == "" {
+		return false
+	}
+	for _, mp := range MaskedParams {
+		if mp == param {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	pairMu sync.Mutex
+	// pairParams are the MaskedParams pairPattern was compiled for.
+	pairParams  string
+	pairPattern *regexp.Regexp
+)
+
+// maskedPairs returns the pattern matching the key/value pairs of the
+// MaskedParams, or nil if there are none.
+func maskedPairs() *regexp.Regexp {
+	names := make([]string, len(MaskedParams))
+	for i, p := range MaskedParams {
+		names[i] = regexp.QuoteMeta(string(p))
+	}
+	key := strings.Join(names, "|")
+	pairMu.Lock()
+	defer pairMu.Unlock()
+	if key != pairParams || pairPattern == nil {
+		pairParams, pairPattern = key, nil
+		if key != "" {
+			// A colon must be followed by the value, so prose such
+			// as "token: expired" is not taken for a pair
+			pairPattern = regexp.MustCompile(`(?i)\b(` + key + `)(\s*=\s*|:)[^\s,;]+`)
+		}
+	}
+	return pairPattern
+}
+
+// maskPairs masks the values of the key/value pairs of the
+// MaskedParams in msg.
+func maskPairs(msg string) string {
+	re := maskedPairs()
+	if re == nil || msg == "" {
+		return msg
+	}
+	return re.ReplaceAllString(msg, "${1}${2}"+Redaction.mask())
+}
+
+// maskMessage returns msg, the message of err or a text derived from
+// it, with the values of the MaskedParams masked: if an error of the
+// chain of err is about one of them, the message of the original cause
+// of err and the invalid value, if any, are masked.
+func maskMessage(err error, msg string) string {
+	var isMasked bool
+	eachOf(err, func(err error) bool {
+		switch e := err.(type) {
+		case *Error:
+			isMasked = masked(e.Param)
+		case *HTTPErr:
+			isMasked = masked(e.Param)
+		case *InvalidValue:
+			isMasked = masked(e.Param)
+		}
+		return !isMasked
+	})
+	if isMasked {
+		if root := Root(err).Error(); root != "" {
+			msg = strings.ReplaceAll(msg, root, Redaction.mask())
+		}
+		if iv := invalidValueOf(err); iv != nil {
+			msg = maskInvalidValue(iv, msg)
+		}
+	}
+	return maskPairs(msg)
+}
+
+// maskInvalidValue masks the invalid value of iv in msg where it is
+// given as the value of its parameter: in the message of iv, and as a
+// "param=value" pair. The value is not masked anywhere else, as a short
+// value, e.g. "1", would mask unrelated text.
+func maskInvalidValue(iv *InvalidValue, msg string) string {
+	if iv.Got == nil {
+		return msg
+	}
+	got := fmt.Sprint(iv.Got)
+	if got == "" {
+		return msg
+	}
+	masked := *iv
+	masked.Got = Redaction.mask()
+	msg = strings.ReplaceAll(msg, iv.Error(), masked.Error())
+	pair := string(iv.Param) + "="
+	return strings.ReplaceAll(msg, pair+got, pair+Redaction.mask())
+}
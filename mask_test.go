@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaskedParams(t *testing.T) {
+	defer SetLogger(nil)
+	tests := []struct {
+		name    string
+		err     error
+		wantLog string
+	}{
+		{"Param", RE(http.StatusBadRequest, Validation, Parameter("password"), Str("hunter2 is too short")),
+			"[REDACTED]"},
+		{"Wrapped", E(Op("users.Create"), Validation, E(Op("auth.Check"), Parameter("ssn"), Str("123-45-6789 is not valid"))),
+			"[REDACTED]"},
+		{"Invalid value", RE(http.StatusBadRequest, Validation, Param("token", "abc123", "a valid token")),
+			"[REDACTED]"},
+		{"Pair", Errorf("login failed with password=hunter2 for jane"),
+			"login failed with password=[REDACTED] for jane"},
+		{"Other param", RE(http.StatusBadRequest, Validation, Parameter("name"), Str("hunter2 is too short")),
+			"hunter2 is too short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+			rr := httptest.NewRecorder()
+			HTTPErrorCtx(context.Background(), rr, tt.err)
+			if len(tl.entries) != 1 {
+				t.Fatalf("log entries = %v; want 1", tl.entries)
+			}
+			if got := tl.entries[0].msg; !strings.HasSuffix(got, tt.wantLog) {
+				t.Errorf("logged message = %q; want suffix %q", got, tt.wantLog)
+			}
+			for _, secret := range []string{"hunter2", "123-45-6789", "abc123"} {
+				if tt.name == "Other param" {
+					break
+				}
+				if strings.Contains(tl.entries[0].msg, secret) || strings.Contains(rr.Body.String(), secret) {
+					t.Errorf("%q logged or sent: %q, %s", secret, tl.entries[0].msg, rr.Body.String())
+				}
+				b, _ := json.Marshal(tt.err)
+				if strings.Contains(string(b), secret) {
+					t.Errorf("%q in the JSON encoding: %s", secret, b)
+				}
+			}
+		})
+	}
+}
+
+func TestMaskedParamsConfig(t *testing.T) {
+	defer func(prev []Parameter) { MaskedParams = prev }(MaskedParams)
+	MaskedParams = []Parameter{"pin"}
+	if got := maskPairs("pin:1234, password=hunter2"); got != "pin:[REDACTED], password=hunter2" {
+		t.Errorf("maskPairs() = %q", got)
+	}
+	MaskedParams = nil
+	if got := maskPairs("pin:1234"); got != "pin:1234" {
+		t.Errorf("maskPairs() = %q", got)
+	}
+}
+
+func TestMaskShortValue(t *testing.T) {
+	// Only the value of the parameter is masked, not every "1"
+	err := E(Op("auth.Check"), Param("token", "1", "6 digits"))
+	msg := "user 1001 sent token=1, retry 1 of 3: " + Root(err).Error()
+	want := "user 1001 sent token=[REDACTED], retry 1 of 3: [REDACTED]"
+	if got := maskMessage(err, msg); got != want {
+		t.Errorf("maskMessage() = %q; want %q", got, want)
+	}
+}
+
+func TestMaskPairsProse(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"token: expired, please log in again", "token: expired, please log in again"},
+		{"the password: is required", "the password: is required"},
+		{"token:abc123 rejected", "token:[REDACTED] rejected"},
+		{"token = abc123 rejected", "token = [REDACTED] rejected"},
+	}
+	for _, tt := range tests {
+		if got := maskPairs(tt.msg); got != tt.want {
+			t.Errorf("maskPairs(%q) = %q; want %q", tt.msg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+var (
+	messagesMu sync.RWMutex
+	// messages holds the message templates registered for each
+	// Code, by language.
+	messages = map[Code]map[string]*template.Template{}
+)
+
+// DefaultLanguage is the language used for error messages when none
+// of the languages of the locale have a message registered. If empty,
+// the original error message is sent instead.
+var DefaultLanguage = ""
+
+// MessageData is the data a message template is executed with.
+type MessageData struct {
+	Code    Code
+	Kind    string
+	Param   Parameter
+	Message string
+}
+
+// RegisterMessage registers a translation of the message for errors
+// with the given Code. lang is a language tag such as "en" or "fr-CA".
+// The template is parsed with text/template and executed with a
+// MessageData, e.g. "{{.Param}} est obligatoire". RegisterMessage
+// panics if the template cannot be parsed.
+//
+// HTTPErrorCtx sends the translated message in place of the error
+// message when the locale of the context (see WithLocale) matches a
+// registered language.
+func RegisterMessage(code Code, lang string, tmpl string) {
+	t := template.Must(template.New(string(code) + "/" + lang).Parse(tmpl))
+
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	if messages[code] == nil {
+		messages[code] = map[string]*template.Template{}
+	}
+	messages[code][strings.ToLower(lang)] = t
+}
+
+// WithLocale returns a copy of ctx which carries the locale used to
+// translate error messages. locale is either a single language tag,
+// e.g. "fr-CA", or a list of languages in the format of an
+// Accept-Language header, e.g. "fr-CA,fr;q=0.9,en;q=0.8".
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// LocaleFromContext returns the locale added to ctx with WithLocale,
+// or an empty string if there is none.
+func LocaleFromContext(ctx context.Context) string {
+	l, _ := ctx.Value(localeKey).(string)
+	return l
+}
+
+// Message returns the message for the given Code, translated into
+// the best matching language of locale, executing the template with
+// data. It reports whether a translation was found.
+func Message(code Code, locale string, data MessageData) (string, bool) {
+	messagesMu.RLock()
+	byLang := messages[code]
+	messagesMu.RUnlock()
+	if len(byLang) == 0 {
+		return "", false
+	}
+
+	langs := languages(locale)
+	if DefaultLanguage != "" {
+		langs = append(langs, strings.ToLower(DefaultLanguage))
+	}
+	for _, lang := range langs {
+		t, ok := byLang[lang]
+		if !ok {
+			// Fall back from a regional variant to the base language,
+			// e.g. from "fr-ca" to "fr"
+			if i := strings.Index(lang, "-"); i > 0 {
+				t, ok = byLang[lang[:i]]
+			}
+		}
+		if !ok {
+			continue
+		}
+		b := new(bytes.Buffer)
+		if err := t.Execute(b, data); err != nil {
+			logf(ErrorLevel, "errors.Message: template for code %q: %v", code, err)
+			return "", false
+		}
+		return b.String(), true
+	}
+	return "", false
+}
+
+// localize replaces the messages of se, and of the errors it lists,
+// with their translations into the locale of ctx, if any.
+func localize(ctx context.Context, se *ServiceError) {
+	locale := LocaleFromContext(ctx)
+	if se == nil || (locale == "" && DefaultLanguage == "") {
+		return
+	}
+	localizeOne := func(se *ServiceError) {
+		if se.Code == "" {
+			return
+		}
+		data := MessageData{
+			Code:    Code(se.Code),
+			Kind:    se.Kind,
+			Param:   Parameter(se.Param),
+			Message: se.Message,
+		}
+		if msg, ok := Message(Code(se.Code), locale, data); ok {
+			se.Message = msg
+		}
+	}
+	localizeOne(se)
+	for i := range se.Errors {
+		localizeOne(&se.Errors[i])
+	}
+}
+
+// languages parses locale, a language tag or an Accept-Language
+// header value, and returns its language tags in lower case,
+// in order of preference. Languages with a q-value of 0 are not
+// acceptable to the client, so they are left out.
+func languages(locale string) []string {
+	type lq struct {
+		lang string
+		q    float64
+	}
+	var lqs []lq
+	for _, part := range strings.Split(locale, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		lqs = append(lqs, lq{lang, q})
+	}
+	sort.SliceStable(lqs, func(i, j int) bool {
+		return lqs[i].q > lqs[j].q
+	})
+	langs := make([]string, len(lqs))
+	for i, l := range lqs {
+		langs[i] = l.lang
+	}
+	return langs
+}
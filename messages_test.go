@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	const code Code = "test_required"
+	RegisterMessage(code, "fr", "{{.Param}} est obligatoire")
+	RegisterMessage(code, "de-AT", "{{.Param}} ist erforderlich")
+	data := MessageData{Code: code, Param: "name"}
+
+	tests := []struct {
+		locale string
+		want   string
+		ok     bool
+	}{
+		{"fr", "name est obligatoire", true},
+		{"fr-CA", "name est obligatoire", true},
+		{"DE-at", "name ist erforderlich", true},
+		{"en-US,de-AT;q=0.8,fr;q=0.9", "name est obligatoire", true},
+		{"en", "", false},
+		{"en-US,fr;q=0", "", false},
+		{"fr;q=0,de-AT;q=0.5", "name ist erforderlich", true},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got, ok := Message(code, tt.locale, data)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Message() = %q, %t; want %q, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorLocalized(t *testing.T) {
+	const code Code = "test_name_required"
+	RegisterMessage(code, "fr", "{{.Param}} est obligatoire")
+	err := RE(http.StatusBadRequest, Validation, code, Parameter("name"), MissingField("name"))
+
+	t.Run("WithLocale", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		HTTPErrorCtx(WithLocale(context.Background(), "fr"), rr, err)
+		var er ErrResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if er.Error.Message != "name est obligatoire" {
+			t.Errorf("Message = %q; want %q", er.Error.Message, "name est obligatoire")
+		}
+	})
+
+	t.Run("Accept-Language", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "fr-CA,fr;q=0.9")
+		Handler(func(w http.ResponseWriter, r *http.Request) error {
+			return err
+		}).ServeHTTP(rr, req)
+		var er ErrResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if er.Error.Message != "name est obligatoire" {
+			t.Errorf("Message = %q; want %q", er.Error.Message, "name est obligatoire")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+)
+
+// Metrics is notified of the errors handled by this package, so
+// they can be counted by class, e.g. with Prometheus or OpenTelemetry
+// counters. Register an implementation with SetMetrics.
+type Metrics interface {
+	// ErrorSent is called by HTTPError for each error response sent,
+	// with the Kind and Code of the error and the HTTP status code.
+	ErrorSent(ctx context.Context, kind Kind, code Code, status int)
+	// ErrorCreated is called by E and RE for each error created,
+	// if MetricsOnCreate is true.
+	ErrorCreated(kind Kind, code Code)
+}
+
+// MetricsOnCreate determines whether E and RE notify the Metrics of
+// each error they create. It is false by default, as an error which is
+// wrapped by each layer it passes through would be counted each time.
+var MetricsOnCreate = false
+
+// metrics is the Metrics used by the package. By default, it is a
+// no-op implementation.
+var metrics Metrics = noopMetrics{}
+
+// SetMetrics sets the Metrics notified of errors. If m is nil, the
+// default no-op implementation is restored. SetMetrics should be
+// called at program start, before any errors are created.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		m = noopMetrics{}
+	}
+	metrics = m
+}
+
+// noopMetrics is a Metrics which does nothing.
+type noopMetrics struct{}
+
+func (noopMetrics) ErrorSent(context.Context, Kind, Code, int) {}
+func (noopMetrics) ErrorCreated(Kind, Code)                    {}
+
+// recordCreated notifies the Metrics that e was created,
+// if MetricsOnCreate is true.
+func (e *Error) recordCreated() {
+	if MetricsOnCreate {
+		metrics.ErrorCreated(e.Kind, e.Code)
+	}
+}
+
+// recordCreated notifies the Metrics that hse was created,
+// if MetricsOnCreate is true.
+func (hse *HTTPErr) recordCreated() {
+	if MetricsOnCreate {
+		metrics.ErrorCreated(hse.Kind, hse.Code)
+	}
+}
+
+// classify returns the Kind and Code HTTPError sends for err.
+func classify(err error) (Kind, Code) {
+	if nilHTTPErr(err) {
+		err = nilHTTPErrError()
+	}
+	switch e := err.(type) {
+	case *HTTPErr:
+		return e.Kind, e.Code
+	case hError:
+		return kindFromString(e.ErrKind()), Code(e.ErrCode())
+	}
+	if ge := groupOf(err); ge != nil {
+		return classify(ge.dominant())
+	}
+	if be := batchOf(err); be != nil {
+		return be.Kind(), ""
+	}
+	var ve ValidationErrors
+	if stderrors.As(err, &ve) {
+		return Validation, ""
+	}
+	if te := translate(err); te != nil {
+		return classify(te)
+	}
+	_, se := FallbackResponse(err)
+	return kindFromString(se.Kind), Code(se.Code)
+}
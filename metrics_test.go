@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testMetrics records the calls made to it.
+type testMetrics struct {
+	sent    []string
+	created []Kind
+}
+
+func (m *testMetrics) ErrorSent(_ context.Context, kind Kind, code Code, status int) {
+	m.sent = append(m.sent, kind.String()+"/"+string(code)+"/"+http.StatusText(status))
+}
+
+func (m *testMetrics) ErrorCreated(kind Kind, code Code) {
+	m.created = append(m.created, kind)
+}
+
+func TestMetrics(t *testing.T) {
+	tm := &testMetrics{}
+	SetMetrics(tm)
+	defer SetMetrics(nil)
+
+	HTTPError(httptest.NewRecorder(), RE(http.StatusNotFound, NotExist, Code("user_not_found")))
+	HTTPError(httptest.NewRecorder(), Str("some error"))
+
+	want := []string{
+		"item_does_not_exist/user_not_found/Not Found",
+		"unanticipated_error/Unanticipated/Internal Server Error",
+	}
+	if len(tm.sent) != len(want) {
+		t.Fatalf("got %d ErrorSent calls; want %d", len(tm.sent), len(want))
+	}
+	for i := range want {
+		if tm.sent[i] != want[i] {
+			t.Errorf("ErrorSent call %d = %q; want %q", i, tm.sent[i], want[i])
+		}
+	}
+	if len(tm.created) != 0 {
+		t.Errorf("got %d ErrorCreated calls; want 0", len(tm.created))
+	}
+
+	defer func(prev bool) {
+		MetricsOnCreate = prev
+	}(MetricsOnCreate)
+	MetricsOnCreate = true
+	_ = E(Op("errors/TestMetrics"), E(Op("inner"), Permission, "denied"))
+	_ = RE(NotExist)
+	if want := []Kind{Permission, Permission, NotExist}; len(tm.created) != 3 || tm.created[1] != want[1] || tm.created[2] != want[2] {
+		t.Errorf("ErrorCreated kinds = %v; want %v", tm.created, want)
+	}
+}
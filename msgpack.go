@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// appendMsgpack appends the MessagePack encoding of v to b. v is a
+// value decoded from JSON: nil, a bool, a float64, a string, a slice
+// or a map of such values. Map keys are sorted, so the encoding is
+// deterministic. Floats which are integers are encoded as integers.
+func appendMsgpack(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return appendMsgpackInt(b, int64(v))
+		}
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	case string:
+		return appendMsgpackString(b, v)
+	case []interface{}:
+		b = appendMsgpackLen(b, len(v), 0x90, 0xdc, 0xdd)
+		for _, e := range v {
+			b = appendMsgpack(b, e)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = appendMsgpackLen(b, len(v), 0x80, 0xde, 0xdf)
+		for _, k := range keys {
+			b = appendMsgpackString(b, k)
+			b = appendMsgpack(b, v[k])
+		}
+		return b
+	}
+	// Not produced by encoding/json
+	return append(b, 0xc0)
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= 0x7f:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		b = append(b, 0xd2)
+		return binary.BigEndian.AppendUint32(b, uint32(i))
+	}
+	b = append(b, 0xd3)
+	return binary.BigEndian.AppendUint64(b, uint64(i))
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	if len(s) <= 31 {
+		b = append(b, 0xa0|byte(len(s)))
+	} else {
+		b = appendMsgpackLen(b, len(s), 0, 0xda, 0xdb)
+	}
+	return append(b, s...)
+}
+
+// appendMsgpackLen appends the header of a string, array or map of
+// length n: fix is the fixed-size header, for n up to 15 (ignored if
+// 0), then the headers with a 16 and a 32 bits length.
+func appendMsgpackLen(b []byte, n int, fix, h16, h32 byte) []byte {
+	switch {
+	case fix != 0 && n <= 15:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, h16)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	}
+	b = append(b, h32)
+	return binary.BigEndian.AppendUint32(b, uint32(n))
+}
@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIComponents holds the OpenAPI 3.1 component schemas and
+// responses describing the error responses of HTTPError, as returned by
+// OpenAPISchemas. It is encoded as JSON as the "components" object of
+// an OpenAPI document, and can be merged into a generated one.
+type OpenAPIComponents struct {
+	// Schemas maps the names of the schemas to the JSON schemas of the
+	// types of the response body.
+	Schemas map[string]interface{} `json:"schemas"`
+	// Responses maps the names of the responses, "Error" followed by
+	// the status code, e.g. "Error404", to the response objects.
+	Responses map[string]interface{} `json:"responses"`
+}
+
+// openAPITypes are the types of the response bodies of HTTPError
+// described by a schema of their own.
+var openAPITypes = []reflect.Type{
+	reflect.TypeOf(ErrResponse{}),
+	reflect.TypeOf(ServiceError{}),
+	reflect.TypeOf(FieldViolation{}),
+	reflect.TypeOf(ChainLink{}),
+	reflect.TypeOf(ProblemResponse{}),
+}
+
+// OpenAPISchemas returns the OpenAPI 3.1 components describing the
+// error responses of HTTPError. The schemas are derived from the types
+// of the response bodies, so they always match them, and the kind
+// properties list the Kinds of the Catalog. A response is described
+// for each status code of the Kinds and Codes of the Catalog, in the
+// format HTTPError sends: the ErrResponse format, or the Problem
+// Details format if ProblemDetails is true. For example:
+//
+//	b, _ := json.Marshal(map[string]interface{}{"components": errors.OpenAPISchemas()})
+//
+// The responses can then be referred to by the operations of the API,
+// e.g. {"$ref": "#/components/responses/Error404"}.
+func OpenAPISchemas() OpenAPIComponents {
+	c := Catalog()
+	kinds := make([]interface{}, len(c.Kinds))
+	statuses := map[int]bool{}
+	for i, k := range c.Kinds {
+		kinds[i] = k.Kind
+		statuses[k.Status] = true
+	}
+	for _, code := range c.Codes {
+		if code.Status != 0 {
+			statuses[code.Status] = true
+		}
+	}
+
+	oc := OpenAPIComponents{
+		Schemas:   map[string]interface{}{},
+		Responses: map[string]interface{}{},
+	}
+	for _, t := range openAPITypes {
+		s := openAPISchema(t)
+		if props, ok := s["properties"].(map[string]interface{}); ok {
+			if kind, ok := props["kind"].(map[string]interface{}); ok {
+				kind["enum"] = kinds
+			}
+		}
+		oc.Schemas[t.Name()] = s
+	}
+
+	mediaType, schema := "application/json", "ErrResponse"
+	if ProblemDetails {
+		mediaType, schema = "application/problem+json", "ProblemResponse"
+	}
+	codes := make([]int, 0, len(statuses))
+	for status := range statuses {
+		codes = append(codes, status)
+	}
+	sort.Ints(codes)
+	for _, status := range codes {
+		oc.Responses["Error"+strconv.Itoa(status)] = map[string]interface{}{
+			"description": ReasonPhrase(status),
+			"content": map[string]interface{}{
+				mediaType: map[string]interface{}{
+					"schema": openAPIRef(schema),
+				},
+			},
+		}
+	}
+	return oc
+}
+
+// openAPIRef returns a reference to the component schema name.
+func openAPIRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// openAPISchema returns the JSON schema of the values of t, as encoded
+// by encoding/json. The types of openAPITypes other than t are
+// referred to.
+func openAPISchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": openAPIField(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": openAPIField(t.Elem())}
+	case reflect.Ptr:
+		return openAPIField(t.Elem())
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = openAPIField(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		s := map[string]interface{}{"type": "object", "properties": props}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	// Any value, such as the Got value of an error
+	return map[string]interface{}{}
+}
+
+// openAPIField returns the schema of a field of type t, or a reference
+// to it if it has a component schema.
+func openAPIField(t reflect.Type) map[string]interface{} {
+	for _, ct := range openAPITypes {
+		if t == ct {
+			return openAPIRef(t.Name())
+		}
+	}
+	return openAPISchema(t)
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAPISchemas(t *testing.T) {
+	defer func() { ProblemDetails = false }()
+	oc := OpenAPISchemas()
+
+	se, ok := oc.Schemas["ServiceError"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Schemas = %v; want ServiceError", oc.Schemas)
+	}
+	props := se["properties"].(map[string]interface{})
+	if got, want := props["errors"], map[string]interface{}{"type": "array", "items": openAPIRef("ServiceError")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("errors = %v; want %v", got, want)
+	}
+	kinds := props["kind"].(map[string]interface{})["enum"].([]interface{})
+	if len(kinds) <= int(Unauthorized) || kinds[NotExist] != "item_does_not_exist" {
+		t.Errorf("kind enum = %v; want the Kinds", kinds)
+	}
+	er := oc.Schemas["ErrResponse"].(map[string]interface{})
+	if got, want := er["required"], []string{"error"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ErrResponse required = %v; want %v", got, want)
+	}
+
+	// Each property sent by HTTPError is described
+	rr := httptest.NewRecorder()
+	HTTPErrorCtx(WithRequestID(WithDebug(context.Background()), "req-1"), rr,
+		RE(http.StatusNotFound, NotExist, Code("no_user"), Parameter("id"), Str("user not found")))
+	var body map[string]map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	for name := range body["error"] {
+		if _, ok := props[name]; !ok {
+			t.Errorf("property %q sent by HTTPError is not in the schema", name)
+		}
+	}
+
+	resp, ok := oc.Responses["Error404"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Responses = %v; want Error404", oc.Responses)
+	}
+	want := map[string]interface{}{"application/json": map[string]interface{}{"schema": openAPIRef("ErrResponse")}}
+	if !reflect.DeepEqual(resp["content"], want) {
+		t.Errorf("Error404 content = %v; want %v", resp["content"], want)
+	}
+
+	ProblemDetails = true
+	resp = OpenAPISchemas().Responses["Error404"].(map[string]interface{})
+	want = map[string]interface{}{"application/problem+json": map[string]interface{}{"schema": openAPIRef("ProblemResponse")}}
+	if !reflect.DeepEqual(resp["content"], want) {
+		t.Errorf("Error404 content = %v; want %v", resp["content"], want)
+	}
+
+	if _, err := json.Marshal(oc); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
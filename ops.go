@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"strings"
+)
+
+// Ops returns the operations recorded in the chain of errors wrapped
+// by err, from the outermost to the innermost, e.g.
+//
+//	[handler.Create service.Insert db.Exec]
+//
+// so the call path which led to the error can be logged compactly.
+// Errors with no Op are skipped. If err is nil or has no Op, Ops
+// returns nil.
+func Ops(err error) []Op {
+	var ops []Op
+	for _, err := range chainOf(err) {
+		switch e := err.(type) {
+		case *Error:
+			ops = append(ops, e.opChain()...)
+		case *HTTPErr:
+			ops = append(ops, e.ops...)
+		case HTTPErr:
+			ops = append(ops, e.ops...)
+		}
+	}
+	return ops
+}
+
+// opChain returns the operations of e, Op first, or nil if it has no Op.
+func (e *Error) opChain() []Op {
+	if e.Op == "" {
+		return nil
+	}
+	return append([]Op{e.Op}, e.ops...)
+}
+
+// joinOps formats ops as a call path, e.g.
+// "handler.Create -> service.Insert -> db.Exec".
+func joinOps(ops []Op) string {
+	s := make([]string, len(ops))
+	for i, op := range ops {
+		s[i] = string(op)
+	}
+	return strings.Join(s, " -> ")
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOps(t *testing.T) {
+	inner := E(Op("db.Exec"), Database, "connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want []Op
+	}{
+		{"Nested Error", E(Op("handler.Create"), E(Op("service.Insert"), inner)), []Op{"handler.Create", "service.Insert", "db.Exec"}},
+		{"No Op in between", E(Op("handler.Create"), E(Exist, inner)), []Op{"handler.Create", "db.Exec"}},
+		{"HTTPErr", RE(http.StatusInternalServerError, E(Op("service.Insert"), inner)), []Op{"service.Insert", "db.Exec"}},
+		{"Several Ops", E(Op("handler.Create"), Op("service.Insert"), inner), []Op{"handler.Create", "service.Insert", "db.Exec"}},
+		{"Op slice", E([]Op{"handler.Create", "service.Insert"}, inner), []Op{"handler.Create", "service.Insert", "db.Exec"}},
+		{"HTTPErr with Op", RE(http.StatusInternalServerError, Op("handler.Create"), E(Op("service.Insert"), inner)), []Op{"handler.Create", "service.Insert", "db.Exec"}},
+		{"No Op", Str("some error"), nil},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ops(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ops() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeveralOps(t *testing.T) {
+	err := E(Op("handler.Create"), Op("service.Insert"), Exist, Str("user exists"))
+	want := "handler.Create: service.Insert: item_already_exists|: user exists"
+	if got := err.Error(); !strings.HasSuffix(got, want) {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("json.Marshal() error = %v", jerr)
+	}
+	var out Error
+	if jerr := json.Unmarshal(b, &out); jerr != nil {
+		t.Fatalf("json.Unmarshal() error = %v", jerr)
+	}
+	if got := Ops(&out); !reflect.DeepEqual(got, Ops(err)) {
+		t.Errorf("Ops() after JSON round trip = %v; want %v", got, Ops(err))
+	}
+}
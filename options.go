@@ -0,0 +1,60 @@
+package errors
+
+// Option sets a field of the error built by New. The Options of this
+// package are checked by the compiler, unlike the arguments of RE, whose
+// meaning depends on their type, so a string cannot be mistaken for a
+// Code or a message.
+type Option func(*HTTPBuilder)
+
+// New returns an *HTTPErr for op with the fields set by opts, as built
+// by RE, as a type-safe alternative to it. For example:
+//
+//	return errors.New(op, errors.WithKind(errors.NotExist), errors.WithCode(CodeUserNotFound),
+//		errors.WithParam("id"), errors.WithMsg("user not found"))
+//
+// If op is empty, the error has no Op. If several Options set the same
+// field, the last one wins.
+func New(op Op, opts ...Option) error {
+	b := NewHTTP()
+	if op != "" {
+		b.Op(op)
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b.build(1)
+}
+
+// WithKind sets the Kind of the error.
+func WithKind(k Kind) Option {
+	return func(b *HTTPBuilder) { b.Kind(k) }
+}
+
+// WithCode sets the Code of the error.
+func WithCode(c Code) Option {
+	return func(b *HTTPBuilder) { b.Code(c) }
+}
+
+// WithStatus sets the HTTP status code of the error. If it is not set,
+// the status code mapped to the Kind is sent (see KindStatus).
+func WithStatus(status int) Option {
+	return func(b *HTTPBuilder) { b.Status(status) }
+}
+
+// WithParam sets the Parameter the error relates to.
+func WithParam(p Parameter) Option {
+	return func(b *HTTPBuilder) { b.Param(p) }
+}
+
+// WithMsg sets the message of the error, which is sent to the client.
+// It replaces any error given to WithErr.
+func WithMsg(msg string) Option {
+	return func(b *HTTPBuilder) { b.Msg(msg) }
+}
+
+// WithErr sets the error wrapped by the error. As with RE, the Kind,
+// Code and Parameter of err are inherited unless they are set. It
+// replaces any message given to WithMsg.
+func WithErr(err error) Option {
+	return func(b *HTTPBuilder) { b.Wrap(err) }
+}
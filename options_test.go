@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	const op Op = "users.Get"
+	inner := E(op, Validation, Code("bad_id"), Parameter("id"), Str("id is not a number"))
+
+	tests := []struct {
+		name string
+		got  error
+		want error
+	}{
+		{
+			"Fields",
+			New(op, WithStatus(http.StatusNotFound), WithKind(NotExist), WithCode("USER_NOT_FOUND"), WithParam("id"), WithMsg("user not found")),
+			RE(http.StatusNotFound, op, NotExist, Code("USER_NOT_FOUND"), Parameter("id"), Str("user not found")),
+		},
+		{
+			"Kind only",
+			New(op, WithKind(Permission)),
+			RE(op, Permission),
+		},
+		{
+			"No Op",
+			New("", WithKind(IO), WithMsg("try again")),
+			RE(IO, Str("try again")),
+		},
+		{
+			"Err",
+			New(op, WithStatus(http.StatusBadRequest), WithErr(inner)),
+			RE(http.StatusBadRequest, op, inner),
+		},
+		{
+			"Last wins",
+			New(op, WithKind(IO), WithKind(NotExist), WithErr(inner), WithMsg("not found")),
+			RE(op, NotExist, Str("not found")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := *tt.got.(*HTTPErr), *tt.want.(*HTTPErr)
+			// Only the locations differ
+			got.pc, want.pc = 0, 0
+			got.trace, want.trace = nil, nil
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("New() = %#v; want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewLocation(t *testing.T) {
+	err := New("users.Get", WithKind(NotExist))
+	if loc := err.(*HTTPErr).Location(); !strings.Contains(loc, "options_test.go") {
+		t.Errorf("Location() = %q; want the caller of New", loc)
+	}
+}
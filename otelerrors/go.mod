@@ -0,0 +1,21 @@
+module github.com/gilcrest/errors/otelerrors
+
+go 1.21
+
+require (
+	github.com/gilcrest/errors v0.0.0
+	go.opentelemetry.io/otel v1.27.0
+	go.opentelemetry.io/otel/metric v1.27.0
+	go.opentelemetry.io/otel/sdk v1.27.0
+	go.opentelemetry.io/otel/sdk/metric v1.27.0
+	go.opentelemetry.io/otel/trace v1.27.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/rs/zerolog v1.14.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+)
+
+replace github.com/gilcrest/errors => ../
@@ -0,0 +1,60 @@
+// Package otelerrors integrates the errors package with OpenTelemetry
+// metrics (see NewMetrics) and tracing (see Tracer).
+package otelerrors
+
+import (
+	"context"
+
+	"github.com/gilcrest/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics implements errors.Metrics with OpenTelemetry counters.
+// Register it with errors.SetMetrics.
+type Metrics struct {
+	sent    metric.Int64Counter
+	created metric.Int64Counter
+}
+
+var _ errors.Metrics = (*Metrics)(nil)
+
+// NewMetrics returns a Metrics which records its counters with meter:
+//
+//	errors.responses  the number of error responses sent by HTTPError
+//	errors.created    the number of errors created by E and RE
+//
+// Each count has the error.kind and error.code attributes; responses
+// also have the http.response.status_code attribute.
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	sent, err := meter.Int64Counter("errors.responses",
+		metric.WithDescription("Number of error responses sent."),
+		metric.WithUnit("{response}"))
+	if err != nil {
+		return nil, err
+	}
+	created, err := meter.Int64Counter("errors.created",
+		metric.WithDescription("Number of errors created."),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{sent: sent, created: created}, nil
+}
+
+// ErrorSent adds one to the errors.responses counter.
+func (m *Metrics) ErrorSent(ctx context.Context, kind errors.Kind, code errors.Code, status int) {
+	m.sent.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("error.kind", kind.String()),
+		attribute.String("error.code", string(code)),
+		attribute.Int("http.response.status_code", status),
+	))
+}
+
+// ErrorCreated adds one to the errors.created counter.
+func (m *Metrics) ErrorCreated(kind errors.Kind, code errors.Code) {
+	m.created.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("error.kind", kind.String()),
+		attribute.String("error.code", string(code)),
+	))
+}
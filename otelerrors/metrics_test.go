@@ -0,0 +1,47 @@
+package otelerrors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	m, err := NewMetrics(provider.Meter("test"))
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+	errors.SetMetrics(m)
+	defer errors.SetMetrics(nil)
+
+	errors.HTTPError(httptest.NewRecorder(), errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("user_not_found")))
+	errors.HTTPError(httptest.NewRecorder(), errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("user_not_found")))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	var got int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, mt := range sm.Metrics {
+			if mt.Name != "errors.responses" {
+				continue
+			}
+			for _, dp := range mt.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, _ := dp.Attributes.Value("error.code"); v.AsString() == "user_not_found" {
+					got += dp.Value
+				}
+			}
+		}
+	}
+	if got != 2 {
+		t.Errorf("errors.responses for user_not_found = %d; want 2", got)
+	}
+}
@@ -0,0 +1,44 @@
+package otelerrors
+
+import (
+	"context"
+
+	"github.com/gilcrest/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer implements errors.Tracer with OpenTelemetry tracing. Register
+// it with errors.SetTracer. Errors sent for requests without a
+// recording span are not recorded, and are sent without a trace ID if
+// the context has no valid span context.
+type Tracer struct{}
+
+var _ errors.Tracer = Tracer{}
+
+// ErrorSent sets the status of the active span of ctx to Error, and
+// records err as an exception event on it, with the error.kind,
+// error.code and http.response.status_code attributes.
+func (Tracer) ErrorSent(ctx context.Context, err error, kind errors.Kind, code errors.Code, status int) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetStatus(codes.Error, kind.String())
+	span.RecordError(err, trace.WithAttributes(
+		attribute.String("error.kind", kind.String()),
+		attribute.String("error.code", string(code)),
+		attribute.Int("http.response.status_code", status),
+	))
+}
+
+// TraceID returns the trace ID of the span context of ctx, or "" if
+// it is not valid.
+func (Tracer) TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
@@ -0,0 +1,71 @@
+package otelerrors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	errors.SetTracer(Tracer{})
+	defer errors.SetTracer(nil)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "GET /users/1")
+	rr := httptest.NewRecorder()
+	errors.HTTPErrorCtx(ctx, rr, errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("no_user"), errors.Str("no such user")))
+	span.End()
+
+	var er errors.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if want := span.SpanContext().TraceID().String(); er.Error.TraceID != want {
+		t.Errorf("trace_id = %q; want %q", er.Error.TraceID, want)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans; want 1", len(spans))
+	}
+	if st := spans[0].Status(); st.Code != codes.Error || st.Description != errors.NotExist.String() {
+		t.Errorf("span status = %+v; want Error with the Kind", st)
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("span events = %+v; want one exception", events)
+	}
+	var code string
+	for _, a := range events[0].Attributes {
+		if a.Key == "error.code" {
+			code = a.Value.AsString()
+		}
+	}
+	if code != "no_user" {
+		t.Errorf("error.code = %q; want no_user", code)
+	}
+}
+
+func TestTracerNoSpan(t *testing.T) {
+	errors.SetTracer(Tracer{})
+	defer errors.SetTracer(nil)
+
+	rr := httptest.NewRecorder()
+	errors.HTTPErrorCtx(context.Background(), rr, errors.RE(http.StatusNotFound, errors.NotExist, errors.Str("no such user")))
+
+	var er errors.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if er.Error.TraceID != "" {
+		t.Errorf("trace_id = %q; want none", er.Error.TraceID)
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import "sync"
+
+// PoolErrors determines whether E and RE take the errors they return
+// from a pool, to which they are put back by Release. It is false by
+// default. It may be enabled in programs which create many errors, such
+// as a proxy during an outage of its upstream, to reduce allocations,
+// provided the errors are released once they have been handled and are
+// not used after that.
+var PoolErrors = false
+
+var (
+	errorPool   = sync.Pool{New: func() interface{} { return new(Error) }}
+	httpErrPool = sync.Pool{New: func() interface{} { return new(HTTPErr) }}
+)
+
+// newError returns a zero Error, from the pool if PoolErrors is true.
+func newError() *Error {
+	if PoolErrors {
+		return errorPool.Get().(*Error)
+	}
+	return &Error{}
+}
+
+// newHTTPErr returns a zero HTTPErr, from the pool if PoolErrors is
+// true.
+func newHTTPErr() *HTTPErr {
+	if PoolErrors {
+		return httpErrPool.Get().(*HTTPErr)
+	}
+	return &HTTPErr{}
+}
+
+// Release puts err back in the pool of E or RE, if PoolErrors is true
+// and err is an *Error or an *HTTPErr, so it can be reused. The errors
+// err wraps are not released. Neither err nor a copy of it may be used
+// after it is released, e.g. once it was sent with HTTPError:
+//
+//	err := errors.RE(http.StatusBadGateway, errors.IO, upstreamErr)
+//	errors.HTTPError(w, err)
+//	errors.Release(err)
+//
+// Release does nothing when PoolErrors is false, so it can be called
+// whether pooling is enabled or not. It does nothing either while
+// reporting is asynchronous (see SetAsyncReporting): the Metrics, the
+// Reporter and the AuditSink may then be notified of err after it was
+// sent, so it is left to the garbage collector instead.
+func Release(err error) {
+	if !PoolErrors || asyncReporting() {
+		return
+	}
+	switch e := err.(type) {
+	case *Error:
+		if e != nil {
+			// Keep the array of the ops for the next error
+			*e = Error{ops: e.ops[:0]}
+			errorPool.Put(e)
+		}
+	case *HTTPErr:
+		if e != nil {
+			// Keep the array of the ops for the next error
+			*e = HTTPErr{ops: e.ops[:0]}
+			httpErrPool.Put(e)
+		}
+	}
+}
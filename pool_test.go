@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRelease(t *testing.T) {
+	defer func(prev bool) { PoolErrors = prev }(PoolErrors)
+	PoolErrors = true
+
+	err := E(Op("db.Get"), NotExist, Code("no_user"), Str("user not found"))
+	Release(err)
+	e := E(Op("db.Get"), Str("other")).(*Error)
+	if e.Kind != Other || e.Code != "" {
+		t.Errorf("E() after Release = %+v; want no Kind or Code from the released error", e)
+	}
+	Release(e)
+
+	hse := RE(http.StatusNotFound, NotExist, Code("no_user"), UserMsg("not found"))
+	Release(hse)
+	got := RE(http.StatusBadRequest).(*HTTPErr)
+	if got.Kind != Other || got.Code != "" || got.UserMsg != "" {
+		t.Errorf("RE() after Release = %+v; want no Kind, Code or UserMsg from the released error", got)
+	}
+
+	// Other errors and nil are ignored
+	Release(Str("some error"))
+	Release(nil)
+}
+
+func TestReleaseAsyncReporting(t *testing.T) {
+	defer func(prev bool) { PoolErrors = prev }(PoolErrors)
+	PoolErrors = true
+	defer SetReporter(nil)
+	tr := &testReporter{}
+	SetReporter(tr)
+	defer SetAsyncReporting(0)
+	SetAsyncReporting(1)
+
+	// The queued report still reads the error after it is released
+	err := RE(http.StatusBadGateway, IO, Code("upstream_down"), Str("connection reset"))
+	HTTPError(httptest.NewRecorder(), err)
+	Release(err)
+	if err := Close(context.Background()); err != nil {
+		t.Fatalf("Close() = %v; want nil", err)
+	}
+	if len(tr.infos) != 1 || tr.infos[0].Code != "upstream_down" {
+		t.Errorf("reported %+v; want the error with Code upstream_down", tr.infos)
+	}
+	if hse := err.(*HTTPErr); hse.Code != "upstream_down" {
+		t.Errorf("Release() reset %+v while reporting is asynchronous", hse)
+	}
+}
+
+func benchmarkConstruction(b *testing.B, pool bool, f func() error) {
+	defer func(stack, location, pooled bool) {
+		CaptureStack, CaptureLocation, PoolErrors = stack, location, pooled
+	}(CaptureStack, CaptureLocation, PoolErrors)
+	// The configuration of a proxy creating many errors
+	CaptureStack, CaptureLocation, PoolErrors = false, false, pool
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := f()
+		if pool {
+			Release(err)
+		}
+	}
+}
+
+var errUpstream = Str("upstream unavailable")
+
+func newE() error {
+	return E(Op("proxy.Forward"), IO, Code("Unavailable"), errUpstream)
+}
+
+func newRE() error {
+	return RE(http.StatusBadGateway, IO, Code("Unavailable"), Op("proxy.Forward"), errUpstream)
+}
+
+func BenchmarkE(b *testing.B)        { benchmarkConstruction(b, false, newE) }
+func BenchmarkEPooled(b *testing.B)  { benchmarkConstruction(b, true, newE) }
+func BenchmarkRE(b *testing.B)       { benchmarkConstruction(b, false, newRE) }
+func BenchmarkREPooled(b *testing.B) { benchmarkConstruction(b, true, newRE) }
+
+func BenchmarkEWithStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = newE()
+	}
+}
@@ -0,0 +1,80 @@
+package errors
+
+import "sync"
+
+// DefaultKindPrecedence is the default precedence of the Kinds, from
+// the most to the least severe: server errors come before client
+// errors, so a group with any server error is sent as one.
+var DefaultKindPrecedence = []Kind{
+	Internal,
+	Unanticipated,
+	Database,
+	IO,
+	Timeout,
+	Canceled,
+	Unauthorized,
+	Permission,
+	Private,
+	NotExist,
+	Exist,
+	BrokenLink,
+	Invalid,
+	InvalidRequest,
+	Validation,
+}
+
+var (
+	precedenceMu sync.RWMutex
+	// kindPrecedence is the precedence set with SetKindPrecedence.
+	kindPrecedence = append([]Kind(nil), DefaultKindPrecedence...)
+)
+
+// SetKindPrecedence sets the precedence of the Kinds, from the most to
+// the least severe, which determines the Kind of the errors merging
+// several errors: the Kind of a GroupError, and of a BatchError, is the
+// most severe Kind of its errors, and a GroupError is sent with the
+// status code of its first error of that Kind. Kinds which are not
+// listed are less severe than all those listed, and equally so, so the
+// first error with one of them wins. If kinds is nil,
+// DefaultKindPrecedence is restored. For example, to have permission
+// errors win over all others:
+//
+//	errors.SetKindPrecedence(append([]errors.Kind{errors.Permission}, errors.DefaultKindPrecedence...))
+func SetKindPrecedence(kinds []Kind) {
+	if kinds == nil {
+		kinds = DefaultKindPrecedence
+	}
+	precedenceMu.Lock()
+	defer precedenceMu.Unlock()
+	kindPrecedence = append([]Kind(nil), kinds...)
+}
+
+// kindRank returns the position of k in the precedence of the Kinds,
+// or the number of Kinds it lists if k is not listed. The first
+// position of k counts if it is listed more than once.
+func kindRank(k Kind) int {
+	precedenceMu.RLock()
+	defer precedenceMu.RUnlock()
+	for i, pk := range kindPrecedence {
+		if pk == k {
+			return i
+		}
+	}
+	return len(kindPrecedence)
+}
+
+// mostSevere returns the first of errs whose Kind, as HTTPError sends
+// it, is the most severe, or nil if errs is empty.
+func mostSevere(errs []error) error {
+	var (
+		dom  error
+		rank int
+	)
+	for _, err := range errs {
+		k, _ := classify(err)
+		if r := kindRank(k); dom == nil || r < rank {
+			dom, rank = err, r
+		}
+	}
+	return dom
+}
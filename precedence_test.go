@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetKindPrecedence(t *testing.T) {
+	defer SetKindPrecedence(nil)
+	validation := RE(http.StatusBadRequest, Validation, Str("name is required"))
+	permission := RE(http.StatusForbidden, Permission, Str("access denied"))
+	internal := RE(http.StatusInternalServerError, Internal, Str("inconsistent state"))
+	errs := []error{validation, permission, internal}
+	tests := []struct {
+		name       string
+		precedence []Kind
+		want       Kind
+	}{
+		{"Default", nil, Internal},
+		{"Custom", []Kind{Permission, Internal}, Permission},
+		{"Unlisted", []Kind{Exist}, Validation},
+		{"Empty", []Kind{}, Validation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetKindPrecedence(tt.precedence)
+			ge := &GroupError{Errors: errs}
+			if got := KindOf(ge); got != tt.want {
+				t.Errorf("KindOf(GroupError) = %v; want %v", got, tt.want)
+			}
+			be := NewBatchError(len(errs))
+			for i, err := range errs {
+				be.Add(i, err)
+			}
+			if got := KindOf(be); got != tt.want {
+				t.Errorf("KindOf(BatchError) = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
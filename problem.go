@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// ProblemDetails determines whether HTTPError sends error responses
+// in the RFC 7807 "Problem Details for HTTP APIs" format
+// (application/problem+json) instead of the ErrResponse format.
+// It is false by default.
+var ProblemDetails = false
+
+// ProblemTypeURI is the base URI used to build the "type" member of a
+// ProblemResponse. When set, the error Code is appended to it, e.g.
+// "https://example.com/probs/" + "out_of_credit". When empty, or when
+// the error has no Code, the type is "about:blank" as per RFC 7807.
+// The documentation URL registered for a Code with RegisterDocURL, if
+// any, is used as the type instead.
+var ProblemTypeURI = ""
+
+// ProblemResponse is used as the Response Body when sending errors
+// in the RFC 7807 format. Kind, Code, Param and, for ValidationErrors,
+// the lists of Errors and Fields are sent as extension members. All
+// fields with no data will be omitted.
+type ProblemResponse struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	// Instance is the path of the request which failed, if known
+	// (see RequestContext and RequestInfoHandler)
+	Instance string `json:"instance,omitempty"`
+	Kind     string `json:"kind,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Param    string `json:"param,omitempty"`
+	// Errors lists each error of a ValidationErrors
+	Errors []ServiceError `json:"errors,omitempty"`
+	// Fields lists the errors of a ValidationErrors which relate
+	// to a parameter
+	Fields []FieldViolation `json:"fields,omitempty"`
+	// RequestID is the ID of the request which failed, if known
+	RequestID string `json:"request_id,omitempty"`
+	// ErrorID is the ID of the error response (see ErrorIDFunc)
+	ErrorID string `json:"error_id,omitempty"`
+	// TraceID is the ID of the trace of the request, if known
+	TraceID string `json:"trace_id,omitempty"`
+	// Chain lists the nested errors of the error in debug responses
+	Chain []ChainLink `json:"chain,omitempty"`
+}
+
+// NewProblemResponse builds a ProblemResponse from err, using the same
+// rules HTTPError uses to determine the status code and error fields.
+func NewProblemResponse(err error) ProblemResponse {
+	return problemResponse(serviceError(err))
+}
+
+// problemResponse builds a ProblemResponse from the status code and
+// ServiceError determined for an error.
+func problemResponse(status int, se *ServiceError) ProblemResponse {
+	pr := ProblemResponse{
+		Type:   "about:blank",
+		Title:  ReasonPhrase(status),
+		Status: status,
+	}
+	if se == nil {
+		return pr
+	}
+	if u := DocURL(Code(se.Code)); u != "" {
+		pr.Type = u
+	} else if ProblemTypeURI != "" && se.Code != "" {
+		pr.Type = ProblemTypeURI + se.Code
+	}
+	truncateServiceError(se)
+	pr.Detail = se.Message
+	pr.Kind = se.Kind
+	pr.Code = se.Code
+	pr.Param = se.Param
+	pr.Errors = se.Errors
+	pr.Fields = fieldViolations(se.Errors)
+	return pr
+}
+
+// HTTPProblem sends err to the client as an RFC 7807 Problem Details
+// response with a Content-Type of application/problem+json. The status
+// code and error fields are determined in the same way as HTTPError.
+func HTTPProblem(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	err = soundChain(err)
+	ctx := withErrorID(context.Background())
+	logHTTPError(ctx, err, Fields{})
+	httpProblem(ctx, w, err)
+}
+
+// problemInstance returns the instance of the problem of the request
+// of ctx: the path of the request kept by RequestContext or, if there
+// is none, the Path of its RequestInfo, if any. The query is left out,
+// as it may hold sensitive data.
+func problemInstance(ctx context.Context) string {
+	if r, ok := ctx.Value(requestKey).(*http.Request); ok && r.URL != nil {
+		return r.URL.EscapedPath()
+	}
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		return info.Path
+	}
+	return ""
+}
+
+// httpProblem sends err as an RFC 7807 Problem Details response,
+// adding the request ID found in ctx, if any. It does not log err.
+func httpProblem(ctx context.Context, w http.ResponseWriter, err error) {
+	setCORS(ctx, w)
+	rid := RequestIDFunc(ctx)
+
+	status, se := serviceError(err)
+	errorSent(ctx, err, status)
+	Redaction.redactServiceError(se)
+	localize(ctx, se)
+	pr := problemResponse(status, se)
+	pr.Instance = problemInstance(ctx)
+	pr.RequestID = rid
+	pr.ErrorID = errorIDFromContext(ctx)
+	pr.TraceID = tracer.TraceID(ctx)
+	if se != nil && debugEnabled(ctx) {
+		pr.Chain = chain(err)
+	}
+
+	// Marshal ProblemResponse struct to JSON for the response body
+	errJSON, merr := marshalResponse(pr)
+	if merr != nil {
+		body := `{"type":"about:blank","title":"` + ReasonPhrase(pr.Status) + `","status":` + strconv.Itoa(pr.Status) + `}`
+		sendMarshalFailure(w, pr.Status, merr, "application/problem+json", body)
+		return
+	}
+
+	setRetryAfter(w, err)
+	setRateLimit(w, err)
+	setFingerprint(w, err)
+	setHeaders(w, err)
+	writeBody(ctx, w, pr.Status, "application/problem+json", append(errJSON, '\n'))
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHTTPProblem(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ProblemResponse
+	}{
+		{"HTTPErr", RE(http.StatusBadRequest, Validation, Code("invalid_date"), Parameter("date"), Str("date is invalid")),
+			ProblemResponse{Type: "about:blank", Title: "Bad Request", Status: 400, Detail: "date is invalid", Kind: "input_validation_error", Code: "invalid_date", Param: "date"}},
+		{"Status Only", RE(http.StatusNotFound),
+			ProblemResponse{Type: "about:blank", Title: "Not Found", Status: 404}},
+		{"Unknown", Str("some error"),
+			ProblemResponse{Type: "about:blank", Title: "Internal Server Error", Status: 500, Detail: "Unexpected error - contact support", Kind: "unanticipated_error", Code: "Unanticipated"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPProblem(rr, tt.err)
+
+			if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q; want %q", ct, "application/problem+json")
+			}
+			if rr.Code != tt.want.Status {
+				t.Errorf("status = %d; want %d", rr.Code, tt.want.Status)
+			}
+			var got ProblemResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HTTPProblem() body = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblemTypeURI(t *testing.T) {
+	defer func(prev string) {
+		ProblemTypeURI = prev
+	}(ProblemTypeURI)
+	ProblemTypeURI = "https://example.com/probs/"
+
+	pr := NewProblemResponse(RE(http.StatusForbidden, Code("out_of_credit")))
+	if want := "https://example.com/probs/out_of_credit"; pr.Type != want {
+		t.Errorf("Type = %q; want %q", pr.Type, want)
+	}
+}
+
+func TestProblemInstance(t *testing.T) {
+	defer func() { ProblemDetails = false }()
+	ProblemDetails = true
+	r := httptest.NewRequest(http.MethodGet, "/users/1?token=abc123", nil)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"Request", RequestContext(r), "/users/1"},
+		{"RequestInfo", WithRequestInfo(context.Background(), RequestInfo{Method: http.MethodGet, Path: "/users/2"}), "/users/2"},
+		{"None", context.Background(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPErrorCtx(tt.ctx, rr, NotFound("users.Get", "no such user"))
+			var got ProblemResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got.Instance != tt.want {
+				t.Errorf("Instance = %q; want %q", got.Instance, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// reportQueue delivers notifications of sent errors in a background
+// goroutine.
+type reportQueue struct {
+	jobs chan func()
+	done chan struct{}
+}
+
+var (
+	queueMu sync.RWMutex
+	// queue is the queue of the notifications of sent errors, or nil
+	// if they are delivered synchronously.
+	queue *reportQueue
+)
+
+// SetAsyncReporting makes the Metrics, the Reporter and the AuditSink
+// be notified of the errors sent by a background goroutine, through a
+// queue of the given size, so slow implementations, e.g. sending errors
+// over the network, never block the request path. When the queue is
+// full, the notification of an error is dropped, and a warning logged.
+// The Tracer is still notified synchronously, as spans are usually
+// ended with the request. Implementations must then be safe for
+// concurrent use, and must not expect the context they are given to
+// be canceled with the request.
+//
+// If size is 0 or less, the notifications are delivered synchronously,
+// which is the default. Call Close at shutdown to deliver the pending
+// notifications.
+func SetAsyncReporting(size int) {
+	var q *reportQueue
+	if size > 0 {
+		q = &reportQueue{jobs: make(chan func(), size), done: make(chan struct{})}
+		go q.run()
+	}
+	queueMu.Lock()
+	prev := queue
+	queue = q
+	queueMu.Unlock()
+	if prev != nil {
+		prev.close(context.Background())
+	}
+}
+
+// Close delivers the notifications of sent errors still pending in
+// the queue of SetAsyncReporting, then stops its goroutine, so that
+// notifications are delivered synchronously again. If ctx is done
+// first, Close returns its error, and the remaining notifications are
+// delivered in the background. Close does nothing if reporting is
+// synchronous.
+func Close(ctx context.Context) error {
+	queueMu.Lock()
+	q := queue
+	queue = nil
+	queueMu.Unlock()
+	if q == nil {
+		return nil
+	}
+	return q.close(ctx)
+}
+
+// run delivers the notifications of the queue until it is closed.
+func (q *reportQueue) run() {
+	defer close(q.done)
+	for job := range q.jobs {
+		job()
+	}
+}
+
+// close closes the queue and waits for its pending notifications to
+// be delivered, or for ctx to be done.
+func (q *reportQueue) close(ctx context.Context) error {
+	close(q.jobs)
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// asyncReporting reports whether the notifications of sent errors are
+// delivered in the background.
+func asyncReporting() bool {
+	queueMu.RLock()
+	defer queueMu.RUnlock()
+	return queue != nil
+}
+
+// dispatch runs job, the notification of a sent error, in the queue
+// if reporting is asynchronous, or else right away.
+func dispatch(job func()) {
+	// The read lock keeps the queue from being closed while the job
+	// is sent to it
+	queueMu.RLock()
+	defer queueMu.RUnlock()
+	if queue == nil {
+		job()
+		return
+	}
+	select {
+	case queue.jobs <- job:
+	default:
+		logger.Log(WarnLevel, "error report queue full, report dropped", Fields{})
+	}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// blockingReporter is a Reporter which blocks until it is released.
+type blockingReporter struct {
+	release  chan struct{}
+	reported chan ReportInfo
+}
+
+func (br *blockingReporter) Report(_ context.Context, _ error, info ReportInfo) {
+	<-br.release
+	br.reported <- info
+}
+
+func TestAsyncReporting(t *testing.T) {
+	defer SetReporter(nil)
+	br := &blockingReporter{release: make(chan struct{}), reported: make(chan ReportInfo, 2)}
+	SetReporter(br)
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+	defer SetAsyncReporting(0)
+	SetAsyncReporting(1)
+
+	// The first report is taken by the goroutine of the queue, the
+	// second is queued and the third dropped, without blocking
+	for i := 0; i < 3; i++ {
+		HTTPErrorCtx(context.Background(), httptest.NewRecorder(), RE(http.StatusInternalServerError, Internal, Code("db_down")))
+	}
+	close(br.release)
+	if err := Close(context.Background()); err != nil {
+		t.Fatalf("Close() = %v; want nil", err)
+	}
+	if got := len(br.reported); got < 1 || got > 2 {
+		t.Errorf("reported %d errors; want 1 or 2", got)
+	}
+	var dropped int
+	for _, e := range tl.entries {
+		if e.msg == "error report queue full, report dropped" {
+			dropped++
+		}
+	}
+	if dropped+len(br.reported) != 3 {
+		t.Errorf("dropped %d and reported %d errors; want 3 in all", dropped, len(br.reported))
+	}
+
+	// After Close, errors are reported synchronously again
+	br.reported = make(chan ReportInfo, 1)
+	HTTPError(httptest.NewRecorder(), RE(http.StatusInternalServerError, Internal))
+	if got := len(br.reported); got != 1 {
+		t.Errorf("reported %d errors after Close; want 1", got)
+	}
+}
+
+func TestCloseTimeout(t *testing.T) {
+	defer SetReporter(nil)
+	br := &blockingReporter{release: make(chan struct{}), reported: make(chan ReportInfo, 1)}
+	SetReporter(br)
+	SetAsyncReporting(1)
+	defer func() {
+		// Wait for the report left in the background
+		close(br.release)
+		<-br.reported
+	}()
+
+	HTTPError(httptest.NewRecorder(), RE(http.StatusInternalServerError, Internal))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Close(ctx); err != context.Canceled {
+		t.Errorf("Close() = %v; want %v", err, context.Canceled)
+	}
+	if err := Close(context.Background()); err != nil {
+		t.Errorf("Close() of synchronous reporting = %v; want nil", err)
+	}
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit describes the rate limit exceeded by a request. Given to
+// RE, it makes an error which HTTPError sends as an HTTP 429 (Too Many
+// Requests) with the rate limit headers, e.g.
+//
+//	errors.RE(errors.RateLimit{Limit: 100, Reset: windowEnd})
+type RateLimit struct {
+	// Limit is the number of requests allowed in the window.
+	Limit int `json:"limit"`
+	// Remaining is the number of requests remaining in the window.
+	Remaining int `json:"remaining"`
+	// Reset is the time at which the window resets, if known.
+	Reset time.Time `json:"reset,omitempty"`
+}
+
+// setRateLimit marks hse as a rate limit error, with the HTTP status
+// code, Code and message of such errors if they are not given.
+func (hse *HTTPErr) setRateLimit(rl RateLimit) {
+	hse.RateLimit = &rl
+	hse.Retryable = true
+	if hse.HTTPStatusCode == 0 {
+		hse.HTTPStatusCode = http.StatusTooManyRequests
+	}
+	if hse.Code == "" {
+		hse.Code = "RateLimited"
+	}
+	if hse.Err == nil {
+		hse.Err = Str("Too many requests - try again later")
+	}
+}
+
+// rateLimitOf returns the RateLimit of the first HTTPErr in the chain
+// of err which has one, or nil if there is none.
+func rateLimitOf(err error) *RateLimit {
+	for _, err := range chainOf(err) {
+		if e, ok := err.(*HTTPErr); ok && e.RateLimit != nil {
+			return e.RateLimit
+		}
+	}
+	return nil
+}
+
+// setRateLimit sets the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers of the response if a RateLimit is attached
+// to err. Unless a retry delay is attached to err, the Retry-After
+// header is set to the time remaining until the reset.
+func setRateLimit(w http.ResponseWriter, err error) {
+	rl := rateLimitOf(err)
+	if rl == nil {
+		return
+	}
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(rl.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(rl.Remaining))
+	if rl.Reset.IsZero() {
+		return
+	}
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(rl.Reset.Unix(), 10))
+	if d := rl.Reset.Sub(now()); h.Get("Retry-After") == "" && d > 0 {
+		secs := int64((d + time.Second - 1) / time.Second)
+		h.Set("Retry-After", strconv.FormatInt(secs, 10))
+	}
+}
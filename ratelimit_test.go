@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	defer func() { now = time.Now }()
+	t0 := time.Unix(1700000000, 0)
+	now = func() time.Time { return t0 }
+	reset := t0.Add(90 * time.Second)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantHeader map[string]string
+		wantCode   string
+	}{
+		{"Defaults", RE(RateLimit{Limit: 100, Reset: reset}), map[string]string{
+			"X-RateLimit-Limit":     "100",
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     "1700000090",
+			"Retry-After":           "90",
+		}, "RateLimited"},
+		{"Retry given", RE(RateLimit{Limit: 10, Remaining: 2, Reset: reset}, Retry(5*time.Second), Code("quota")), map[string]string{
+			"X-RateLimit-Limit":     "10",
+			"X-RateLimit-Remaining": "2",
+			"Retry-After":           "5",
+		}, "quota"},
+		{"No reset", RE(RateLimit{Limit: 10}), map[string]string{
+			"X-RateLimit-Limit": "10",
+			"X-RateLimit-Reset": "",
+			"Retry-After":       "",
+		}, "RateLimited"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			if rr.Code != http.StatusTooManyRequests {
+				t.Errorf("status = %d; want %d", rr.Code, http.StatusTooManyRequests)
+			}
+			for k, want := range tt.wantHeader {
+				if got := rr.Header().Get(k); got != want {
+					t.Errorf("header %s = %q; want %q", k, got, want)
+				}
+			}
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Code != tt.wantCode || er.Error.Message == "" {
+				t.Errorf("body = %+v; want Code %q and a message", er.Error, tt.wantCode)
+			}
+			if !IsRetryable(tt.err) {
+				t.Error("IsRetryable() = false; want true")
+			}
+		})
+	}
+}
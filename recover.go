@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Recoverer is middleware which recovers from panics in next, so they
+// are reported in the same format as any other error. The panic is
+// wrapped in an *HTTPErr with Kind Unanticipated and the stack of the
+// panic, which is logged with the panic value and sent with
+// HTTPErrorCtx, as an HTTP 500 with a generic message.
+//
+// Panics with http.ErrAbortHandler, which are used to abort a response,
+// are not recovered.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w = TrackWrites(w)
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			// The stack is recorded even if CaptureStack is false,
+			// as panics are rare and hard to debug without it
+			err := &HTTPErr{
+				HTTPStatusCode: http.StatusInternalServerError,
+				Kind:           Unanticipated,
+				Code:           "Unanticipated",
+				Err:            Str("Unexpected error - contact support"),
+				trace:          recordStack(1),
+			}
+			ctx := withErrorID(RequestContext(r))
+			f := requestFields(RequestIDFunc(ctx))
+			f["panic"] = fmt.Sprint(rec)
+			logHTTPError(ctx, err, f)
+			writeHTTPError(ctx, w, err)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// FromPanic returns an error of Kind Internal and Code Panic for op,
+// which wraps the value rec recovered from a panic, with the stack of
+// the panic, for recover blocks outside Recoverer, e.g. in goroutines
+// and workers:
+//
+//	defer func() {
+//		if err := errors.FromPanic(recover(), op); err != nil {
+//			errors.ReportError(ctx, err, nil)
+//		}
+//	}()
+//
+// If rec is an error, it is wrapped as is, so it can be inspected with
+// errors.Is and errors.As; other values are wrapped in an error with
+// the message "panic: " followed by the value. As with InternalError,
+// HTTPError sends the error as an HTTP 500 with a generic message. The
+// stack is recorded even if CaptureStack is false, as in Recoverer. If
+// rec is nil, FromPanic returns nil.
+func FromPanic(rec interface{}, op Op) error {
+	if rec == nil {
+		return nil
+	}
+	var err error
+	switch v := rec.(type) {
+	case *Error:
+		// Make a copy, as E does
+		copy := *v
+		err = &copy
+	case error:
+		err = v
+	default:
+		err = Str(fmt.Sprintf("panic: %v", v))
+	}
+	e := &Error{Op: op, Kind: Internal, Code: "Panic", Err: err}
+	e.populateStack()
+	e.trace = recordStack(1)
+	e.pc = captureLocation(0)
+	e.recordCreated()
+	return e
+}
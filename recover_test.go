@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverer(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	h := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("something broke")
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusInternalServerError)
+	}
+	var er ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if er.Error.Kind != Unanticipated.String() || er.Error.Message != "Unexpected error - contact support" {
+		t.Errorf("body = %+v; want Unanticipated error with a generic message", er.Error)
+	}
+	if len(tl.entries) != 1 {
+		t.Fatalf("got %d log entries; want 1", len(tl.entries))
+	}
+	f := tl.entries[0].fields
+	if f["panic"] != "something broke" {
+		t.Errorf("fields[panic] = %v; want %q", f["panic"], "something broke")
+	}
+	if stack, _ := f["stack"].(string); !strings.Contains(stack, "recover_test.go") {
+		t.Errorf("fields[stack] = %q; want the stack of the panic", stack)
+	}
+}
+
+func TestRecovererAbortHandler(t *testing.T) {
+	h := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("recover() = %v; want http.ErrAbortHandler", rec)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestFromPanic(t *testing.T) {
+	const op Op = "worker.Run"
+	tests := []struct {
+		name    string
+		panic   interface{}
+		wantMsg string
+		wantErr error
+	}{
+		{"error", errSentinel, "sentinel error", errSentinel},
+		{"string", "index out of range", "panic: index out of range", nil},
+		{"other", 42, "panic: 42", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			func() {
+				defer func() {
+					err = FromPanic(recover(), op)
+				}()
+				panic(tt.panic)
+			}()
+			if KindOf(err) != Internal || CodeOf(err) != "Panic" {
+				t.Errorf("KindOf(), CodeOf() = %v, %q; want Internal, Panic", KindOf(err), CodeOf(err))
+			}
+			if got := Root(err).Error(); got != tt.wantMsg {
+				t.Errorf("message = %q; want %q", got, tt.wantMsg)
+			}
+			if tt.wantErr != nil && !stderrors.Is(err, tt.wantErr) {
+				t.Errorf("errors.Is(err, %v) = false; want true", tt.wantErr)
+			}
+			if got := Ops(err); len(got) != 1 || got[0] != op {
+				t.Errorf("Ops() = %v; want [%s]", got, op)
+			}
+			if st := stackOf(err); !strings.Contains(st.String(), "TestFromPanic") {
+				t.Errorf("stack = %s; want the stack of the panic", st)
+			}
+		})
+	}
+	if err := FromPanic(nil, op); err != nil {
+		t.Errorf("FromPanic(nil) = %v; want nil", err)
+	}
+}
@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultMask is the text which replaces redacted data when the
+// RedactionPolicy has no Mask.
+const DefaultMask = "[REDACTED]"
+
+// Patterns matching data which commonly ends up in error messages,
+// for use in a RedactionPolicy.
+var (
+	// SQLPattern matches SQL statements, from the statement keyword
+	// to the end of the message.
+	SQLPattern = regexp.MustCompile(`(?is)\b(select|insert|update|delete)\b.*`)
+	// CredentialPattern matches credentials given as key/value pairs,
+	// e.g. "password=secret" or "token: abc", and bearer tokens.
+	CredentialPattern = regexp.MustCompile(`(?i)\b((password|passwd|pwd|secret|token|api[_-]?key)\s*[=:]\s*\S+|bearer\s+\S+)`)
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactionPolicy determines which parts of error messages are
+// sensitive. Sensitive data is masked before errors are sent to the
+// client by HTTPError, but errors are always logged in full.
+type RedactionPolicy struct {
+	// Patterns are matched against error messages. Each match is
+	// replaced with the Mask.
+	Patterns []*regexp.Regexp
+	// Params are the parameters whose values are sensitive. The
+	// message of an error for one of these parameters is replaced
+	// with the Mask entirely, as is its invalid value (see Param).
+	Params []Parameter
+	// Mask is the text which replaces redacted data. If empty,
+	// DefaultMask is used.
+	Mask string
+}
+
+// Redaction is the RedactionPolicy applied to error responses. By
+// default, nothing is redacted. For example, to mask SQL statements
+// and credentials, and any error about the password parameter:
+//
+//	errors.Redaction = errors.RedactionPolicy{
+//		Patterns: []*regexp.Regexp{errors.SQLPattern, errors.CredentialPattern},
+//		Params:   []errors.Parameter{"password"},
+//	}
+var Redaction RedactionPolicy
+
+// mask returns the Mask of the policy, or DefaultMask if it has none.
+func (p RedactionPolicy) mask() string {
+	if p.Mask == "" {
+		return DefaultMask
+	}
+	return p.Mask
+}
+
+// redact returns msg, the message of an error for the parameter param,
+// with its sensitive data masked.
+func (p RedactionPolicy) redact(param Parameter, msg string) string {
+	if msg == "" {
+		return msg
+	}
+	if p.sensitive(param) {
+		return p.mask()
+	}
+	for _, re := range p.Patterns {
+		msg = re.ReplaceAllString(msg, p.mask())
+	}
+	return msg
+}
+
+// sensitive reports whether param is one of the sensitive Params, or
+// one of the MaskedParams.
+func (p RedactionPolicy) sensitive(param Parameter) bool {
+	if param == "" {
+		return false
+	}
+	if masked(param) {
+		return true
+	}
+	for _, sp := range p.Params {
+		if sp == param {
+			return true
+		}
+	}
+	return false
+}
+
+// redactServiceError masks the sensitive data in the messages of se
+// and of the errors it lists.
+func (p RedactionPolicy) redactServiceError(se *ServiceError) {
+	if se == nil {
+		return
+	}
+	// The invalid value of a sensitive parameter is not sent either
+	if se.Got != nil && p.sensitive(Parameter(se.Param)) {
+		se.Got = p.mask()
+	}
+	if len(se.Errors) == 0 {
+		se.Message = p.redact(Parameter(se.Param), se.Message)
+		return
+	}
+	// The message of a ValidationErrors joins the message of each of
+	// its errors, so it is rebuilt from the redacted messages
+	msgs := make([]string, len(se.Errors))
+	for i := range se.Errors {
+		p.redactServiceError(&se.Errors[i])
+		msgs[i] = se.Errors[i].Message
+	}
+	se.Message = strings.Join(msgs, "; ")
+}
+
+// Redact returns err with the sensitive data in its message masked,
+// as determined by the Redaction policy. The returned error unwraps to
+// err, so the original error can still be inspected with errors.Is
+// and errors.As. An *HTTPErr is returned as an *HTTPErr with the same
+// fields, so it is sent to the client in the same way. If err is nil,
+// Redact returns nil.
+func Redact(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *HTTPErr:
+		re := copyHTTPErr(e)
+		if e.Err != nil {
+			re.Err = &strippedError{s: Redaction.redact(e.Param, e.Err.Error()), err: e.Err}
+		}
+		return re
+	case *Error:
+		return &strippedError{s: Redaction.redact(e.Param, e.Error()), err: e}
+	}
+	return &strippedError{s: Redaction.redact("", err.Error()), err: err}
+}
@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	defer func(prev RedactionPolicy) {
+		Redaction = prev
+	}(Redaction)
+	Redaction = RedactionPolicy{
+		Patterns: []*regexp.Regexp{SQLPattern, CredentialPattern, EmailPattern},
+		Params:   []Parameter{"ssn"},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"SQL", Str("query failed: SELECT * FROM users WHERE id = 1"), "query failed: [REDACTED]"},
+		{"Credential", Str("connect: password=hunter2 refused"), "connect: [REDACTED] refused"},
+		{"Bearer", Str("bad header Bearer abc.def"), "bad header [REDACTED]"},
+		{"Email", RE(http.StatusConflict, Exist, Str("jane@example.com already exists")), "[REDACTED] already exists"},
+		{"Param", RE(http.StatusBadRequest, Validation, Parameter("ssn"), Str("123-45-6789 is not valid")), "[REDACTED]"},
+		{"Nothing to redact", Str("not found"), "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(tt.err)
+			if got.Error() != tt.want {
+				t.Errorf("Redact().Error() = %q; want %q", got.Error(), tt.want)
+			}
+			// A redacted HTTPErr is a copy, which wraps the original
+			// underlying error
+			orig := tt.err
+			if he, ok := orig.(*HTTPErr); ok {
+				orig = he.Err
+			}
+			if !stderrors.Is(got, orig) {
+				t.Error("Redact() does not wrap the original error")
+			}
+		})
+	}
+	if Redact(nil) != nil {
+		t.Error("Redact(nil) != nil")
+	}
+}
+
+func TestRedactCopy(t *testing.T) {
+	e := RE(http.StatusTooManyRequests, Op("users.Get"), Header("X-Upstream", "users"), RateLimit{Limit: 10}, Str("slow down")).(*HTTPErr)
+	re := Redact(e).(*HTTPErr)
+	e.Headers.Set("X-Upstream", "changed")
+	e.RateLimit.Limit = 20
+	e.ops[0] = "changed"
+	if re.Headers.Get("X-Upstream") != "users" || re.RateLimit.Limit != 10 || Ops(re)[0] != "users.Get" {
+		t.Errorf("Redact() = %+v; shares its headers, rate limit or ops with the original", re)
+	}
+}
+
+func TestHTTPErrorRedacted(t *testing.T) {
+	defer SetLogger(nil)
+	defer func(prev RedactionPolicy) {
+		Redaction = prev
+	}(Redaction)
+	Redaction = RedactionPolicy{
+		Patterns: []*regexp.Regexp{SQLPattern},
+		Params:   []Parameter{"password"},
+		Mask:     "***",
+	}
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	var ve ValidationErrors
+	ve.Add(RE(http.StatusBadRequest, Validation, Parameter("password"), Str("hunter2 is too short")))
+	tests := []struct {
+		name     string
+		err      error
+		wantMsg  string
+		wantLogs string
+	}{
+		{"HTTPErr", RE(http.StatusInternalServerError, Database, Str("exec: DELETE FROM users")), "exec: ***", "DELETE FROM users"},
+		{"ValidationErrors", ve.Err(), "***", "hunter2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl.entries = nil
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			msg := er.Error.Message
+			if len(er.Error.Errors) > 0 {
+				msg = er.Error.Errors[0].Message
+			}
+			if msg != tt.wantMsg {
+				t.Errorf("message = %q; want %q", msg, tt.wantMsg)
+			}
+			if strings.Contains(rr.Body.String(), tt.wantLogs) {
+				t.Errorf("body = %s; want %q redacted", rr.Body.String(), tt.wantLogs)
+			}
+			if len(tl.entries) != 1 || !strings.Contains(tl.entries[0].msg, tt.wantLogs) {
+				t.Errorf("log entries = %v; want full message logged", tl.entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package errors
+
+import "context"
+
+// ReportError logs err and notifies the Metrics and the Tracer that
+// it was sent, as HTTPErrorCtx does, for transports which send errors
+// themselves, such as gRPC. The status code given to the Metrics and
+// the Tracer is the HTTP status code HTTPError would send for err. The
+// fields f, which may be nil, are added to the log entry, along with
+// the request ID found in ctx. If err is nil, ReportError does nothing.
+func ReportError(ctx context.Context, err error, f Fields) {
+	if err == nil {
+		return
+	}
+	err = soundChain(err)
+	lf := requestFields(RequestIDFunc(ctx))
+	for k, v := range f {
+		lf[k] = v
+	}
+	logHTTPError(ctx, err, lf)
+	status, _ := serviceError(err)
+	errorSent(ctx, err, status)
+}
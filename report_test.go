@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestReportError(t *testing.T) {
+	defer SetLogger(nil)
+	defer SetMetrics(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+	tm := &testMetrics{}
+	SetMetrics(tm)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ReportError(ctx, RE(http.StatusNotFound, NotExist, Code("no_user"), Str("user not found")), Fields{"grpc_method": "/users.Users/Get"})
+	ReportError(ctx, nil, nil)
+
+	if len(tl.entries) != 1 {
+		t.Fatalf("got %d log entries; want 1", len(tl.entries))
+	}
+	f := tl.entries[0].fields
+	for k, want := range map[string]interface{}{"request_id": "req-1", "grpc_method": "/users.Users/Get", "code": "no_user", "status": 404} {
+		if f[k] != want {
+			t.Errorf("fields[%s] = %v; want %v", k, f[k], want)
+		}
+	}
+	if len(tm.sent) != 1 || tm.sent[0] != "item_does_not_exist/no_user/Not Found" {
+		t.Errorf("ErrorSent calls = %v; want one for item_does_not_exist/no_user/Not Found", tm.sent)
+	}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import "context"
+
+// Reporter sends errors to an exception tracking service, such as
+// Sentry (see the sentryerrors package). Register an implementation
+// with SetReporter.
+type Reporter interface {
+	// Report is called for each server error, i.e. with an HTTP
+	// status code of 500 or above, sent by HTTPError, and for each
+	// error given to Report.
+	Report(ctx context.Context, err error, info ReportInfo)
+}
+
+// ReportInfo describes an error given to a Reporter.
+type ReportInfo struct {
+	// Kind and Code are the ones of the error (see KindOf), which may
+	// differ from the ones sent, e.g. for an *Error sent as a 500
+	Kind Kind
+	Code Code
+	// Ops is the op chain of the error (see Ops)
+	Ops []Op
+	// Status is the HTTP status code HTTPError sends for the error
+	Status int
+	// Stack is the innermost stack trace recorded for the error,
+	// if any
+	Stack StackTrace
+	// Fingerprint identifies the class of the error (see Fingerprint)
+	Fingerprint string
+	// RequestID is the ID of the request which failed, if known
+	RequestID string
+	// Request describes the request which failed, if known (see
+	// RequestInfoHandler)
+	Request RequestInfo
+}
+
+// reporter is the Reporter used by the package. By default, it is a
+// no-op implementation.
+var reporter Reporter = noopReporter{}
+
+// SetReporter sets the Reporter errors are sent to. If r is nil, the
+// default no-op implementation is restored. SetReporter should be
+// called at program start, before any errors are sent.
+func SetReporter(r Reporter) {
+	if r == nil {
+		r = noopReporter{}
+	}
+	reporter = r
+}
+
+// noopReporter is a Reporter which does nothing.
+type noopReporter struct{}
+
+func (noopReporter) Report(context.Context, error, ReportInfo) {}
+
+// Report sends err to the Reporter, whatever its HTTP status code,
+// e.g. for errors of background jobs which are not sent to a client.
+// If err is nil, Report does nothing.
+func Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	status, _ := serviceError(err)
+	report(ctx, err, status)
+}
+
+// report sends err, which is sent with the given HTTP status code, to
+// the Reporter.
+func report(ctx context.Context, err error, status int) {
+	req, _ := RequestInfoFromContext(ctx)
+	reporter.Report(ctx, err, ReportInfo{
+		Kind:        KindOf(err),
+		Code:        chainCode(err),
+		Ops:         Ops(err),
+		Status:      status,
+		Stack:       stackOf(err),
+		Fingerprint: Fingerprint(err),
+		RequestID:   RequestIDFunc(ctx),
+		Request:     req,
+	})
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// testReporter records the errors reported.
+type testReporter struct {
+	infos []ReportInfo
+}
+
+func (tr *testReporter) Report(_ context.Context, _ error, info ReportInfo) {
+	tr.infos = append(tr.infos, info)
+}
+
+func TestReporter(t *testing.T) {
+	defer SetReporter(nil)
+	tr := &testReporter{}
+	SetReporter(tr)
+
+	// Client errors are not reported
+	HTTPError(httptest.NewRecorder(), RE(http.StatusNotFound, NotExist, Code("no_user")))
+	if len(tr.infos) != 0 {
+		t.Fatalf("reported %+v; want nothing for a 404", tr.infos)
+	}
+
+	err := E(Op("users.Get"), Database, Code("db_down"), io.ErrUnexpectedEOF)
+	ctx := WithRequestID(context.Background(), "req-1")
+	HTTPErrorCtx(ctx, httptest.NewRecorder(), err)
+	if len(tr.infos) != 1 {
+		t.Fatalf("reported %d errors; want 1", len(tr.infos))
+	}
+	got := tr.infos[0]
+	if len(got.Stack) == 0 {
+		t.Errorf("Stack is empty")
+	}
+	got.Stack = nil
+	want := ReportInfo{
+		Kind:        Database,
+		Code:        "db_down",
+		Ops:         []Op{"users.Get"},
+		Status:      http.StatusInternalServerError,
+		Fingerprint: Fingerprint(err),
+		RequestID:   "req-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReportInfo = %+v; want %+v", got, want)
+	}
+
+	// Report reports any error
+	Report(context.Background(), RE(http.StatusNotFound, NotExist))
+	Report(context.Background(), nil)
+	if len(tr.infos) != 2 || tr.infos[1].Status != http.StatusNotFound {
+		t.Errorf("Report() reported %+v; want the 404", tr.infos[1:])
+	}
+}
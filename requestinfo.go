@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RequestInfo describes the request an error is sent for. Stored in
+// the context of the request by RequestInfoHandler, it is added to the
+// log entries of HTTPErrorCtx and ReportError, as the method, path,
+// user_id and client_ip fields, and given to the Reporter.
+type RequestInfo struct {
+	Method string
+	Path   string
+	// UserID is the ID of the user of the request, if known
+	UserID string
+	// ClientIP is the IP address of the client (see ClientIPHeader)
+	ClientIP string
+}
+
+// ClientIPHeader is the name of a request header holding the IP address
+// of the client, e.g. "X-Forwarded-For" behind a proxy which appends
+// it. Only the last address of the header is used, i.e. the address
+// added by the proxy in front of the service: the addresses before it
+// may be sent by the client, so they cannot be trusted. When empty, the
+// default, or if the request has no such header, the client IP of
+// RequestInfoHandler is the remote address of the request. It must
+// only be set when the service is behind a proxy which sets the header,
+// as clients could forge it otherwise.
+var ClientIPHeader = ""
+
+// WithRequestInfo returns a copy of ctx which carries info.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey, info)
+}
+
+// RequestInfoFromContext returns the RequestInfo added to ctx with
+// WithRequestInfo, if any.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey).(RequestInfo)
+	return info, ok
+}
+
+// RequestInfoHandler is middleware which stores the RequestInfo of
+// the requests of next in their context, so the errors sent for them
+// are logged and reported with it. userID, which may be nil, returns
+// the ID of the user of a request, e.g. from the claims of its token
+// set in its context by an authentication middleware, which must then
+// run before RequestInfoHandler:
+//
+//	h := errors.RequestInfoHandler(func(r *http.Request) string {
+//		return auth.UserID(r.Context())
+//	}, mux)
+func RequestInfoHandler(userID func(r *http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := RequestInfo{
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			ClientIP: clientIP(r),
+		}
+		if userID != nil {
+			info.UserID = userID(r)
+		}
+		next.ServeHTTP(w, r.WithContext(WithRequestInfo(r.Context(), info)))
+	})
+}
+
+// clientIP returns the IP address of the client of r.
+func clientIP(r *http.Request) string {
+	if ClientIPHeader != "" {
+		// The proxy appends the address to the last header line
+		if vs := r.Header.Values(ClientIPHeader); len(vs) > 0 {
+			v := vs[len(vs)-1]
+			if i := strings.LastIndex(v, ","); i >= 0 {
+				v = v[i+1:]
+			}
+			if ip := strings.TrimSpace(v); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// addRequestInfo adds the fields of the RequestInfo of ctx, if any,
+// to f.
+func addRequestInfo(ctx context.Context, f Fields) {
+	info, ok := RequestInfoFromContext(ctx)
+	if !ok {
+		return
+	}
+	addField(f, "method", info.Method)
+	addField(f, "path", info.Path)
+	addField(f, "user_id", info.UserID)
+	addField(f, "client_ip", info.ClientIP)
+}
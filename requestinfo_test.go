@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestInfoHandler(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+	defer SetReporter(nil)
+	tr := &testReporter{}
+	SetReporter(tr)
+
+	h := RequestInfoHandler(func(r *http.Request) string {
+		return r.Header.Get("X-User")
+	}, HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return RE(http.StatusBadGateway, IO, Str("upstream failed"))
+	}))
+	r := httptest.NewRequest("POST", "/orders", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	r.Header.Set("X-User", "u-42")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	want := RequestInfo{Method: "POST", Path: "/orders", UserID: "u-42", ClientIP: "192.0.2.1"}
+	if len(tl.entries) != 1 {
+		t.Fatalf("%d log entries; want 1", len(tl.entries))
+	}
+	f := tl.entries[0].fields
+	for k, v := range map[string]string{"method": want.Method, "path": want.Path, "user_id": want.UserID, "client_ip": want.ClientIP} {
+		if f[k] != v {
+			t.Errorf("fields[%s] = %v; want %q", k, f[k], v)
+		}
+	}
+	if len(tr.infos) != 1 {
+		t.Fatalf("reported %d errors; want 1", len(tr.infos))
+	}
+	if got := tr.infos[0].Request; got != want {
+		t.Errorf("ReportInfo.Request = %+v; want %+v", got, want)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	defer func(h string) { ClientIPHeader = h }(ClientIPHeader)
+	tests := []struct {
+		name       string
+		header     string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"Remote address", "", "192.0.2.1:1234", "198.51.100.7", "192.0.2.1"},
+		{"No port", "", "192.0.2.1", "", "192.0.2.1"},
+		{"Header", "X-Forwarded-For", "10.0.0.1:80", "198.51.100.7", "198.51.100.7"},
+		{"Forged", "X-Forwarded-For", "10.0.0.1:80", "203.0.113.9, 198.51.100.7", "198.51.100.7"},
+		{"Empty last address", "X-Forwarded-For", "10.0.0.1:80", "198.51.100.7,", "10.0.0.1"},
+		{"Missing header", "X-Forwarded-For", "10.0.0.1:80", "", "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClientIPHeader = tt.header
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry marks an error as retryable when given to E or RE: the
+// operation which failed is transient and may succeed if retried.
+// Its value is the suggested delay before retrying, or 0 if there is
+// no suggestion. For example:
+//
+//	errors.E(op, errors.IO, errors.Retry(5*time.Second), err)
+type Retry time.Duration
+
+// IsRetryable reports whether err, or any error it wraps, was marked
+// as retryable. If err is nil then IsRetryable returns false.
+func IsRetryable(err error) bool {
+	for _, err := range chainOf(err) {
+		switch e := err.(type) {
+		case *Error:
+			if e.Retryable {
+				return true
+			}
+		case *HTTPErr:
+			if e.Retryable {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RetryDelay returns the suggested delay before retrying the operation
+// which failed with err, from the first error in the chain of err
+// which has one. It returns 0 if there is none.
+func RetryDelay(err error) time.Duration {
+	for _, err := range chainOf(err) {
+		switch e := err.(type) {
+		case *Error:
+			if e.RetryAfter > 0 {
+				return e.RetryAfter
+			}
+		case *HTTPErr:
+			if e.RetryAfter > 0 {
+				return e.RetryAfter
+			}
+		}
+	}
+	return 0
+}
+
+// setRetryAfter sets the Retry-After header of the response if
+// a retry delay is attached to err. The delay is rounded up to
+// the next second.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	d := RetryDelay(err)
+	if d <= 0 {
+		return
+	}
+	secs := int64((d + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is
+// either a number of seconds or an HTTP date, into a delay. It reports
+// false if v is empty or invalid. A date in the past is a delay of 0.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(t); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
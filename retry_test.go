@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	const op Op = "errors/TestIsRetryable"
+
+	tests := []struct {
+		name      string
+		err       error
+		want      bool
+		wantDelay time.Duration
+	}{
+		{"nil", nil, false, 0},
+		{"Not retryable", E(op, IO, "network unreachable"), false, 0},
+		{"Error", E(op, IO, Retry(0), "network unreachable"), true, 0},
+		{"Error with delay", E(op, IO, Retry(2*time.Second), "network unreachable"), true, 2 * time.Second},
+		{"Wrapped", E(op, E(Op("inner"), IO, Retry(time.Second), "network unreachable")), true, time.Second},
+		{"HTTPErr", RE(http.StatusServiceUnavailable, Retry(3*time.Second)), true, 3 * time.Second},
+		{"HTTPErr wrapping Error", RE(http.StatusServiceUnavailable, E(op, IO, Retry(0), "network unreachable")), true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %t; want %t", got, tt.want)
+			}
+			if got := RetryDelay(tt.err); got != tt.wantDelay {
+				t.Errorf("RetryDelay() = %v; want %v", got, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	rr := httptest.NewRecorder()
+	HTTPError(rr, RE(http.StatusServiceUnavailable, Retry(1500*time.Millisecond), Str("try later")))
+	if got := rr.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q; want %q", got, "2")
+	}
+
+	rr = httptest.NewRecorder()
+	HTTPError(rr, RE(http.StatusServiceUnavailable, Str("try later")))
+	if got := rr.Header().Get("Retry-After"); got != "" {
+		t.Errorf("Retry-After = %q; want none", got)
+	}
+}
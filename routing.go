@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// NotFoundHandler returns an http.Handler which sends an error of Kind
+// NotExist and Code "NotFound" for any request, as an HTTP 404 in the
+// format of the other error responses. Use it as the handler of routers
+// for the requests which match no route, instead of the plain text
+// response of http.NotFound.
+func NotFoundHandler() http.Handler {
+	return HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return RE(http.StatusNotFound, NotExist, Code("NotFound"), Str(fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path)))
+	})
+}
+
+// MethodNotAllowedHandler returns an http.Handler which sends an error
+// of Kind InvalidRequest and Code "MethodNotAllowed" for any request,
+// as an HTTP 405 in the format of the other error responses. The
+// allowed methods of the route are sent in the Allow header, which a
+// 405 must have, e.g.:
+//
+//	Allow: GET, HEAD
+//
+// Use it as the handler of routers for the requests which match a route
+// but not its methods.
+func MethodNotAllowedHandler(allowed ...string) http.Handler {
+	allow := strings.Join(allowed, ", ")
+	return HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return RE(http.StatusMethodNotAllowed, InvalidRequest, Code("MethodNotAllowed"), Header("Allow", allow),
+			Str(fmt.Sprintf("method %s not allowed for %s", r.Method, r.URL.Path)))
+	})
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoutingHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		h          http.Handler
+		method     string
+		wantStatus int
+		want       ServiceError
+		wantAllow  []string
+	}{
+		{
+			name:       "Not found",
+			h:          NotFoundHandler(),
+			method:     http.MethodGet,
+			wantStatus: http.StatusNotFound,
+			want:       ServiceError{Kind: "item_does_not_exist", Code: "NotFound", Message: "no route for GET /users/42"},
+		},
+		{
+			name:       "Method not allowed",
+			h:          MethodNotAllowedHandler(http.MethodGet, http.MethodHead),
+			method:     http.MethodDelete,
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       ServiceError{Kind: "invalid_request_error", Code: "MethodNotAllowed", Message: "method DELETE not allowed for /users/42"},
+			wantAllow:  []string{"GET, HEAD"},
+		},
+		{
+			name:       "No allowed methods",
+			h:          MethodNotAllowedHandler(),
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       ServiceError{Kind: "invalid_request_error", Code: "MethodNotAllowed", Message: "method POST not allowed for /users/42"},
+			wantAllow:  []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			tt.h.ServeHTTP(rr, httptest.NewRequest(tt.method, "/users/42", nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+			if got := rr.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q; want %q", got, "application/json")
+			}
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatal(err)
+			}
+			if er.Error.Kind != tt.want.Kind || er.Error.Code != tt.want.Code || er.Error.Message != tt.want.Message {
+				t.Errorf("error = %+v; want %+v", er.Error, tt.want)
+			}
+			if got := rr.Header().Values("Allow"); len(got) != len(tt.wantAllow) || len(got) > 0 && got[0] != tt.wantAllow[0] {
+				t.Errorf("Allow = %q; want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
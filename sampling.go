@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// sampler limits the number of identical errors logged by HTTPError.
+// Errors are identical if they have the same status code, Kind, Code
+// and chain of Ops. Within each window, the first burst identical errors are
+// logged and the others are only counted. The count is added to the
+// "suppressed" field of the next identical error logged.
+type sampler struct {
+	mu      sync.Mutex
+	window  time.Duration
+	burst   int
+	entries map[sampleKey]*sampleEntry
+}
+
+type sampleKey struct {
+	status          int
+	kind, code, ops string
+}
+
+type sampleEntry struct {
+	start      time.Time // start of the current window
+	logged     int       // number of errors logged in the window
+	suppressed int       // number of errors not logged since the last one
+}
+
+// logSampler is the sampler used by logHTTPError. Sampling is
+// disabled when it is nil.
+var logSampler *sampler
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// SetLogSampling enables the sampling of the errors logged by
+// HTTPError, to prevent log floods during incidents. Within each
+// window, only the first burst errors with the same Kind, Code and
+// chain of Ops are logged. The number of errors which were not
+// logged is added to the "suppressed" field of the next one logged.
+// If window or burst is not positive, sampling is disabled, which
+// is the default. SetLogSampling should be called at program start,
+// before any errors are logged.
+func SetLogSampling(window time.Duration, burst int) {
+	if window <= 0 || burst <= 0 {
+		logSampler = nil
+		return
+	}
+	logSampler = &sampler{
+		window:  window,
+		burst:   burst,
+		entries: make(map[sampleKey]*sampleEntry),
+	}
+}
+
+// allow reports whether the error identified by k should be logged.
+// If so, it also returns the number of identical errors which were
+// not logged since the last one.
+func (s *sampler) allow(k sampleKey) (ok bool, suppressed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := now()
+	e := s.entries[k]
+	if e == nil {
+		s.prune(t)
+		e = &sampleEntry{start: t}
+		s.entries[k] = e
+	}
+	if t.Sub(e.start) >= s.window {
+		e.start = t
+		e.logged = 0
+	}
+	if e.logged >= s.burst {
+		e.suppressed++
+		return false, 0
+	}
+	e.logged++
+	suppressed, e.suppressed = e.suppressed, 0
+	return true, suppressed
+}
+
+// prune removes the entries of errors which were not seen during the
+// last window and for which no count remains to be logged, so errors
+// which do not reoccur do not use memory forever.
+func (s *sampler) prune(t time.Time) {
+	for k, e := range s.entries {
+		if e.suppressed == 0 && t.Sub(e.start) >= s.window {
+			delete(s.entries, k)
+		}
+	}
+}
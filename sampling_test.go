@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSetLogSampling(t *testing.T) {
+	defer SetLogger(nil)
+	defer SetLogSampling(0, 0)
+	defer func() { now = time.Now }()
+	tl := &testLogger{}
+	SetLogger(tl)
+	t0 := time.Now()
+	now = func() time.Time { return t0 }
+	SetLogSampling(time.Minute, 2)
+
+	notFound := RE(404, NotExist, Code("no_user"), E(Op("getUser"), Str("user not found")))
+	conflict := RE(409, Exist, Code("user_exists"), Str("user exists"))
+	for i := 0; i < 5; i++ {
+		logHTTPError(context.Background(), notFound, Fields{})
+	}
+	logHTTPError(context.Background(), conflict, Fields{})
+	if len(tl.entries) != 3 {
+		t.Fatalf("got %d log entries; want 3", len(tl.entries))
+	}
+
+	now = func() time.Time { return t0.Add(time.Minute) }
+	logHTTPError(context.Background(), notFound, Fields{})
+	if len(tl.entries) != 4 {
+		t.Fatalf("got %d log entries after the window; want 4", len(tl.entries))
+	}
+	if got := tl.entries[3].fields["suppressed"]; got != 3 {
+		t.Errorf("fields[suppressed] = %v; want 3", got)
+	}
+
+	SetLogSampling(0, 0)
+	for i := 0; i < 5; i++ {
+		logHTTPError(context.Background(), notFound, Fields{})
+	}
+	if len(tl.entries) != 9 {
+		t.Errorf("got %d log entries with sampling disabled; want 9", len(tl.entries))
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ResponseSchema renames the fields of the JSON error responses sent by
+// HTTPError, e.g. to keep the contract of an existing public API:
+//
+//	errors.Schema = &errors.ResponseSchema{
+//		Envelope: "errors",
+//		Fields:   map[string]string{"kind": "type", "message": "detail"},
+//	}
+//
+// It applies to the responses in the default format (see ErrResponse),
+// not to the RFC 7807 responses, whose fields are standard, nor to the
+// responses of the other versions or encodings. ParseHTTPError reverses
+// the renaming, so Go clients using the same Schema decode the
+// responses.
+type ResponseSchema struct {
+	// Envelope is the name of the top-level field holding the error,
+	// instead of "error". It is not renamed if empty.
+	Envelope string
+	// Fields maps the names of the fields of ServiceError and of the
+	// values it holds, as in their JSON tags, to the names sent. They
+	// are renamed at any depth, so the errors listed by a ServiceError
+	// are renamed too.
+	Fields map[string]string
+}
+
+// Schema is the ResponseSchema of the JSON error responses. It is nil
+// by default, so the fields are named as in ErrResponse. It should be
+// set at program start, before any errors are sent.
+var Schema *ResponseSchema
+
+// apply renames the fields of the JSON error response data, or
+// restores their names if reverse is true.
+func (s *ResponseSchema) apply(data []byte, reverse bool) ([]byte, error) {
+	if s == nil {
+		return data, nil
+	}
+	envelope, names := "error", s.Fields
+	if s.Envelope != "" {
+		envelope = s.Envelope
+	}
+	from, to := "error", envelope
+	if reverse {
+		from, to = envelope, "error"
+		names = make(map[string]string, len(s.Fields))
+		for k, v := range s.Fields {
+			names[v] = k
+		}
+	}
+
+	r := renamer{top: map[string]string{from: to}, names: names}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var b bytes.Buffer
+	if err := r.value(dec, &b, 0); err != nil {
+		return nil, err
+	}
+	if reverse || ResponseIndent == "" {
+		return b.Bytes(), nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, b.Bytes(), "", ResponseIndent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// renamer copies a JSON value, renaming the keys of its objects.
+type renamer struct {
+	// top renames the keys of the top-level object
+	top map[string]string
+	// names renames the keys of the nested objects
+	names map[string]string
+}
+
+// value copies the next JSON value of dec, at the given depth, to b.
+func (r renamer) value(dec *json.Decoder, b *bytes.Buffer, depth int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	d, ok := tok.(json.Delim)
+	if !ok {
+		v, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		b.Write(v)
+		return nil
+	}
+	b.WriteByte(byte(d))
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if d == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			k, _ := json.Marshal(r.rename(tok.(string), depth))
+			b.Write(k)
+			b.WriteByte(':')
+		}
+		if err := r.value(dec, b, depth+1); err != nil {
+			return err
+		}
+	}
+	// The closing delimiter
+	tok, err = dec.Token()
+	if err != nil {
+		return err
+	}
+	b.WriteByte(byte(tok.(json.Delim)))
+	return nil
+}
+
+// rename returns the new name of the key of an object at the given
+// depth.
+func (r renamer) rename(key string, depth int) string {
+	names := r.names
+	if depth == 0 {
+		names = r.top
+	}
+	if n, ok := names[key]; ok {
+		return n
+	}
+	return key
+}
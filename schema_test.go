@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseSchema(t *testing.T) {
+	defer func() { Schema = nil }()
+	Schema = &ResponseSchema{
+		Envelope: "errors",
+		Fields:   map[string]string{"kind": "type", "message": "detail"},
+	}
+
+	ve := ValidationErrors{
+		RE(Validation, Parameter("name"), Str("name is required")),
+	}
+	tests := []struct {
+		name     string
+		err      error
+		wantKind Kind
+	}{
+		{"Single", RE(http.StatusNotFound, NotExist, Code("NotFound"), Str("user not found")), NotExist},
+		{"Nested", ve, Validation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			var body map[string]map[string]json.RawMessage
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON %q: %v", rr.Body.String(), err)
+			}
+			se, ok := body["errors"]
+			if !ok {
+				t.Fatalf("body = %s; want an errors envelope", rr.Body.String())
+			}
+			if _, ok := se["type"]; !ok {
+				t.Errorf("body = %s; want a type field", rr.Body.String())
+			}
+			if _, ok := se["kind"]; ok {
+				t.Errorf("body = %s; want no kind field", rr.Body.String())
+			}
+			if nested, ok := se["errors"]; ok {
+				var errs []map[string]json.RawMessage
+				if err := json.Unmarshal(nested, &errs); err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := errs[0]["detail"]; !ok {
+					t.Errorf("nested error = %s; want a detail field", nested)
+				}
+			}
+
+			// Clients using the same Schema decode the response
+			got := ParseHTTPError(rr.Result())
+			if KindOf(got) != tt.wantKind {
+				t.Errorf("ParseHTTPError() Kind = %v; want %v", KindOf(got), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestResponseSchemaApply(t *testing.T) {
+	defer func(indent string) { ResponseIndent = indent }(ResponseIndent)
+	ResponseIndent = ""
+	s := &ResponseSchema{Fields: map[string]string{"code": "error_code"}}
+	in := `{"error":{"code":"NotFound","got":1.5,"want":null,"errors":[{"code":"a"}]}}`
+	want := `{"error":{"error_code":"NotFound","got":1.5,"want":null,"errors":[{"error_code":"a"}]}}`
+
+	got, err := s.apply([]byte(in), false)
+	if err != nil || string(got) != want {
+		t.Errorf("apply() = %s, %v; want %s", got, err, want)
+	}
+	back, err := s.apply(got, true)
+	if err != nil || string(back) != in {
+		t.Errorf("apply(reverse) = %s, %v; want %s", back, err, in)
+	}
+
+	var nilSchema *ResponseSchema
+	if got, _ := nilSchema.apply([]byte(in), false); string(got) != in {
+		t.Errorf("nil apply() = %s; want it unchanged", got)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/gilcrest/errors/sentryerrors
+
+go 1.21
+
+require (
+	github.com/getsentry/sentry-go v0.31.1
+	github.com/gilcrest/errors v0.0.0
+)
+
+require (
+	github.com/rs/zerolog v1.14.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/gilcrest/errors => ../
@@ -0,0 +1,113 @@
+// Package sentryerrors reports the server errors sent by the errors
+// package to Sentry. Initialize Sentry with sentry.Init, then register
+// the Reporter:
+//
+//	errors.SetReporter(sentryerrors.Reporter{})
+package sentryerrors
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gilcrest/errors"
+)
+
+// Reporter implements errors.Reporter with Sentry. Register it with
+// errors.SetReporter, after initializing Sentry with sentry.Init.
+// Errors are sent with the hub of their context (see
+// sentry.GetHubFromContext), or the current hub if it has none.
+type Reporter struct{}
+
+var _ errors.Reporter = Reporter{}
+
+// Report sends err to Sentry as an exception with the stack trace of
+// info, grouped by its fingerprint. Its Kind, Code and op chain are set
+// as the error.kind, error.code and error.op_chain tags, along with the
+// http.status_code and request_id tags. The level of the event is the
+// Severity of err (see errors.SeverityOf).
+func (Reporter) Report(ctx context.Context, err error, info errors.ReportInfo) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("error.kind", info.Kind.String())
+		setTag(scope, "error.code", string(info.Code))
+		setTag(scope, "error.op_chain", opChain(info.Ops))
+		scope.SetTag("http.status_code", strconv.Itoa(info.Status))
+		setTag(scope, "request_id", info.RequestID)
+		if info.Fingerprint != "" {
+			scope.SetFingerprint([]string{info.Fingerprint})
+		}
+
+		event := sentry.NewEvent()
+		event.Level = level(errors.SeverityOf(err))
+		event.Exception = []sentry.Exception{{
+			Type:       exceptionType(err, info),
+			Value:      err.Error(),
+			Stacktrace: stacktrace(info.Stack),
+		}}
+		hub.CaptureEvent(event)
+	})
+}
+
+// setTag sets the tag key of scope to value, unless value is empty.
+func setTag(scope *sentry.Scope, key, value string) {
+	if value != "" {
+		scope.SetTag(key, value)
+	}
+}
+
+// opChain joins ops from the outermost to the innermost.
+func opChain(ops []errors.Op) string {
+	s := make([]string, len(ops))
+	for i, op := range ops {
+		s[i] = string(op)
+	}
+	return strings.Join(s, " -> ")
+}
+
+// exceptionType returns the type of the exception for err, which is
+// the title of its issue: its Code, or else its Kind, or else its Go
+// type.
+func exceptionType(err error, info errors.ReportInfo) string {
+	switch {
+	case info.Code != "":
+		return string(info.Code)
+	case info.Kind != errors.Other:
+		return info.Kind.String()
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// stacktrace converts st to a Sentry stack trace, whose innermost
+// call is last. It returns nil if st is empty.
+func stacktrace(st errors.StackTrace) *sentry.Stacktrace {
+	frames := st.Frames()
+	if len(frames) == 0 {
+		return nil
+	}
+	sf := make([]sentry.Frame, len(frames))
+	for i, f := range frames {
+		sf[len(frames)-1-i] = sentry.NewFrame(f)
+	}
+	return &sentry.Stacktrace{Frames: sf}
+}
+
+// level returns the Sentry level of errors of Severity s.
+func level(s errors.Severity) sentry.Level {
+	switch s {
+	case errors.DebugSeverity:
+		return sentry.LevelDebug
+	case errors.InfoSeverity:
+		return sentry.LevelInfo
+	case errors.WarnSeverity:
+		return sentry.LevelWarning
+	case errors.CriticalSeverity:
+		return sentry.LevelFatal
+	}
+	return sentry.LevelError
+}
@@ -0,0 +1,74 @@
+package sentryerrors
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gilcrest/errors"
+)
+
+// testTransport records the events sent to Sentry.
+type testTransport struct {
+	events []*sentry.Event
+}
+
+func (t *testTransport) Configure(sentry.ClientOptions) {}
+func (t *testTransport) SendEvent(event *sentry.Event)  { t.events = append(t.events, event) }
+func (t *testTransport) Flush(time.Duration) bool       { return true }
+func (t *testTransport) Close()                         {}
+
+func TestReporter(t *testing.T) {
+	errors.SetReporter(Reporter{})
+	defer errors.SetReporter(nil)
+	transport := &testTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatalf("sentry.NewClient() error = %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+	ctx := sentry.SetHubOnContext(errors.WithRequestID(context.Background(), "req-1"), hub)
+
+	e := errors.E(errors.Op("users.Get"), errors.Database, errors.Code("db_down"), io.ErrUnexpectedEOF)
+	errors.HTTPErrorCtx(ctx, httptest.NewRecorder(), e)
+
+	if len(transport.events) != 1 {
+		t.Fatalf("sent %d events; want 1", len(transport.events))
+	}
+	event := transport.events[0]
+	wantTags := map[string]string{
+		"error.kind":       errors.Database.String(),
+		"error.code":       "db_down",
+		"error.op_chain":   "users.Get",
+		"http.status_code": "500",
+		"request_id":       "req-1",
+	}
+	if !reflect.DeepEqual(event.Tags, wantTags) {
+		t.Errorf("Tags = %v; want %v", event.Tags, wantTags)
+	}
+	if want := []string{errors.Fingerprint(e)}; !reflect.DeepEqual(event.Fingerprint, want) {
+		t.Errorf("Fingerprint = %v; want %v", event.Fingerprint, want)
+	}
+	if event.Level != sentry.LevelError {
+		t.Errorf("Level = %q; want %q", event.Level, sentry.LevelError)
+	}
+	if len(event.Exception) != 1 {
+		t.Fatalf("Exception = %+v; want 1 exception", event.Exception)
+	}
+	ex := event.Exception[0]
+	if ex.Type != "db_down" || ex.Value != e.Error() {
+		t.Errorf("Exception = %q: %q; want db_down: %q", ex.Type, ex.Value, e.Error())
+	}
+	// The innermost call, where the error was built, is last
+	if ex.Stacktrace == nil || len(ex.Stacktrace.Frames) == 0 {
+		t.Fatalf("Stacktrace is empty")
+	}
+	if last := ex.Stacktrace.Frames[len(ex.Stacktrace.Frames)-1]; !strings.HasSuffix(last.Function, "TestReporter") {
+		t.Errorf("last frame = %q; want TestReporter", last.Function)
+	}
+}
@@ -0,0 +1,85 @@
+package errors
+
+// Severity defines how severe an error is. It determines the level at
+// which HTTPError logs the error, so expected errors, such as a 404 for
+// an unknown item, do not flood the error logs, and critical errors
+// can drive alerting.
+type Severity uint8
+
+// Severities, from least to most severe. DefaultSeverity is the
+// Severity of errors for which none was given; they are logged at the
+// level of their HTTP status code, which is error level by default
+// (see LogLevels).
+const (
+	DefaultSeverity  Severity = iota // Severity not set; logged as per LogLevels.
+	DebugSeverity                    // Only useful for debugging.
+	InfoSeverity                     // Expected errors, such as unknown items.
+	WarnSeverity                     // Errors that should be looked at.
+	ErrorSeverity                    // Errors.
+	CriticalSeverity                 // Errors which need immediate attention.
+)
+
+func (s Severity) String() string {
+	switch s {
+	case DefaultSeverity:
+		return "default"
+	case DebugSeverity:
+		return "debug"
+	case InfoSeverity:
+		return "info"
+	case WarnSeverity:
+		return "warn"
+	case ErrorSeverity:
+		return "error"
+	case CriticalSeverity:
+		return "critical"
+	}
+	return "unknown_severity"
+}
+
+// severityFromString returns the Severity whose String method
+// returns s, or DefaultSeverity if there is none.
+func severityFromString(s string) Severity {
+	for sev := DebugSeverity; sev <= CriticalSeverity; sev++ {
+		if sev.String() == s {
+			return sev
+		}
+	}
+	return DefaultSeverity
+}
+
+// level returns the Level at which errors of Severity s are logged.
+func (s Severity) level() Level {
+	switch s {
+	case DebugSeverity:
+		return DebugLevel
+	case InfoSeverity:
+		return InfoLevel
+	case WarnSeverity:
+		return WarnLevel
+	case CriticalSeverity:
+		return CriticalLevel
+	}
+	return ErrorLevel
+}
+
+// SeverityOf returns the Severity of err, i.e. the Severity of the
+// outermost Error or HTTPErr in its chain with one set. If there is
+// none, it returns DefaultSeverity.
+func SeverityOf(err error) Severity {
+	for _, err := range chainOf(err) {
+		var s Severity
+		switch e := err.(type) {
+		case *Error:
+			s = e.Severity
+		case *HTTPErr:
+			s = e.Severity
+		case HTTPErr:
+			s = e.Severity
+		}
+		if s != DefaultSeverity {
+			return s
+		}
+	}
+	return DefaultSeverity
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSeverity(t *testing.T) {
+	defer SetLogger(nil)
+
+	tests := []struct {
+		name      string
+		err       error
+		wantSev   Severity
+		wantLevel Level
+	}{
+		{"Default", RE(http.StatusNotFound, NotExist, Str("no such user")), DefaultSeverity, ErrorLevel},
+		{"HTTPErr", RE(http.StatusNotFound, NotExist, InfoSeverity, Str("no such user")), InfoSeverity, InfoLevel},
+		{"Inner Error", RE(http.StatusNotFound, E(Op("db.Get"), WarnSeverity, Str("no rows"))), WarnSeverity, WarnLevel},
+		{"Outermost wins", E(Op("service.Get"), DebugSeverity, E(Op("db.Get"), CriticalSeverity, Str("no rows"))), DebugSeverity, DebugLevel},
+		{"Critical", E(Op("db.Open"), CriticalSeverity, Str("disk full")), CriticalSeverity, CriticalLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+			if got := SeverityOf(tt.err); got != tt.wantSev {
+				t.Errorf("SeverityOf() = %v; want %v", got, tt.wantSev)
+			}
+			HTTPError(httptest.NewRecorder(), tt.err)
+			if len(tl.entries) != 1 {
+				t.Fatalf("got %d log entries; want 1", len(tl.entries))
+			}
+			if got := tl.entries[0].level; got != tt.wantLevel {
+				t.Errorf("level = %v; want %v", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestSeverityJSON(t *testing.T) {
+	in := E(Op("db.Open"), CriticalSeverity, Str("disk full"))
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var out Error
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if out.Severity != CriticalSeverity {
+		t.Errorf("Severity = %v; want %v", out.Severity, CriticalSeverity)
+	}
+}
+
+func TestZerologCritical(t *testing.T) {
+	var buf bytes.Buffer
+	NewZerologLogger(zerolog.New(&buf)).Log(CriticalLevel, "disk full", nil)
+	for _, want := range []string{`"level":"error"`, `"severity":"critical"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output %q does not contain %s", buf.String(), want)
+		}
+	}
+}
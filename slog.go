@@ -0,0 +1,71 @@
+package errors
+
+import (
+	stderrors "errors"
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer, so an Error logged with log/slog,
+// e.g. slog.Error("request failed", "err", err), is expanded into a
+// group of attributes: its message (msg), and its kind, code, param,
+// op_chain and status, named as in the log entries of HTTPError. The
+// status is the HTTP status code HTTPError would send for it.
+func (e *Error) LogValue() slog.Value {
+	if e == nil {
+		return slog.StringValue("no error")
+	}
+	return errorValue(e)
+}
+
+// LogValue implements slog.LogValuer, as (*Error).LogValue does.
+func (hse *HTTPErr) LogValue() slog.Value {
+	if hse == nil {
+		return slog.StringValue("")
+	}
+	return errorValue(hse)
+}
+
+// ReplaceAttr is a function for slog.HandlerOptions.ReplaceAttr which
+// expands the errors wrapping an Error or an HTTPErr, e.g. with
+// fmt.Errorf, as their LogValue method does:
+//
+//	h := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{ReplaceAttr: errors.ReplaceAttr})
+//
+// Other attributes are returned unchanged.
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+	err, ok := a.Value.Any().(error)
+	if !ok || !wrapsOwnError(err) {
+		return a
+	}
+	return slog.Attr{Key: a.Key, Value: errorValue(err)}
+}
+
+// wrapsOwnError reports whether the chain of err has an Error or an
+// HTTPErr.
+func wrapsOwnError(err error) bool {
+	var e *Error
+	var hse *HTTPErr
+	return stderrors.As(err, &e) || stderrors.As(err, &hse)
+}
+
+// errorValue returns the group of attributes of err logged by slog.
+func errorValue(err error) slog.Value {
+	attrs := []slog.Attr{slog.String("msg", maskMessage(err, err.Error()))}
+	if k := KindOf(err); k != Other {
+		attrs = append(attrs, slog.String("kind", k.String()))
+	}
+	if c := chainCode(err); c != "" {
+		attrs = append(attrs, slog.String("code", string(c)))
+	}
+	if p := chainParam(err); p != "" {
+		attrs = append(attrs, slog.String("param", string(p)))
+	}
+	if ops := Ops(err); len(ops) > 0 {
+		attrs = append(attrs, slog.String("op_chain", joinOps(ops)))
+	}
+	attrs = append(attrs, slog.Int("status", StatusOf(err)))
+	return slog.GroupValue(attrs...)
+}
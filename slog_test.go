@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	defer func() { CaptureStack = true }()
+	CaptureStack = false
+
+	hse := RE(http.StatusNotFound, Op("users.Get"), NotExist, Code("no_user"), Parameter("id"), Str("no user 42"))
+	tests := []struct {
+		name        string
+		err         error
+		replaceAttr func([]string, slog.Attr) slog.Attr
+		want        interface{}
+	}{
+		{
+			"HTTPErr",
+			hse,
+			nil,
+			map[string]interface{}{"msg": "no user 42", "kind": "item_does_not_exist", "code": "no_user", "param": "id", "op_chain": "users.Get", "status": float64(404)},
+		},
+		{
+			"Wrapped",
+			fmt.Errorf("handler: %w", hse),
+			ReplaceAttr,
+			// HTTPError only sends the status code of an outermost HTTPErr
+			map[string]interface{}{"msg": "handler: no user 42", "kind": "item_does_not_exist", "code": "no_user", "param": "id", "op_chain": "users.Get", "status": float64(500)},
+		},
+		{"Wrapped without ReplaceAttr", fmt.Errorf("handler: %w", hse), nil, "handler: no user 42"},
+		{"Other error", fmt.Errorf("some error"), ReplaceAttr, "some error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: tt.replaceAttr}))
+			l.Error("request failed", "err", tt.err)
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatal(err)
+			}
+			if got := entry["err"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("err = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
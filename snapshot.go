@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Snapshot is the complete record of an error, for postmortems. Unlike
+// the log entry of the error, nothing is redacted, but the values of
+// the MaskedParams are masked.
+type Snapshot struct {
+	Time time.Time `json:"time"`
+	// ErrorID, RequestID and TraceID identify the response, the
+	// request and the trace of the error, if known
+	ErrorID   string `json:"error_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	// Actor and Resource are those of the request, if known (see
+	// WithActor and WithResource)
+	Actor    string `json:"actor,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	// Status is the HTTP status code sent for the error
+	Status  int    `json:"status"`
+	Kind    string `json:"kind,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	// Error is the JSON encoding of the error and of the errors it
+	// wraps (see (*Error).MarshalJSON)
+	Error json.RawMessage `json:"error"`
+	// Tree is the error as formatted by Format
+	Tree string `json:"tree"`
+	// Stack is the innermost stack trace recorded for the error
+	Stack string `json:"stack,omitempty"`
+}
+
+// SnapshotStore persists the Snapshots of errors, e.g. to files, an
+// object store or a database. Register an implementation with
+// SetSnapshotStore.
+type SnapshotStore interface {
+	// Store persists the Snapshot of an error.
+	Store(ctx context.Context, s Snapshot) error
+}
+
+// snapshotStore is the SnapshotStore used by the package. By default,
+// it is a no-op implementation.
+var snapshotStore SnapshotStore = noopSnapshotStore{}
+
+// SetSnapshotStore sets the SnapshotStore to which the Snapshots of the
+// errors sent with a 5xx status code by HTTPError, or given to
+// ReportError, are persisted, asynchronously if SetAsyncReporting was
+// called. If s is nil, the default no-op implementation is restored.
+// SetSnapshotStore should be called at program start, before any
+// errors are sent.
+func SetSnapshotStore(s SnapshotStore) {
+	if s == nil {
+		s = noopSnapshotStore{}
+	}
+	snapshotStore = s
+}
+
+// noopSnapshotStore is a SnapshotStore which does nothing.
+type noopSnapshotStore struct{}
+
+func (noopSnapshotStore) Store(context.Context, Snapshot) error { return nil }
+
+// Persist persists the Snapshot of err, with the request metadata of
+// ctx, to the SnapshotStore, whatever its status code, and returns the
+// error of the store, if any. If err is nil, Persist does nothing.
+func Persist(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return snapshotStore.Store(ctx, snapshotOf(ctx, err, StatusOf(err), now()))
+}
+
+// persist persists the Snapshot of err, sent with the given status code
+// at the time at, if it is a server error, and logs the error of the
+// store, if any.
+func persist(ctx context.Context, err error, status int, at time.Time) {
+	if status < http.StatusInternalServerError {
+		return
+	}
+	if serr := snapshotStore.Store(ctx, snapshotOf(ctx, err, status, at)); serr != nil {
+		logger.Log(WarnLevel, "error snapshot not persisted", Fields{"error": serr.Error(), "error_id": errorIDFromContext(ctx)})
+	}
+}
+
+// snapshotOf returns the Snapshot of err, sent with the given status
+// code at the time at.
+func snapshotOf(ctx context.Context, err error, status int, at time.Time) Snapshot {
+	kind, code := classify(err)
+	s := Snapshot{
+		Time:      at,
+		ErrorID:   errorIDFromContext(ctx),
+		RequestID: RequestIDFunc(ctx),
+		TraceID:   tracer.TraceID(ctx),
+		Status:    status,
+		Code:      string(code),
+		Message:   maskMessage(err, err.Error()),
+		Tree:      maskMessage(err, Format(err)),
+	}
+	if kind != Other {
+		s.Kind = kind.String()
+	}
+	s.Actor, _ = ctx.Value(actorKey).(string)
+	s.Resource, _ = ctx.Value(resourceKey).(string)
+	if b, jerr := json.Marshal(toJSONError(err)); jerr == nil {
+		s.Error = b
+	}
+	if st := stackOf(err); len(st) > 0 {
+		s.Stack = st.String()
+	}
+	return s
+}
+
+// FileStore is a SnapshotStore which writes each Snapshot to a JSON
+// file in a directory, named after its error ID, or its time if it has
+// none.
+type FileStore struct {
+	Dir string
+}
+
+// Store writes s to a file in the directory of fs.
+func (fs FileStore) Store(_ context.Context, s Snapshot) error {
+	name := s.ErrorID
+	if name == "" {
+		name = strconv.FormatInt(s.Time.UnixNano(), 10)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(fs.Dir, name+".json"), b, 0o600)
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSnapshotStore records the snapshots.
+type testSnapshotStore struct {
+	snapshots []Snapshot
+}
+
+func (s *testSnapshotStore) Store(_ context.Context, snap Snapshot) error {
+	s.snapshots = append(s.snapshots, snap)
+	return nil
+}
+
+func TestSnapshotStore(t *testing.T) {
+	defer SetSnapshotStore(nil)
+	defer func() { now = time.Now }()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Server error", E(Op("users.Get"), Database, Code("db_down"), Str("pq: connection refused")), true},
+		{"Client error", NotFound("users.Get", "no user 42"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &testSnapshotStore{}
+			SetSnapshotStore(s)
+			ctx := WithActor(WithRequestID(context.Background(), "req-1"), "user-7")
+			HTTPErrorCtx(ctx, httptest.NewRecorder(), tt.err)
+			if !tt.want {
+				if len(s.snapshots) != 0 {
+					t.Errorf("snapshots = %v; want none", s.snapshots)
+				}
+				return
+			}
+			if len(s.snapshots) != 1 {
+				t.Fatalf("len(snapshots) = %d; want 1", len(s.snapshots))
+			}
+			snap := s.snapshots[0]
+			if snap.Time != at || snap.RequestID != "req-1" || snap.Actor != "user-7" || snap.Status != http.StatusInternalServerError {
+				t.Errorf("snapshot = %+v; want the request metadata", snap)
+			}
+			if !strings.Contains(snap.Message, "pq: connection refused") || !strings.Contains(snap.Tree, "users.Get") {
+				t.Errorf("snapshot = %+v; want the unredacted error", snap)
+			}
+			var e Error
+			if err := json.Unmarshal(snap.Error, &e); err != nil || e.Code != "db_down" {
+				t.Errorf("snapshot Error = %s, %v; want the encoded chain", snap.Error, err)
+			}
+		})
+	}
+}
+
+func TestPersist(t *testing.T) {
+	defer SetSnapshotStore(nil)
+	dir := t.TempDir()
+	SetSnapshotStore(FileStore{Dir: dir})
+	ctx := context.WithValue(context.Background(), errorIDKey, "01HX")
+	if err := Persist(ctx, NotFound("users.Get", "no user 42")); err != nil {
+		t.Fatalf("Persist() error = %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "01HX.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil || snap.Status != http.StatusNotFound || snap.Kind != NotExist.String() {
+		t.Errorf("snapshot = %s, %v; want the 404 of the error", b, err)
+	}
+
+	SetSnapshotStore(FileStore{Dir: filepath.Join(dir, "missing")})
+	if err := Persist(ctx, NotFound("users.Get", "no user 42")); err == nil {
+		t.Error("Persist() error = nil; want the error of the store")
+	}
+	if err := Persist(ctx, nil); err != nil {
+		t.Errorf("Persist(nil) error = %v; want nil", err)
+	}
+}
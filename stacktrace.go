@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+)
+
+// CaptureStack determines whether E, RE and WithStack record the call
+// stack at the point the error is constructed. Capturing a stack has a
+// cost, so it may be disabled in production by setting CaptureStack
+// to false.
+var CaptureStack = true
+
+// maxStackDepth is the maximum number of frames recorded in a StackTrace.
+const maxStackDepth = 32
+
+// StackTrace is the call stack recorded when an error was constructed.
+// The innermost (most recent) call is first.
+type StackTrace []uintptr
+
+// Frames returns the runtime frames of the stack trace,
+// the innermost call first.
+func (st StackTrace) Frames() []runtime.Frame {
+	if len(st) == 0 {
+		return nil
+	}
+	var fs []runtime.Frame
+	frames := runtime.CallersFrames(st)
+	for {
+		f, more := frames.Next()
+		fs = append(fs, f)
+		if !more {
+			break
+		}
+	}
+	return fs
+}
+
+// String formats the stack trace with one frame per line, as
+// the function name followed by the file name and line number.
+func (st StackTrace) String() string {
+	b := new(bytes.Buffer)
+	for i, f := range st.Frames() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+	return b.String()
+}
+
+// captureStack records the current call stack, skipping skip frames
+// above the caller of captureStack. It returns nil if CaptureStack
+// is false.
+func captureStack(skip int) StackTrace {
+	if !CaptureStack {
+		return nil
+	}
+	return recordStack(skip + 1)
+}
+
+// recordStack records the current call stack, skipping skip frames
+// above the caller of recordStack, whether CaptureStack is true or not.
+func recordStack(skip int) StackTrace {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	// Copy the frames, so only as many as recorded are allocated
+	st := make(StackTrace, n)
+	copy(st, pcs[:n])
+	return st
+}
+
+// stackTracer is implemented by errors that carry a StackTrace.
+type stackTracer interface {
+	StackTrace() StackTrace
+}
+
+// stackOf returns the innermost stack trace recorded in the chain of
+// errors wrapped by err, as it is the closest to where the error
+// occurred. It returns nil if no stack trace was recorded.
+func stackOf(err error) StackTrace {
+	var st StackTrace
+	eachOf(err, func(err error) bool {
+		if t, ok := err.(stackTracer); ok && len(t.StackTrace()) > 0 {
+			st = t.StackTrace()
+		}
+		return true
+	})
+	return st
+}
+
+// StackTrace returns the call stack recorded when the Error was
+// constructed, or nil if none was recorded.
+func (e *Error) StackTrace() StackTrace {
+	if e == nil {
+		return nil
+	}
+	return e.trace
+}
+
+// StackTrace returns the call stack recorded when the HTTPErr was
+// constructed, or nil if none was recorded.
+func (hse HTTPErr) StackTrace() StackTrace {
+	return hse.trace
+}
+
+// WithStack annotates err with the call stack at the point WithStack
+// is called. If err is nil, WithStack returns nil. The stack is only
+// recorded if CaptureStack is true.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Err: err, trace: captureStack(1), pc: captureLocation(0)}
+}
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStackTrace(t *testing.T) {
+	const fn = "github.com/gilcrest/errors.TestStackTrace"
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"E", E(Op("errors/TestStackTrace"), "message")},
+		{"RE", RE(400, "message")},
+		{"WithStack", WithStack(Str("message"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := tt.err.(stackTracer)
+			if !ok {
+				t.Fatalf("%T does not implement StackTrace()", tt.err)
+			}
+			frames := st.StackTrace().Frames()
+			if len(frames) == 0 {
+				t.Fatal("StackTrace() is empty")
+			}
+			if frames[0].Function != fn {
+				t.Errorf("first frame = %q; want %q", frames[0].Function, fn)
+			}
+			if !strings.Contains(st.StackTrace().String(), "stacktrace_test.go") {
+				t.Errorf("String() = %q; want file name", st.StackTrace().String())
+			}
+		})
+	}
+}
+
+func TestCaptureStackDisabled(t *testing.T) {
+	defer func(prev bool) {
+		CaptureStack = prev
+	}(CaptureStack)
+	CaptureStack = false
+
+	if st := E("message").(*Error).StackTrace(); st != nil {
+		t.Errorf("StackTrace() = %v; want nil", st)
+	}
+	if WithStack(nil) != nil {
+		t.Error("WithStack(nil) != nil")
+	}
+}
+
+func newStackError() error {
+	return E(Op("errors/newStackError"), Internal, "message")
+}
+
+func TestStackTraceInnermost(t *testing.T) {
+	const fn = "github.com/gilcrest/errors.newStackError"
+
+	err := RE(500, newStackError())
+	frames := err.(stackTracer).StackTrace().Frames()
+	if len(frames) == 0 {
+		t.Fatal("StackTrace() is empty")
+	}
+	if frames[0].Function != fn {
+		t.Errorf("first frame = %q; want %q", frames[0].Function, fn)
+	}
+}
+
+func TestStackTraceLogged(t *testing.T) {
+	defer SetLogger(nil)
+
+	tests := []struct {
+		name      string
+		err       error
+		wantStack bool
+	}{
+		{"Client HTTPErr", RE(404, NotExist, Str("not found")), false},
+		{"Server HTTPErr", RE(500, Internal, Str("failed")), true},
+		{"Error", newStackError(), true},
+		{"Wrapped Error", WithStack(Str("failed")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+
+			HTTPError(httptest.NewRecorder(), tt.err)
+			if len(tl.entries) != 1 {
+				t.Fatalf("got %d log entries; want 1", len(tl.entries))
+			}
+			_, ok := tl.entries[0].fields["stack"]
+			if ok != tt.wantStack {
+				t.Errorf("stack logged = %t; want %t", ok, tt.wantStack)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"net/http"
+	"sync"
+)
+
+// StatusClientClosedRequest is the non-standard HTTP status code used
+// for requests canceled by the client before a response was sent, as
+// in nginx. It is the status code mapped to Kind Canceled.
+const StatusClientClosedRequest = 499
+
+var (
+	statusMu sync.RWMutex
+	// kindStatus maps each Kind to its default HTTP status code.
+	kindStatus = map[Kind]int{
+		Other:          http.StatusInternalServerError,
+		Invalid:        http.StatusBadRequest,
+		Permission:     http.StatusForbidden,
+		IO:             http.StatusServiceUnavailable,
+		Exist:          http.StatusConflict,
+		NotExist:       http.StatusNotFound,
+		Private:        http.StatusForbidden,
+		Internal:       http.StatusInternalServerError,
+		BrokenLink:     http.StatusNotFound,
+		Database:       http.StatusInternalServerError,
+		Validation:     http.StatusBadRequest,
+		Unanticipated:  http.StatusInternalServerError,
+		InvalidRequest: http.StatusBadRequest,
+		Timeout:        http.StatusGatewayTimeout,
+		Canceled:       StatusClientClosedRequest,
+		Unauthorized:   http.StatusUnauthorized,
+	}
+)
+
+// RegisterStatusMapping sets the HTTP status code used for an HTTPErr
+// of the given Kind when no status code is given to RE. It overrides
+// the default mapping for that Kind.
+func RegisterStatusMapping(k Kind, status int) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	kindStatus[k] = status
+}
+
+// KindStatus returns the HTTP status code mapped to the given Kind.
+// If the Kind has no mapping, it returns 500 (Internal Server Error).
+func KindStatus(k Kind) int {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	if status, ok := kindStatus[k]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ReasonPhrase returns the canonical reason phrase of the HTTP status
+// code, as http.StatusText, e.g. "Not Found" for 404. It also knows
+// StatusClientClosedRequest. It returns "" if the code is unknown.
+func ReasonPhrase(code int) string {
+	if code == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(code)
+}
+
+// IncludeReason determines whether the error responses sent by
+// HTTPError include the reason phrase of their status code, in the
+// reason field (see ReasonPhrase), e.g. for clients which cannot read
+// the status line. It is false by default.
+var IncludeReason = false
+
+// validStatus reports whether code is a known HTTP status code, which
+// may be sent to clients.
+func validStatus(code int) bool {
+	return ReasonPhrase(code) != ""
+}
+
+// StatusOf returns the HTTP status code err is sent with by HTTPError.
+func StatusOf(err error) int {
+	status, _ := serviceError(err)
+	return status
+}
@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestKindStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"NotExist", RE(NotExist, Str("no such user")), http.StatusNotFound},
+		{"Permission", RE(Permission), http.StatusForbidden},
+		{"Exist", RE(Exist, Code("user_exists")), http.StatusConflict},
+		{"No Kind", RE(Str("some error")), http.StatusInternalServerError},
+		{"Explicit status", RE(http.StatusTeapot, NotExist), http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+			if rr.Code != tt.want {
+				t.Errorf("status = %d; want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterStatusMapping(t *testing.T) {
+	defer RegisterStatusMapping(Database, KindStatus(Database))
+	RegisterStatusMapping(Database, http.StatusServiceUnavailable)
+
+	if got := RE(Database).(*HTTPErr).Status(); got != http.StatusServiceUnavailable {
+		t.Errorf("Status() = %d; want %d", got, http.StatusServiceUnavailable)
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"HTTPErr", RE(http.StatusConflict, Exist), http.StatusConflict},
+		{"Kind", RE(NotExist), http.StatusNotFound},
+		{"Validation errors", ValidationErrors{RE(Validation, Parameter("id"))}, http.StatusBadRequest},
+		{"Error", E(Op("db.Get"), NotExist), http.StatusInternalServerError},
+		{"Other error", Str("some error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusOf(tt.err); got != tt.want {
+				t.Errorf("StatusOf() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReasonPhrase(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{http.StatusNotFound, "Not Found"},
+		{http.StatusTooManyRequests, "Too Many Requests"},
+		{StatusClientClosedRequest, "Client Closed Request"},
+		{442, ""},
+	}
+	for _, tt := range tests {
+		if got := ReasonPhrase(tt.code); got != tt.want {
+			t.Errorf("ReasonPhrase(%d) = %q; want %q", tt.code, got, tt.want)
+		}
+	}
+	var hse *HTTPErr
+	if !stderrors.As(RE(http.StatusConflict, Exist), &hse) || hse.ReasonPhrase() != "Conflict" {
+		t.Errorf("HTTPErr.ReasonPhrase() = %q; want %q", hse.ReasonPhrase(), "Conflict")
+	}
+}
+
+func TestREInvalidStatus(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	err := RE(442, Validation, Str("typo"))
+	if got := StatusOf(err); got != http.StatusInternalServerError {
+		t.Errorf("StatusOf() = %d; want %d", got, http.StatusInternalServerError)
+	}
+	if len(tl.entries) != 1 || tl.entries[0].level != WarnLevel {
+		t.Fatalf("log entries = %v; want a warning", tl.entries)
+	}
+	if msg := tl.entries[0].msg; !strings.Contains(msg, "442") || !strings.Contains(msg, "status_test.go") {
+		t.Errorf("log message = %q; want the status code and its caller", msg)
+	}
+}
+
+func TestIncludeReason(t *testing.T) {
+	defer func(b bool) { IncludeReason = b }(IncludeReason)
+
+	for _, include := range []bool{false, true} {
+		IncludeReason = include
+		w := httptest.NewRecorder()
+		HTTPError(w, RE(http.StatusNotFound, NotExist, Str("no such user")))
+		var er ErrResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil {
+			t.Fatal(err)
+		}
+		want := ""
+		if include {
+			want = "Not Found"
+		}
+		if er.Error.Reason != want {
+			t.Errorf("IncludeReason = %v: Reason = %q; want %q", include, er.Error.Reason, want)
+		}
+	}
+}
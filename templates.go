@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+)
+
+var (
+	templatesMu sync.RWMutex
+	// templates holds the message template registered for each Code.
+	templates = map[Code]*template.Template{}
+)
+
+// TemplateData is the custom data of the message template of the Code
+// of an error (see RegisterTemplate). It is given to E or RE with the
+// Code, e.g.
+//
+//	errors.RE(errors.Code("TooLong"), errors.Parameter("name"), errors.TemplateData{"Max": 50})
+type TemplateData map[string]interface{}
+
+// RegisterTemplate registers the template of the message of the errors
+// with the given Code, so the message is written consistently wherever
+// the Code is used. When E or RE is given the Code, but no message or
+// underlying error, the message is generated with the template. The
+// template is parsed with text/template and executed with the
+// TemplateData given to E or RE, along with the Code, Kind (its name)
+// and Param of the error, e.g.
+//
+//	errors.RegisterTemplate("TooLong", "field {{.Param}} must be at most {{.Max}} characters")
+//
+// RegisterTemplate panics if the template cannot be parsed. Unlike
+// RegisterMessage, it sets the message of the error itself, which is
+// logged, rather than translating the message sent to the client.
+func RegisterTemplate(code Code, tmpl string) {
+	t := template.Must(template.New(string(code)).Option("missingkey=error").Parse(tmpl))
+
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[code] = t
+}
+
+// templateMessage returns the message of an error generated with the
+// template of its Code, given its Kind, Param and TemplateData. It
+// reports whether there is a template for code which could be executed.
+func templateMessage(code Code, kind Kind, param Parameter, data TemplateData) (string, bool) {
+	if code == "" {
+		return "", false
+	}
+	templatesMu.RLock()
+	t, ok := templates[code]
+	templatesMu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	d := make(map[string]interface{}, len(data)+3)
+	for k, v := range data {
+		d[k] = v
+	}
+	d["Code"], d["Param"] = code, param
+	d["Kind"] = ""
+	if kind != Other {
+		d["Kind"] = kind.String()
+	}
+	b := new(bytes.Buffer)
+	if err := t.Execute(b, d); err != nil {
+		logf(ErrorLevel, "errors: template for code %q: %v", code, err)
+		return "", false
+	}
+	return b.String(), true
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"testing"
+	"text/template"
+)
+
+func TestRegisterTemplate(t *testing.T) {
+	defer func() {
+		templatesMu.Lock()
+		templates = map[Code]*template.Template{}
+		templatesMu.Unlock()
+	}()
+	RegisterTemplate("TooLong", "field {{.Param}} must be at most {{.Max}} characters")
+	RegisterTemplate("Locked", "{{.Code}}: {{.Kind}}")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"RE", RE(Validation, Code("TooLong"), Parameter("name"), TemplateData{"Max": 50}), "field name must be at most 50 characters"},
+		{"Builder", NewHTTP().Code("TooLong").Param("bio").Data(TemplateData{"Max": 500}).Err(), "field bio must be at most 500 characters"},
+		{"No data", RE(Permission, Code("Locked")), "Locked: permission_denied"},
+		{"Message given", RE(Code("TooLong"), Parameter("name"), Str("name is too long")), "name is too long"},
+		{"Missing data", RE(Code("TooLong"), Parameter("name")), ""},
+		{"No template", RE(Code("Other"), TemplateData{"Max": 50}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+
+	e := E(Op("users.Update"), Validation, Code("TooLong"), Parameter("name"), TemplateData{"Max": 50}).(*Error)
+	if got, want := e.Err.Error(), "field name must be at most 50 characters"; got != want {
+		t.Errorf("E() message = %q; want %q", got, want)
+	}
+}
+
+func TestRegisterTemplatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterTemplate() with an invalid template did not panic")
+		}
+	}()
+	RegisterTemplate("Bad", "{{.Param")
+}
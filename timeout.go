@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+)
+
+// timeout is implemented by errors which report whether they are a
+// timeout, such as net.Error and context.DeadlineExceeded.
+type timeout interface {
+	Timeout() bool
+}
+
+// temporary is implemented by errors which report whether they are
+// temporary, such as some net.Error implementations.
+type temporary interface {
+	Temporary() bool
+}
+
+// Timeout reports whether the error is a timeout, i.e. whether an
+// error it wraps has a Timeout method which returns true, following
+// the semantics of net.Error.
+func (e *Error) Timeout() bool {
+	return e != nil && isTimeout(e.Err)
+}
+
+// Temporary reports whether the error is temporary, i.e. whether an
+// error it wraps has a Temporary method which returns true.
+func (e *Error) Temporary() bool {
+	return e != nil && isTemporary(e.Err)
+}
+
+// Timeout reports whether the error is a timeout, as
+// (*Error).Timeout does.
+func (hse *HTTPErr) Timeout() bool {
+	return hse != nil && isTimeout(hse.Err)
+}
+
+// Temporary reports whether the error is temporary, as
+// (*Error).Temporary does.
+func (hse *HTTPErr) Temporary() bool {
+	return hse != nil && isTemporary(hse.Err)
+}
+
+// isTimeout reports whether err or an error it wraps is a timeout.
+func isTimeout(err error) bool {
+	var t timeout
+	return stderrors.As(err, &t) && t.Timeout()
+}
+
+// isTemporary reports whether err or an error it wraps is temporary.
+func isTemporary(err error) bool {
+	var t temporary
+	return stderrors.As(err, &t) && t.Temporary()
+}
+
+// FromContextErr returns the error for op of a request whose context
+// ctx is done, or nil if it is not. The error of a context whose
+// deadline was exceeded has Kind Timeout and Code Timeout, so it is sent
+// as an HTTP 504. The error of a context which was canceled, usually
+// because the client disconnected, has Kind Canceled and Code Canceled,
+// so it is sent as an HTTP 499 (see StatusClientClosedRequest); it has
+// InfoSeverity, as it is expected and needs no alert. Both errors wrap
+// ctx.Err(), so they can be tested with errors.Is.
+func FromContextErr(ctx context.Context, op Op) error {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case stderrors.Is(err, context.DeadlineExceeded):
+		e := httpErr(op, Timeout, "", &strippedError{s: "Request timed out", err: err}).(*HTTPErr)
+		e.Code = "Timeout"
+		return e
+	}
+	e := httpErr(op, Canceled, "", &strippedError{s: "Request canceled", err: err}).(*HTTPErr)
+	e.Code = "Canceled"
+	e.Severity = InfoSeverity
+	return e
+}
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tempError is a temporary error which is not a timeout.
+type tempError struct{}
+
+func (tempError) Error() string   { return "connection reset" }
+func (tempError) Temporary() bool { return true }
+
+func TestTimeoutTemporary(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantTimeout   bool
+		wantTemporary bool
+		wantStatus    int
+	}{
+		{"Timeout", E(Op("db.Query"), context.DeadlineExceeded), true, true, http.StatusGatewayTimeout},
+		{"Temporary", E(Op("db.Query"), E(Op("net.Dial"), tempError{})), false, true, http.StatusServiceUnavailable},
+		{"Neither", E(Op("db.Query"), Str("syntax error")), false, false, http.StatusInternalServerError},
+		{"HTTPErr", &HTTPErr{Err: context.DeadlineExceeded}, true, true, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.(timeout).Timeout(); got != tt.wantTimeout {
+				t.Errorf("Timeout() = %v; want %v", got, tt.wantTimeout)
+			}
+			if got := tt.err.(temporary).Temporary(); got != tt.wantTemporary {
+				t.Errorf("Temporary() = %v; want %v", got, tt.wantTemporary)
+			}
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestFromContextErr(t *testing.T) {
+	const op Op = "service.Get"
+	defer SetLogger(nil)
+
+	if err := FromContextErr(context.Background(), op); err != nil {
+		t.Errorf("FromContextErr() = %v, want nil for a context which is not done", err)
+	}
+
+	deadline, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantErr    error
+		wantStatus int
+		wantKind   Kind
+		wantCode   string
+		wantLevel  Level
+	}{
+		{"DeadlineExceeded", deadline, context.DeadlineExceeded, http.StatusGatewayTimeout, Timeout, "Timeout", ErrorLevel},
+		{"Canceled", canceled, context.Canceled, StatusClientClosedRequest, Canceled, "Canceled", InfoLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &testLogger{}
+			SetLogger(tl)
+
+			err := FromContextErr(tt.ctx, op)
+			if !stderrors.Is(err, tt.wantErr) {
+				t.Errorf("FromContextErr() = %v, does not wrap %v", err, tt.wantErr)
+			}
+			if got := Ops(err); len(got) != 1 || got[0] != op {
+				t.Errorf("Ops() = %v, want [%s]", got, op)
+			}
+
+			rr := httptest.NewRecorder()
+			HTTPError(rr, err)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if er.Error.Kind != tt.wantKind.String() || er.Error.Code != tt.wantCode {
+				t.Errorf("body = %+v, want Kind %q and Code %q", er.Error, tt.wantKind, tt.wantCode)
+			}
+			if len(tl.entries) != 1 || tl.entries[0].level != tt.wantLevel {
+				t.Errorf("logged %+v, want one entry at level %v", tl.entries, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"time"
+)
+
+// Elapsed is the time spent by an operation before it failed, given to
+// E or RE, so a failure caused by latency can be diagnosed from the
+// error alone. For example:
+//
+//	start := time.Now()
+//	...
+//	errors.E(op, errors.Timeout, errors.Elapsed(time.Since(start)), err)
+//
+// The elapsed time is logged by HTTPError, in the elapsed field, and
+// sent in debug responses (see DebugResponses).
+type Elapsed time.Duration
+
+// Deadline is the deadline of an operation which failed, given to E or
+// RE, e.g. the deadline of its context:
+//
+//	if d, ok := ctx.Deadline(); ok {
+//		return errors.E(op, errors.Timeout, errors.Deadline(d), err)
+//	}
+//
+// The deadline is logged by HTTPError, in the deadline field, and sent
+// in debug responses (see DebugResponses).
+type Deadline time.Time
+
+// ElapsedOf returns the time spent by the operation which failed with
+// err, from the first error in the chain of err which has one (see
+// Elapsed). It returns 0 if there is none.
+func ElapsedOf(err error) time.Duration {
+	for _, err := range chainOf(err) {
+		if d, _ := timingOf(err); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// DeadlineOf returns the deadline of the operation which failed with
+// err, from the first error in the chain of err which has one (see
+// Deadline), and whether there is one.
+func DeadlineOf(err error) (time.Time, bool) {
+	for _, err := range chainOf(err) {
+		if _, t := timingOf(err); !t.IsZero() {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// timingOf returns the elapsed time and the deadline attached to err
+// itself, not to the errors it wraps.
+func timingOf(err error) (time.Duration, time.Time) {
+	switch e := err.(type) {
+	case *Error:
+		return e.Elapsed, e.Deadline
+	case *HTTPErr:
+		return e.Elapsed, e.Deadline
+	}
+	return 0, time.Time{}
+}
+
+// addTiming adds the elapsed time and the deadline of err to f, if
+// known.
+func addTiming(f Fields, err error) {
+	if d := ElapsedOf(err); d > 0 {
+		f["elapsed"] = d.String()
+	}
+	if t, ok := DeadlineOf(err); ok {
+		f["deadline"] = t.Format(time.RFC3339Nano)
+	}
+}
+
+// setTiming sets the elapsed time and the deadline of l to those
+// attached to err.
+func (l *ChainLink) setTiming(err error) {
+	d, t := timingOf(err)
+	if d > 0 {
+		l.Elapsed = d.String()
+	}
+	if !t.IsZero() {
+		l.Deadline = t.Format(time.RFC3339Nano)
+	}
+}
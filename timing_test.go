@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTiming(t *testing.T) {
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		err          error
+		wantElapsed  time.Duration
+		wantDeadline time.Time
+	}{
+		{"Error", E(Op("db.Query"), Timeout, Elapsed(3*time.Second), Deadline(deadline)), 3 * time.Second, deadline},
+		{"HTTPErr", RE(http.StatusGatewayTimeout, Timeout, Elapsed(time.Second)), time.Second, time.Time{}},
+		{"Wrapped", E(Op("service.Get"), E(Op("db.Query"), Timeout, Deadline(deadline))), 0, deadline},
+		{"Outermost first", E(Elapsed(2*time.Second), E(Timeout, Elapsed(time.Second))), 2 * time.Second, time.Time{}},
+		{"None", E(Op("db.Query"), IO), 0, time.Time{}},
+		{"Other error", Str("timeout"), 0, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ElapsedOf(tt.err); got != tt.wantElapsed {
+				t.Errorf("ElapsedOf() = %v; want %v", got, tt.wantElapsed)
+			}
+			got, ok := DeadlineOf(tt.err)
+			if !got.Equal(tt.wantDeadline) || ok == tt.wantDeadline.IsZero() {
+				t.Errorf("DeadlineOf() = %v, %v; want %v", got, ok, tt.wantDeadline)
+			}
+		})
+	}
+}
+
+func TestTimingLogged(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := E(Op("db.Query"), Timeout, Elapsed(1500*time.Millisecond), Deadline(deadline), Str("query timed out"))
+	HTTPError(httptest.NewRecorder(), err)
+
+	f := tl.entries[len(tl.entries)-1].fields
+	if f["elapsed"] != "1.5s" {
+		t.Errorf("fields[elapsed] = %v; want %q", f["elapsed"], "1.5s")
+	}
+	if f["deadline"] != "2024-03-01T12:00:00Z" {
+		t.Errorf("fields[deadline] = %v; want %q", f["deadline"], "2024-03-01T12:00:00Z")
+	}
+}
+
+func TestTimingDebugChain(t *testing.T) {
+	defer func(b bool) { DebugResponses = b }(DebugResponses)
+	DebugResponses = true
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := E(Op("service.Get"), E(Op("db.Query"), Timeout, Elapsed(time.Second), Deadline(deadline), Str("query timed out")))
+	rr := httptest.NewRecorder()
+	HTTPError(rr, err)
+
+	var er ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatal(err)
+	}
+	want := []ChainLink{
+		{Op: "service.Get", Kind: "timeout"},
+		{Op: "db.Query", Elapsed: "1s", Deadline: "2024-03-01T12:00:00Z"},
+		{Message: "query timed out"},
+	}
+	if !reflect.DeepEqual(er.Error.Chain, want) {
+		t.Errorf("Chain = %+v; want %+v", er.Error.Chain, want)
+	}
+}
+
+func TestTimingJSON(t *testing.T) {
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	err := E(Op("db.Query"), Timeout, Elapsed(time.Second), Deadline(deadline)).(*Error)
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var got Error
+	if jerr := json.Unmarshal(b, &got); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if got.Elapsed != time.Second || !got.Deadline.Equal(deadline) {
+		t.Errorf("Elapsed, Deadline = %v, %v; want %v, %v", got.Elapsed, got.Deadline, time.Second, deadline)
+	}
+}
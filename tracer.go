@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer records the errors sent by HTTPError on the trace of their
+// request, e.g. with OpenTelemetry (see the otelerrors package).
+// Register an implementation with SetTracer.
+type Tracer interface {
+	// ErrorSent is called by HTTPError for each error response sent,
+	// with the Kind and Code of the error and the HTTP status code.
+	ErrorSent(ctx context.Context, err error, kind Kind, code Code, status int)
+	// TraceID returns the ID of the trace of ctx, which is sent in
+	// error responses, or "" if there is none.
+	TraceID(ctx context.Context) string
+}
+
+// tracer is the Tracer used by the package. By default, it is a
+// no-op implementation.
+var tracer Tracer = noopTracer{}
+
+// SetTracer sets the Tracer notified of the errors sent. If t is nil,
+// the default no-op implementation is restored. SetTracer should be
+// called at program start, before any errors are sent.
+func SetTracer(t Tracer) {
+	if t == nil {
+		t = noopTracer{}
+	}
+	tracer = t
+}
+
+// noopTracer is a Tracer which does nothing.
+type noopTracer struct{}
+
+func (noopTracer) ErrorSent(context.Context, error, Kind, Code, int) {}
+func (noopTracer) TraceID(context.Context) string                    { return "" }
+
+// errorSent notifies the Metrics and the Tracer that err is sent with
+// the given HTTP status code, sends it to the Reporter and persists its
+// Snapshot if it is a server error, and sends it to the AuditSink if it
+// is an access denial. All but the Tracer may be notified in the
+// background (see SetAsyncReporting).
+func errorSent(ctx context.Context, err error, status int) {
+	kind, code := classify(err)
+	tracer.ErrorSent(ctx, err, kind, code, status)
+	at := now()
+	ctx = context.WithoutCancel(ctx)
+	dispatch(func() {
+		metrics.ErrorSent(ctx, kind, code, status)
+		if status >= http.StatusInternalServerError {
+			report(ctx, err, status)
+		}
+		persist(ctx, err, status, at)
+		audit(ctx, err, status, at)
+	})
+}
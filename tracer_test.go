@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testTracer records the errors sent and returns a fixed trace ID.
+type testTracer struct {
+	sent []string
+}
+
+func (tt *testTracer) ErrorSent(_ context.Context, err error, kind Kind, code Code, status int) {
+	tt.sent = append(tt.sent, kind.String()+"/"+string(code)+"/"+http.StatusText(status))
+}
+
+func (tt *testTracer) TraceID(context.Context) string {
+	return "4bf92f3577b34da6a3ce929d0e0e4736"
+}
+
+func TestTracer(t *testing.T) {
+	defer SetTracer(nil)
+	defer func() { ProblemDetails = false }()
+	tr := &testTracer{}
+	SetTracer(tr)
+
+	err := RE(http.StatusNotFound, NotExist, Code("no_user"), Str("no such user"))
+	rr := httptest.NewRecorder()
+	HTTPError(rr, err)
+	var er ErrResponse
+	if jerr := json.Unmarshal(rr.Body.Bytes(), &er); jerr != nil {
+		t.Fatalf("json.Unmarshal() error = %v", jerr)
+	}
+	if er.Error.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace_id = %q; want the trace ID", er.Error.TraceID)
+	}
+
+	ProblemDetails = true
+	rr = httptest.NewRecorder()
+	HTTPError(rr, err)
+	var pr ProblemResponse
+	if jerr := json.Unmarshal(rr.Body.Bytes(), &pr); jerr != nil {
+		t.Fatalf("json.Unmarshal() error = %v", jerr)
+	}
+	if pr.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("problem trace_id = %q; want the trace ID", pr.TraceID)
+	}
+
+	if len(tr.sent) != 2 || tr.sent[0] != "item_does_not_exist/no_user/Not Found" {
+		t.Errorf("ErrorSent calls = %v; want 2 for item_does_not_exist/no_user/Not Found", tr.sent)
+	}
+}
@@ -0,0 +1,58 @@
+package errors
+
+import "sync"
+
+// TranslateFunc converts the errors of a third-party package, such as
+// the SDK of an API, into errors of this package with a Kind and Code,
+// e.g. so the outages of an upstream service are sent as an HTTP 503
+// rather than as unanticipated errors. It returns nil if it does not
+// translate err. The translated error should wrap err, so its chain
+// can still be inspected. Register it with RegisterTranslator.
+type TranslateFunc func(err error) error
+
+var (
+	translateMu sync.RWMutex
+	// translators are the TranslateFuncs registered, in order.
+	translators []TranslateFunc
+)
+
+// RegisterTranslator registers fn to translate the errors sent by
+// HTTPError which are not an HTTPErr or a ValidationErrors, before
+// they are sent as determined by FallbackResponse. The TranslateFuncs
+// are tried in the order they were registered. For example, with the
+// awserrors package:
+//
+//	errors.RegisterTranslator(awserrors.Translate)
+//
+// RegisterTranslator is meant to be called during program
+// initialization.
+func RegisterTranslator(fn TranslateFunc) {
+	translateMu.Lock()
+	defer translateMu.Unlock()
+	translators = append(translators, fn)
+}
+
+// Translate returns err translated by the first registered
+// TranslateFunc which translates it, or err if none does.
+func Translate(err error) error {
+	if te := translate(err); te != nil {
+		return te
+	}
+	return err
+}
+
+// translate returns err translated by the first registered
+// TranslateFunc which translates it, or nil if none does.
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	translateMu.RLock()
+	defer translateMu.RUnlock()
+	for _, fn := range translators {
+		if te := fn(err); te != nil {
+			return te
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"testing"
+)
+
+// upstreamError is an error of a third-party SDK.
+type upstreamError struct {
+	status int
+}
+
+func (e *upstreamError) Error() string { return "upstream failed" }
+
+func TestTranslate(t *testing.T) {
+	defer func() {
+		translateMu.Lock()
+		translators = nil
+		translateMu.Unlock()
+	}()
+	RegisterTranslator(func(err error) error {
+		var ue *upstreamError
+		if !stderrors.As(err, &ue) {
+			return nil
+		}
+		if ue.status >= http.StatusInternalServerError {
+			return RE(http.StatusServiceUnavailable, IO, Code("Unavailable"), err)
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantKind   Kind
+		wantCode   Code
+	}{
+		{"Translated", &upstreamError{status: http.StatusBadGateway}, http.StatusServiceUnavailable, IO, "Unavailable"},
+		{"Wrapped", E(Op("users.Get"), &upstreamError{status: http.StatusInternalServerError}), http.StatusServiceUnavailable, IO, "Unavailable"},
+		{"Not translated", &upstreamError{status: http.StatusBadRequest}, http.StatusInternalServerError, Unanticipated, "Unanticipated"},
+		{"Other error", Str("some error"), http.StatusInternalServerError, Unanticipated, "Unanticipated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusOf(tt.err); got != tt.wantStatus {
+				t.Errorf("StatusOf() = %d; want %d", got, tt.wantStatus)
+			}
+			if kind, code := classify(tt.err); kind != tt.wantKind || code != tt.wantCode {
+				t.Errorf("classify() = %v, %q; want %v, %q", kind, code, tt.wantKind, tt.wantCode)
+			}
+		})
+	}
+
+	err := &upstreamError{status: http.StatusBadRequest}
+	if got := Translate(err); got != error(err) {
+		t.Errorf("Translate() = %v; want the error unchanged", got)
+	}
+	if got := Translate(&upstreamError{status: http.StatusBadGateway}); KindOf(got) != IO {
+		t.Errorf("Translate() Kind = %v; want %v", KindOf(got), IO)
+	}
+}
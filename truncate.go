@@ -0,0 +1,39 @@
+package errors
+
+import "unicode/utf8"
+
+// MaxMessageLength is the maximum length in bytes of the error messages
+// sent by HTTPError and of the messages of its log entries. Longer
+// messages, such as an error echoing a large response of an upstream
+// service, are cut at that length, on a rune boundary, and end with
+// TruncatedSuffix. Log entries of truncated messages also have the
+// truncated field set. Set it to 0 to never truncate messages.
+var MaxMessageLength = 4096
+
+// TruncatedSuffix is appended to the messages truncated to
+// MaxMessageLength, so clients and operators can tell they are
+// incomplete.
+var TruncatedSuffix = "… (truncated)"
+
+// truncateMessage returns s cut to MaxMessageLength bytes, without
+// splitting a multi-byte rune, followed by TruncatedSuffix. It reports
+// whether s was truncated.
+func truncateMessage(s string) (string, bool) {
+	if MaxMessageLength <= 0 || len(s) <= MaxMessageLength {
+		return s, false
+	}
+	n := MaxMessageLength
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + TruncatedSuffix, true
+}
+
+// truncateServiceError truncates the messages of se and of the errors
+// it lists.
+func truncateServiceError(se *ServiceError) {
+	se.Message, _ = truncateMessage(se.Message)
+	for i := range se.Errors {
+		truncateServiceError(&se.Errors[i])
+	}
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateMessage(t *testing.T) {
+	defer func(n int) { MaxMessageLength = n }(MaxMessageLength)
+	MaxMessageLength = 5
+
+	tests := []struct {
+		name          string
+		s             string
+		want          string
+		wantTruncated bool
+	}{
+		{"Short", "abc", "abc", false},
+		{"Exact", "abcde", "abcde", false},
+		{"Long", "abcdefgh", "abcde" + TruncatedSuffix, true},
+		// "é" is two bytes, so it is not cut in half
+		{"Multi-byte rune", "abcdé", "abcd" + TruncatedSuffix, true},
+		{"Empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncateMessage(tt.s)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("truncateMessage(%q) = %q, %v; want %q, %v", tt.s, got, truncated, tt.want, tt.wantTruncated)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateMessage(%q) = %q; not valid UTF-8", tt.s, got)
+			}
+		})
+	}
+
+	MaxMessageLength = 0
+	if got, truncated := truncateMessage("abcdefgh"); got != "abcdefgh" || truncated {
+		t.Errorf("truncateMessage() with no limit = %q, %v; want it unchanged", got, truncated)
+	}
+}
+
+func TestHTTPErrorTruncated(t *testing.T) {
+	defer func(n int) { MaxMessageLength = n }(MaxMessageLength)
+	MaxMessageLength = 16
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	blob := "<error>" + strings.Repeat("ü", 100) + "</error>"
+	w := httptest.NewRecorder()
+	HTTPError(w, RE(http.StatusBadGateway, IO, Str(blob)))
+
+	var er ErrResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &er); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(er.Error.Message, TruncatedSuffix) || len(er.Error.Message) > 16+len(TruncatedSuffix) {
+		t.Errorf("Message = %q; want it truncated", er.Error.Message)
+	}
+	entry := tl.entries[len(tl.entries)-1]
+	if !strings.HasSuffix(entry.msg, TruncatedSuffix) {
+		t.Errorf("log message = %q; want it truncated", entry.msg)
+	}
+	if entry.fields["truncated"] != true {
+		t.Errorf("fields[truncated] = %v; want true", entry.fields["truncated"])
+	}
+}
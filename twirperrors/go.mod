@@ -0,0 +1,15 @@
+module github.com/gilcrest/errors/twirperrors
+
+go 1.21
+
+require (
+	github.com/gilcrest/errors v0.0.0
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/rs/zerolog v1.14.0 // indirect
+)
+
+replace github.com/gilcrest/errors => ../
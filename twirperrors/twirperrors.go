@@ -0,0 +1,171 @@
+// Package twirperrors converts errors built with the errors package
+// into Twirp errors, so services that serve Twirp alongside REST
+// classify errors the same way on either wire format.
+package twirperrors
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gilcrest/errors"
+	"github.com/twitchtv/twirp"
+)
+
+// TwirpError converts err into a twirp.Error. The Twirp code is
+// determined from the Kind errors.HTTPError would send for the error
+// or, if it has none, from the HTTP status code it would send. The
+// message is the one errors.HTTPError would send, and the Kind, Code
+// and Param of the error, if any, are added to the metadata under the
+// kind, code and param keys, so they are rendered in the meta object of
+// the Twirp JSON error envelope. The returned error wraps err.
+//
+// Errors which are already Twirp errors are returned as is. If err is
+// nil, TwirpError returns nil.
+func TwirpError(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+	var te twirp.Error
+	if stderrors.As(err, &te) {
+		return te
+	}
+
+	pr := errors.NewProblemResponse(err)
+	code := twirp.Unknown
+	if k := errors.KindOf(err); pr.Kind == k.String() {
+		code = KindCode(k)
+	}
+	if code == twirp.Unknown {
+		code = HTTPStatusCode(pr.Status)
+	}
+	msg := pr.Detail
+	if msg == "" {
+		msg = pr.Title
+	}
+	te = twirp.NewError(code, msg)
+	if pr.Kind != "" {
+		te = te.WithMeta("kind", pr.Kind)
+	}
+	if pr.Code != "" {
+		te = te.WithMeta("code", pr.Code)
+	}
+	if pr.Param != "" {
+		te = te.WithMeta("param", pr.Param)
+	}
+	return twirp.WrapError(te, err)
+}
+
+// KindCode returns the Twirp code for the given Kind.
+func KindCode(k errors.Kind) twirp.ErrorCode {
+	switch k {
+	case errors.Invalid, errors.Validation:
+		return twirp.InvalidArgument
+	case errors.InvalidRequest:
+		return twirp.Malformed
+	case errors.Permission, errors.Private:
+		return twirp.PermissionDenied
+	case errors.IO:
+		return twirp.Unavailable
+	case errors.Exist:
+		return twirp.AlreadyExists
+	case errors.NotExist, errors.BrokenLink:
+		return twirp.NotFound
+	case errors.Internal, errors.Database:
+		return twirp.Internal
+	case errors.Timeout:
+		return twirp.DeadlineExceeded
+	case errors.Canceled:
+		return twirp.Canceled
+	case errors.Unauthorized:
+		return twirp.Unauthenticated
+	}
+	return twirp.Unknown
+}
+
+// HTTPStatusCode returns the Twirp code for the given HTTP status code.
+func HTTPStatusCode(sc int) twirp.ErrorCode {
+	switch sc {
+	case http.StatusBadRequest:
+		return twirp.InvalidArgument
+	case http.StatusUnauthorized:
+		return twirp.Unauthenticated
+	case http.StatusForbidden:
+		return twirp.PermissionDenied
+	case http.StatusNotFound:
+		return twirp.NotFound
+	case http.StatusConflict:
+		return twirp.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return twirp.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return twirp.ResourceExhausted
+	case 499: // Client Closed Request
+		return twirp.Canceled
+	case http.StatusNotImplemented:
+		return twirp.Unimplemented
+	case http.StatusServiceUnavailable:
+		return twirp.Unavailable
+	case http.StatusGatewayTimeout:
+		return twirp.DeadlineExceeded
+	}
+	switch {
+	case sc >= 400 && sc < 500:
+		return twirp.FailedPrecondition
+	case sc >= 500:
+		return twirp.Internal
+	}
+	return twirp.Unknown
+}
+
+// WriteError logs and counts err with errors.ReportError, then writes
+// it to w in the Twirp JSON error envelope, converted with TwirpError,
+// for handlers which serve Twirp clients outside a generated server:
+//
+//	{
+//		"code": "not_found",
+//		"msg": "user not found",
+//		"meta": {"kind": "item_does_not_exist"}
+//	}
+//
+// It returns the error of writing the response, if any. If err is nil,
+// WriteError writes nothing and returns nil.
+func WriteError(ctx context.Context, w http.ResponseWriter, err error) error {
+	if err == nil {
+		return nil
+	}
+	report(ctx, err)
+	return twirp.WriteError(w, TwirpError(err))
+}
+
+// Interceptor returns a twirp.Interceptor which converts the errors
+// returned by the methods of a Twirp server with TwirpError. Each error
+// is logged and counted with errors.ReportError, with the name of the
+// Twirp method in the twirp_method field, as errors.HTTPError does for
+// HTTP handlers:
+//
+//	server := pb.NewUsersServer(svc,
+//		twirp.WithServerInterceptors(twirperrors.Interceptor()))
+func Interceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				report(ctx, err)
+				return resp, TwirpError(err)
+			}
+			return resp, nil
+		}
+	}
+}
+
+// report logs and counts err, adding the name of the Twirp method
+// of ctx, if any.
+func report(ctx context.Context, err error) {
+	f := errors.Fields{}
+	service, _ := twirp.ServiceName(ctx)
+	if method, ok := twirp.MethodName(ctx); ok {
+		f["twirp_method"] = service + "." + method
+	}
+	errors.ReportError(ctx, err, f)
+}
@@ -0,0 +1,101 @@
+package twirperrors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gilcrest/errors"
+	"github.com/twitchtv/twirp"
+)
+
+func TestTwirpError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode twirp.ErrorCode
+		wantMsg  string
+		wantMeta map[string]string
+	}{
+		{"Kind", errors.RE(http.StatusNotFound, errors.NotExist, errors.Code("no_user"), errors.Str("user not found")),
+			twirp.NotFound, "user not found", map[string]string{"kind": "item_does_not_exist", "code": "no_user"}},
+		{"Param", errors.RE(errors.Validation, errors.Parameter("name"), errors.Str("name is required")),
+			twirp.InvalidArgument, "name is required", map[string]string{"kind": "input_validation_error", "param": "name"}},
+		{"Status", errors.RE(http.StatusServiceUnavailable, errors.Str("try later")),
+			twirp.Unavailable, "try later", nil},
+		{"Status only", errors.RE(http.StatusConflict),
+			twirp.AlreadyExists, "Conflict", nil},
+		{"Unknown", errors.Str("pq: connection refused"),
+			twirp.Internal, "Unexpected error - contact support", map[string]string{"kind": "unanticipated_error", "code": "Unanticipated"}},
+		{"Twirp", twirp.NotFoundError("no user"), twirp.NotFound, "no user", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			te := TwirpError(tt.err)
+			if te.Code() != tt.wantCode {
+				t.Errorf("Code() = %v; want %v", te.Code(), tt.wantCode)
+			}
+			if te.Msg() != tt.wantMsg {
+				t.Errorf("Msg() = %q; want %q", te.Msg(), tt.wantMsg)
+			}
+			if got := te.MetaMap(); len(got) != len(tt.wantMeta) || len(got) > 0 && !reflect.DeepEqual(got, tt.wantMeta) {
+				t.Errorf("MetaMap() = %v; want %v", got, tt.wantMeta)
+			}
+			if !stderrors.Is(te, tt.err) {
+				t.Error("errors.Is(TwirpError(err), err) = false; want true")
+			}
+		})
+	}
+	if TwirpError(nil) != nil {
+		t.Error("TwirpError(nil) != nil")
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	err := errors.RE(http.StatusNotFound, errors.NotExist, errors.Str("user not found"))
+	if werr := WriteError(context.Background(), rr, err); werr != nil {
+		t.Fatalf("WriteError() error = %v", werr)
+	}
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusNotFound)
+	}
+	var body struct {
+		Code string            `json:"code"`
+		Msg  string            `json:"msg"`
+		Meta map[string]string `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if body.Code != "not_found" || body.Msg != "user not found" || body.Meta["kind"] != "item_does_not_exist" {
+		t.Errorf("body = %s; want the Twirp envelope of the error", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	if werr := WriteError(context.Background(), rr, nil); werr != nil || rr.Body.Len() != 0 {
+		t.Errorf("WriteError(nil) = %v, wrote %q; want nil and nothing written", werr, rr.Body.String())
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	method := Interceptor()(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.RE(errors.Permission, errors.Str("denied"))
+	})
+	_, err := method(context.Background(), nil)
+	var te twirp.Error
+	if !stderrors.As(err, &te) || te.Code() != twirp.PermissionDenied {
+		t.Errorf("error = %v; want a Twirp permission_denied error", err)
+	}
+
+	method = Interceptor()(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if resp, err := method(context.Background(), nil); resp != "ok" || err != nil {
+		t.Errorf("method() = %v, %v; want ok, nil", resp, err)
+	}
+}
@@ -0,0 +1,72 @@
+// Package validate provides the rules of a declarative validation of
+// input, whose results are aggregated by errors.Check:
+//
+//	if err := errors.Check(
+//		validate.Required("name", u.Name),
+//		validate.Range("age", u.Age, 0, 120),
+//		validate.OneOf("role", u.Role, "admin", "member"),
+//	); err != nil {
+//		return err
+//	}
+//
+// Each rule returns nil if the value satisfies it, or else an
+// *errors.FieldError of Kind Validation, with the field as the
+// Parameter and a Code identifying the rule, so the response of
+// errors.HTTPError lists each invalid field.
+package validate
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/gilcrest/errors"
+)
+
+// Required returns an error with Code Required if v is the zero value
+// of its type, e.g. an empty string.
+func Required[T comparable](name string, v T) error {
+	var zero T
+	if v == zero {
+		return errors.RequiredField(name)
+	}
+	return nil
+}
+
+// Range returns an error with Code OutOfRange if v is not between min
+// and max, inclusive.
+func Range[T cmp.Ordered](name string, v, min, max T) error {
+	if v < min || v > max {
+		return errors.OutOfRange(name, min, max)
+	}
+	return nil
+}
+
+// MaxLen returns an error with Code TooLong if s has more than max
+// characters.
+func MaxLen(name, s string, max int) error {
+	if utf8.RuneCountInString(s) > max {
+		return errors.TooLong(name, max)
+	}
+	return nil
+}
+
+// OneOf returns an error with Code BadEnum if v is not one of the
+// allowed values.
+func OneOf(name, v string, allowed ...string) error {
+	if !slices.Contains(allowed, v) {
+		return errors.BadEnum(name, allowed...)
+	}
+	return nil
+}
+
+// Match returns an error with Code InvalidField if s does not match
+// re. The message of the error gives the reason, e.g. "must be a
+// lowercase identifier", rather than the pattern.
+func Match(name, s string, re *regexp.Regexp, reason string) error {
+	if !re.MatchString(s) {
+		return errors.InvalidField(name, reason)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package validate
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gilcrest/errors"
+)
+
+func TestRules(t *testing.T) {
+	ident := regexp.MustCompile(`^[a-z_]+$`)
+	tests := []struct {
+		name     string
+		err      error
+		wantCode errors.Code
+	}{
+		{"Required", Required("name", ""), "Required"},
+		{"Required ok", Required("name", "joe"), ""},
+		{"Required int", Required("count", 0), "Required"},
+		{"Range below", Range("age", -1, 0, 120), "OutOfRange"},
+		{"Range above", Range("age", 121, 0, 120), "OutOfRange"},
+		{"Range ok", Range("age", 120, 0, 120), ""},
+		{"MaxLen", MaxLen("bio", "héllo", 4), "TooLong"},
+		{"MaxLen ok", MaxLen("bio", "héllo", 5), ""},
+		{"OneOf", OneOf("role", "root", "admin", "member"), "BadEnum"},
+		{"OneOf ok", OneOf("role", "admin", "admin", "member"), ""},
+		{"Match", Match("handle", "Joe!", ident, "must be a lowercase identifier"), "InvalidField"},
+		{"Match ok", Match("handle", "joe", ident, "must be a lowercase identifier"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantCode == "" {
+				if tt.err != nil {
+					t.Errorf("err = %v; want nil", tt.err)
+				}
+				return
+			}
+			fe, ok := tt.err.(*errors.FieldError)
+			if !ok {
+				t.Fatalf("err = %T; want *errors.FieldError", tt.err)
+			}
+			if fe.Code != tt.wantCode {
+				t.Errorf("Code = %q; want %q", fe.Code, tt.wantCode)
+			}
+			if fe.Param == "" {
+				t.Error("Param is empty")
+			}
+		})
+	}
+}
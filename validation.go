@@ -1,5 +1,12 @@
 package errors
 
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
 // MissingField is an error type that can be used when
 // validating input fields that do not have a value, but should
 type MissingField string
@@ -15,3 +22,256 @@ type InputUnwanted string
 func (e InputUnwanted) Error() string {
 	return string(e) + " has a value, but should be nil"
 }
+
+// FieldError is an error type for an input field which is invalid,
+// with a Code identifying the constraint it does not satisfy. Build one
+// with RequiredField, InvalidField, OutOfRange, TooLong or BadEnum. It is sent by
+// HTTPError as an HTTP 400 with Kind Validation, on its own or as one
+// of the errors of a ValidationErrors.
+type FieldError struct {
+	Param   Parameter
+	Code    Code
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// ErrKind returns the Kind of the error, which is always Validation.
+func (e *FieldError) ErrKind() string {
+	return Validation.String()
+}
+
+// ErrParam returns the input field the error relates to.
+func (e *FieldError) ErrParam() string {
+	return string(e.Param)
+}
+
+// ErrCode returns the Code of the error.
+func (e *FieldError) ErrCode() string {
+	return string(e.Code)
+}
+
+// Status returns the HTTP status code of the error, which is always
+// 400 (Bad Request).
+func (e *FieldError) Status() int {
+	return http.StatusBadRequest
+}
+
+// StatusOnly always returns false, as the error has a message.
+func (e *FieldError) StatusOnly() bool {
+	return false
+}
+
+// RequiredField returns an error with Code Required for the input
+// field name, which has no value.
+func RequiredField(name string) error {
+	return &FieldError{
+		Param:   Parameter(name),
+		Code:    "Required",
+		Message: name + " is required",
+	}
+}
+
+// InvalidField returns an error with Code InvalidField for the input
+// field name, whose value is invalid for the given reason.
+func InvalidField(name, reason string) error {
+	return &FieldError{
+		Param:   Parameter(name),
+		Code:    "InvalidField",
+		Message: fmt.Sprintf("%s is invalid: %s", name, reason),
+	}
+}
+
+// OutOfRange returns an error with Code OutOfRange for the input field
+// name, whose value is not between min and max.
+func OutOfRange[T any](name string, min, max T) error {
+	return &FieldError{
+		Param:   Parameter(name),
+		Code:    "OutOfRange",
+		Message: fmt.Sprintf("%s must be between %v and %v", name, min, max),
+	}
+}
+
+// TooLong returns an error with Code TooLong for the input field name,
+// whose value is longer than max, e.g. in characters or items.
+func TooLong(name string, max int) error {
+	return &FieldError{
+		Param:   Parameter(name),
+		Code:    "TooLong",
+		Message: fmt.Sprintf("%s must have a length of at most %d", name, max),
+	}
+}
+
+// BadEnum returns an error with Code BadEnum for the input field name,
+// whose value is not one of the allowed values.
+func BadEnum(name string, allowed ...string) error {
+	return &FieldError{
+		Param:   Parameter(name),
+		Code:    "BadEnum",
+		Message: fmt.Sprintf("%s must be one of: %s", name, strings.Join(allowed, ", ")),
+	}
+}
+
+// InvalidValue is an error type that can be used when validating
+// input fields that have a value which does not satisfy a constraint.
+// It records the offending value and the constraint, which are sent
+// to the client in the got and want fields of the error response.
+// Build one with Param.
+type InvalidValue struct {
+	Param Parameter
+	Got   interface{}
+	Want  string
+}
+
+func (e *InvalidValue) Error() string {
+	return fmt.Sprintf("%s is invalid: got %v, want %s", e.Param, e.Got, e.Want)
+}
+
+// Param returns an error of Kind Validation for the input parameter
+// name, whose value got does not satisfy the constraint want, e.g.
+//
+//	errors.Param("age", age, "between 0 and 150")
+//
+// It is sent as an HTTP 400 with the value and the constraint, unless
+// name is one of the sensitive Params of the Redaction policy. got
+// must be a value which can be encoded as JSON.
+func Param[T any](name string, got T, want string) error {
+	e := &HTTPErr{
+		Kind:  Validation,
+		Param: Parameter(name),
+		Err:   &InvalidValue{Param: Parameter(name), Got: got, Want: want},
+	}
+	e.trace = captureStack(1)
+	e.pc = captureLocation(0)
+	e.recordCreated()
+	return e
+}
+
+// invalidValueOf returns the InvalidValue in the chain of err, if any.
+func invalidValueOf(err error) *InvalidValue {
+	var iv *InvalidValue
+	if stderrors.As(err, &iv) {
+		return iv
+	}
+	return nil
+}
+
+// ValidationErrors collects the errors found while validating input,
+// such as MissingField and InputUnwanted, so they can all be reported
+// at once instead of only the first one. When sent with HTTPError,
+// a ValidationErrors is rendered as a single HTTP 400 response which
+// lists every error.
+type ValidationErrors []error
+
+// Add appends err to the collection. A nil err is ignored.
+func (ve *ValidationErrors) Add(err error) {
+	if err == nil {
+		return
+	}
+	*ve = append(*ve, err)
+}
+
+// Err returns the collection as an error, or nil if no errors
+// have been added.
+func (ve ValidationErrors) Err() error {
+	if len(ve) == 0 {
+		return nil
+	}
+	return ve
+}
+
+// Unwrap returns the errors of the collection, so they can be
+// inspected with errors.Is and errors.As.
+func (ve ValidationErrors) Unwrap() []error {
+	return ve
+}
+
+func (ve ValidationErrors) Error() string {
+	s := make([]string, len(ve))
+	for i, err := range ve {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Check returns the errors of errs which are not nil, such as the
+// results of the rules of the validate package, as a ValidationErrors,
+// or nil if they are all nil:
+//
+//	if err := errors.Check(
+//		validate.Required("name", u.Name),
+//		validate.Range("age", u.Age, 0, 120),
+//	); err != nil {
+//		return err
+//	}
+func Check(errs ...error) error {
+	var ve ValidationErrors
+	for _, err := range errs {
+		ve.Add(err)
+	}
+	return ve.Err()
+}
+
+// serviceErrors returns a ServiceError for each error in the collection.
+func (ve ValidationErrors) serviceErrors() []ServiceError {
+	ses := make([]ServiceError, len(ve))
+	for i, err := range ve {
+		ses[i] = ServiceError{
+			Param:   string(paramOf(err)),
+			Message: err.Error(),
+		}
+		if e, ok := err.(hError); ok {
+			ses[i].Kind = e.ErrKind()
+			ses[i].Code = e.ErrCode()
+		}
+		if iv := invalidValueOf(err); iv != nil {
+			ses[i].Got, ses[i].Want = iv.Got, iv.Want
+		}
+	}
+	return ses
+}
+
+// FieldViolation describes the error for a single parameter of a
+// request, so clients can highlight the inputs in error. It is sent
+// for each error of a ValidationErrors which relates to a parameter.
+type FieldViolation struct {
+	Param   string `json:"param" xml:"param"`
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Message string `json:"message,omitempty" xml:"message,omitempty"`
+	// Got is the invalid value of the parameter and Want the
+	// constraint it does not satisfy, if known (see Param)
+	Got  interface{} `json:"got,omitempty" xml:"got,omitempty"`
+	Want string      `json:"want,omitempty" xml:"want,omitempty"`
+}
+
+// fieldViolations returns the FieldViolations of the service errors
+// which relate to a parameter.
+func fieldViolations(ses []ServiceError) []FieldViolation {
+	var fvs []FieldViolation
+	for _, se := range ses {
+		if se.Param == "" {
+			continue
+		}
+		fvs = append(fvs, FieldViolation{Param: se.Param, Code: se.Code, Message: se.Message, Got: se.Got, Want: se.Want})
+	}
+	return fvs
+}
+
+// paramOf returns the Parameter an error relates to, if any.
+func paramOf(err error) Parameter {
+	switch e := err.(type) {
+	case MissingField:
+		return Parameter(e)
+	case InputUnwanted:
+		return Parameter(e)
+	case *InvalidValue:
+		return e.Param
+	case *Error:
+		return e.Param
+	case hError:
+		return Parameter(e.ErrParam())
+	}
+	return ""
+}
@@ -0,0 +1,254 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestValidationErrors(t *testing.T) {
+	var ve ValidationErrors
+	if ve.Err() != nil {
+		t.Fatal("Err() of empty ValidationErrors != nil")
+	}
+	ve.Add(MissingField("first_name"))
+	ve.Add(nil)
+	ve.Add(InputUnwanted("id"))
+	ve.Add(RE(http.StatusBadRequest, Validation, Code("invalid_date"), Parameter("birth_date"), Str("birth_date is invalid")))
+
+	want := "first_name is required; id has a value, but should be nil; birth_date is invalid"
+	if ve.Error() != want {
+		t.Errorf("Error() = %q; want %q", ve.Error(), want)
+	}
+
+	tests := []struct {
+		name    string
+		err     error
+		problem bool
+	}{
+		{"ValidationErrors", ve.Err(), false},
+		{"Wrapped", E(Op("errors/TestValidationErrors"), ve.Err()), false},
+		{"HTTPErr", RE(http.StatusBadRequest, Validation, ve.Err()), false},
+		{"ProblemDetails", ve.Err(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(prev bool) {
+				ProblemDetails = prev
+			}(ProblemDetails)
+			ProblemDetails = tt.problem
+
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d; want %d", rr.Code, http.StatusBadRequest)
+			}
+			var errs []ServiceError
+			if tt.problem {
+				var pr ProblemResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &pr); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				errs = pr.Errors
+			} else {
+				var er ErrResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				errs = er.Error.Errors
+			}
+			if len(errs) != 3 {
+				t.Fatalf("got %d errors; want 3", len(errs))
+			}
+			for i, param := range []string{"first_name", "id", "birth_date"} {
+				if errs[i].Param != param {
+					t.Errorf("Errors[%d].Param = %q; want %q", i, errs[i].Param, param)
+				}
+			}
+			if errs[2].Code != "invalid_date" {
+				t.Errorf("Errors[2].Code = %q; want %q", errs[2].Code, "invalid_date")
+			}
+		})
+	}
+}
+
+func TestFieldViolations(t *testing.T) {
+	var ve ValidationErrors
+	ve.Add(MissingField("first_name"))
+	ve.Add(Str("start_date is after end_date"))
+	ve.Add(RE(http.StatusBadRequest, Validation, Code("invalid_date"), Parameter("birth_date"), Str("birth_date is invalid")))
+
+	want := []FieldViolation{
+		{Param: "first_name", Message: "first_name is required"},
+		{Param: "birth_date", Code: "invalid_date", Message: "birth_date is invalid"},
+	}
+	for _, problem := range []bool{false, true} {
+		func() {
+			defer func(prev bool) {
+				ProblemDetails = prev
+			}(ProblemDetails)
+			ProblemDetails = problem
+
+			rr := httptest.NewRecorder()
+			HTTPError(rr, ve.Err())
+
+			var got []FieldViolation
+			if problem {
+				var pr ProblemResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &pr); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				got = pr.Fields
+			} else {
+				var er ErrResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				got = er.Error.Fields
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ProblemDetails = %t: Fields = %+v; want %+v", problem, got, want)
+			}
+		}()
+	}
+}
+
+func TestParam(t *testing.T) {
+	defer func(prev RedactionPolicy) {
+		Redaction = prev
+	}(Redaction)
+	Redaction = RedactionPolicy{Params: []Parameter{"pin"}}
+
+	tests := []struct {
+		name string
+		err  error
+		want ServiceError
+	}{
+		{
+			"Single",
+			Param("age", 200, "between 0 and 150"),
+			ServiceError{
+				Kind:    Validation.String(),
+				Param:   "age",
+				Message: "age is invalid: got 200, want between 0 and 150",
+				Got:     200.0,
+				Want:    "between 0 and 150",
+			},
+		},
+		{
+			"Sensitive",
+			Param("pin", "1234", "6 digits"),
+			ServiceError{
+				Kind:    Validation.String(),
+				Param:   "pin",
+				Message: DefaultMask,
+				Got:     DefaultMask,
+				Want:    "6 digits",
+			},
+		},
+		{
+			"ValidationErrors",
+			ValidationErrors{Param("tags", []string{"a", "b"}, "at most 1 tag")},
+			ServiceError{
+				Kind:    Validation.String(),
+				Message: "tags is invalid: got [a b], want at most 1 tag",
+				Errors: []ServiceError{{
+					Kind:    Validation.String(),
+					Param:   "tags",
+					Message: "tags is invalid: got [a b], want at most 1 tag",
+					Got:     []interface{}{"a", "b"},
+					Want:    "at most 1 tag",
+				}},
+				Fields: []FieldViolation{{
+					Param:   "tags",
+					Message: "tags is invalid: got [a b], want at most 1 tag",
+					Got:     []interface{}{"a", "b"},
+					Want:    "at most 1 tag",
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			HTTPError(rr, tt.err)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d; want %d", rr.Code, http.StatusBadRequest)
+			}
+			var er ErrResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(er.Error, tt.want) {
+				t.Errorf("body = %+v; want %+v", er.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	var ve ValidationErrors
+	ve.Add(RequiredField("id"))
+	ve.Add(InvalidField("email", "no @ sign"))
+	ve.Add(OutOfRange("age", 0, 150))
+	ve.Add(TooLong("name", 64))
+	ve.Add(BadEnum("color", "red", "green"))
+
+	rr := httptest.NewRecorder()
+	HTTPError(rr, ve.Err())
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusBadRequest)
+	}
+	var er ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	kind := Validation.String()
+	want := []ServiceError{
+		{Kind: kind, Code: "Required", Param: "id", Message: "id is required"},
+		{Kind: kind, Code: "InvalidField", Param: "email", Message: "email is invalid: no @ sign"},
+		{Kind: kind, Code: "OutOfRange", Param: "age", Message: "age must be between 0 and 150"},
+		{Kind: kind, Code: "TooLong", Param: "name", Message: "name must have a length of at most 64"},
+		{Kind: kind, Code: "BadEnum", Param: "color", Message: "color must be one of: red, green"},
+	}
+	if !reflect.DeepEqual(er.Error.Errors, want) {
+		t.Errorf("Errors = %+v; want %+v", er.Error.Errors, want)
+	}
+
+	// A single FieldError is sent as a 400 too
+	rr = httptest.NewRecorder()
+	HTTPError(rr, TooLong("name", 64))
+	er = ErrResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if rr.Code != http.StatusBadRequest || !reflect.DeepEqual(er.Error, want[3]) {
+		t.Errorf("HTTPError(TooLong()) = %d %+v; want 400 %+v", rr.Code, er.Error, want[3])
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(nil, nil); err != nil {
+		t.Errorf("Check(nil, nil) = %v; want nil", err)
+	}
+	if err := Check(); err != nil {
+		t.Errorf("Check() = %v; want nil", err)
+	}
+	err := Check(nil, RequiredField("name"), nil, TooLong("bio", 10))
+	var ve ValidationErrors
+	if !stderrors.As(err, &ve) || len(ve) != 2 {
+		t.Fatalf("Check() = %#v; want a ValidationErrors of 2 errors", err)
+	}
+	if got := KindOf(err); got != Validation {
+		t.Errorf("KindOf(Check()) = %v; want %v", got, Validation)
+	}
+	if got := StatusOf(err); got != http.StatusBadRequest {
+		t.Errorf("StatusOf(Check()) = %d; want %d", got, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Versions of the format of the error responses sent by HTTPError.
+const (
+	// ResponseV1 is the format of ErrResponse.
+	ResponseV1 = 1
+	// ResponseV2 is the format of ErrResponseV2.
+	ResponseV2 = 2
+)
+
+// ResponseVersion is the version of the format of the error responses
+// sent by HTTPError, unless another one is set for the request with
+// WithResponseVersion, or requested by the client with a version
+// parameter in its Accept header, e.g.
+//
+//	Accept: application/json; version=2
+//
+// which is read by RequestContext. It is ResponseV1 by default, so the
+// format can evolve without breaking existing clients.
+var ResponseVersion = ResponseV1
+
+// ErrResponseV2 is used as the Response Body in the ResponseV2 format.
+// Unlike ErrResponse, it is not nested in an "error" member, always
+// has a status and a message, even for errors which only carry an HTTP
+// status code, and always has a list of fields, which is empty if the
+// error does not relate to a parameter.
+type ErrResponseV2 struct {
+	Status  int    `json:"status" xml:"status"`
+	Kind    string `json:"kind,omitempty" xml:"kind,omitempty"`
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Message string `json:"message" xml:"message"`
+	// Fields lists the parameters the error relates to
+	Fields []FieldViolation `json:"fields" xml:"fields>field"`
+	// RequestID is the ID of the request which failed, if known
+	RequestID string `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	// ErrorID is the ID of the error response (see ErrorIDFunc)
+	ErrorID string `json:"error_id,omitempty" xml:"error_id,omitempty"`
+	// TraceID is the ID of the trace of the request, if known
+	TraceID string `json:"trace_id,omitempty" xml:"trace_id,omitempty"`
+	// DocURL is the URL of the documentation of the error Code,
+	// if known (see RegisterDocURL and ProblemTypeURI)
+	DocURL string `json:"doc_url,omitempty" xml:"doc_url,omitempty"`
+	// Chain is only sent in debug responses (see DebugResponses)
+	Chain []ChainLink `json:"chain,omitempty" xml:"chain>link"`
+}
+
+// WithResponseVersion returns a copy of ctx which sets the version of
+// the format of the error responses sent with HTTPErrorCtx.
+func WithResponseVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, versionKey, version)
+}
+
+// responseVersion returns the version of the format of the error
+// responses for the request of ctx.
+func responseVersion(ctx context.Context) int {
+	if v, ok := ctx.Value(versionKey).(int); ok && validVersion(v) {
+		return v
+	}
+	return ResponseVersion
+}
+
+func validVersion(v int) bool {
+	return v == ResponseV1 || v == ResponseV2
+}
+
+// acceptVersion returns the version parameter of the JSON media ranges
+// of an Accept header, e.g. 2 for "application/json; version=2", or 0
+// if there is none.
+func acceptVersion(accept string) int {
+	for _, mr := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(mr)
+		if err != nil {
+			continue
+		}
+		switch mt {
+		case "application/json", "application/*", "*/*":
+		default:
+			continue
+		}
+		if v, err := strconv.Atoi(params["version"]); err == nil && validVersion(v) {
+			return v
+		}
+	}
+	return 0
+}
+
+// sendResponseV2 sends the error response of err in the ResponseV2
+// format, given the status code and fields HTTPError determined for it.
+func sendResponseV2(ctx context.Context, w http.ResponseWriter, status int, se *ServiceError) {
+	r := ErrResponseV2{
+		Status:    status,
+		Message:   ReasonPhrase(status),
+		Fields:    []FieldViolation{},
+		RequestID: RequestIDFunc(ctx),
+		ErrorID:   errorIDFromContext(ctx),
+		TraceID:   tracer.TraceID(ctx),
+	}
+	if se != nil {
+		r.Kind = se.Kind
+		r.Code = se.Code
+		if se.Message != "" {
+			r.Message = se.Message
+		}
+		r.Fields = append(r.Fields, se.Fields...)
+		if se.Param != "" && len(se.Fields) == 0 {
+			r.Fields = append(r.Fields, FieldViolation{Param: se.Param, Code: se.Code, Message: se.Message, Got: se.Got, Want: se.Want})
+		}
+		r.DocURL = se.DocURL
+		if r.DocURL == "" && ProblemTypeURI != "" && se.Code != "" {
+			r.DocURL = ProblemTypeURI + se.Code
+		}
+		r.Chain = se.Chain
+	}
+
+	if enc := responseEncoder(ctx); enc != nil {
+		sendEncoded(ctx, w, enc, status, r)
+		return
+	}
+	errJSON, err := marshalResponse(r)
+	if err != nil {
+		body := `{"status":` + strconv.Itoa(status) + `,"message":"` + ReasonPhrase(status) + `","fields":[]}`
+		sendMarshalFailure(w, status, err, "application/json; version=2", body)
+		return
+	}
+	writeBody(ctx, w, status, "application/json; version=2", append(errJSON, '\n'))
+}
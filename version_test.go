@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestResponseVersion(t *testing.T) {
+	defer func() {
+		ResponseVersion = ResponseV1
+		ProblemTypeURI = ""
+	}()
+	ProblemTypeURI = "https://example.com/errors/"
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return RE(http.StatusBadRequest, Validation, Parameter("name"), Code("name_required"), Str("name is required"))
+	})
+	want := ErrResponseV2{
+		Status:  http.StatusBadRequest,
+		Kind:    Validation.String(),
+		Code:    "name_required",
+		Message: "name is required",
+		Fields:  []FieldViolation{{Param: "name", Code: "name_required", Message: "name is required"}},
+		DocURL:  "https://example.com/errors/name_required",
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		accept  string
+		wantV2  bool
+	}{
+		{"Default", ResponseV1, "", false},
+		{"Config", ResponseV2, "", true},
+		{"Accept", ResponseV1, "text/html, application/json; version=2", true},
+		{"Accept v1", ResponseV2, "application/json;version=1", false},
+		{"Accept unknown version", ResponseV1, "application/json; version=9", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResponseVersion = tt.version
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			if !tt.wantV2 {
+				var er ErrResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &er); err != nil || er.Error.Code != "name_required" {
+					t.Errorf("body = %s; want a v1 response", rr.Body)
+				}
+				return
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/json; version=2" {
+				t.Errorf("Content-Type = %q; want version 2", ct)
+			}
+			var got ErrResponseV2
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("body = %+v; want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestResponseV2StatusOnly(t *testing.T) {
+	rr := httptest.NewRecorder()
+	HTTPErrorCtx(WithResponseVersion(WithRequestID(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "req-1"), ResponseV2), rr, RE(http.StatusUnauthorized))
+
+	var got ErrResponseV2
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := ErrResponseV2{Status: http.StatusUnauthorized, Message: "Unauthorized", Fields: []FieldViolation{}, RequestID: "req-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %+v; want %+v", got, want)
+	}
+}
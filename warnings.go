@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Warning is a non-fatal problem of a successful response, such as a
+// degraded result because a dependency was unavailable. Warnings are
+// collected with AddWarning and sent in a Warning header (see
+// SetWarningHeaders) or in a warnings member of the response body,
+// e.g.
+//
+//	type UsersResponse struct {
+//		Users    []User           `json:"users"`
+//		Warnings []errors.Warning `json:"warnings,omitempty"`
+//	}
+type Warning struct {
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Message string `json:"message" xml:"message"`
+}
+
+// warnings collects the Warnings of a request.
+type warnings struct {
+	mu   sync.Mutex
+	list []Warning
+}
+
+// WithWarnings returns a copy of ctx which collects the Warnings added
+// with AddWarning. Handler installs it in the context of each request.
+func WithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey, &warnings{})
+}
+
+// AddWarning adds a Warning with the given Code and message to the
+// Warnings of ctx. It is safe to call concurrently. If ctx does not
+// collect Warnings (see WithWarnings), AddWarning does nothing.
+func AddWarning(ctx context.Context, code Code, message string) {
+	ws, ok := ctx.Value(warningsKey).(*warnings)
+	if !ok {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.list = append(ws.list, Warning{Code: string(code), Message: message})
+}
+
+// Warnings returns the Warnings added to ctx, in the order they were
+// added, or nil if there are none.
+func Warnings(ctx context.Context) []Warning {
+	ws, ok := ctx.Value(warningsKey).(*warnings)
+	if !ok {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if len(ws.list) == 0 {
+		return nil
+	}
+	return append([]Warning(nil), ws.list...)
+}
+
+// SetWarningHeaders adds a Warning header to the response for each
+// Warning of ctx, with the warn-code 299 (Miscellaneous Persistent
+// Warning) of RFC 7234, e.g.
+//
+//	Warning: 299 - "prices may be stale"
+//
+// It must be called before the status code of the response is written.
+func SetWarningHeaders(ctx context.Context, w http.ResponseWriter) {
+	for _, wn := range Warnings(ctx) {
+		w.Header().Add("Warning", `299 - `+strconv.Quote(wn.Message))
+	}
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	// Without a collector, warnings are dropped
+	AddWarning(context.Background(), "stale", "prices may be stale")
+	if got := Warnings(context.Background()); got != nil {
+		t.Errorf("Warnings() = %v; want nil", got)
+	}
+
+	ctx := WithWarnings(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			AddWarning(ctx, "partial", "some results are missing")
+		}()
+	}
+	wg.Wait()
+	if got := Warnings(ctx); len(got) != 10 {
+		t.Errorf("len(Warnings()) = %d; want 10", len(got))
+	}
+}
+
+func TestHandlerWarnings(t *testing.T) {
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		AddWarning(r.Context(), "stale", "prices may be stale")
+		AddWarning(r.Context(), "partial", `missing "eu" region`)
+		SetWarningHeaders(r.Context(), w)
+		w.WriteHeader(http.StatusOK)
+		return nil
+	})
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/prices", nil))
+	want := []string{`299 - "prices may be stale"`, `299 - "missing \"eu\" region"`}
+	if got := rr.Header().Values("Warning"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Warning headers = %q; want %q", got, want)
+	}
+}
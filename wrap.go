@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Wrap returns an *Error for op which wraps err, as E(op, args..., err)
+// does, for the common case of annotating an error on its way up the
+// call stack. String arguments are not treated as by E: they are joined
+// with spaces into a message which annotates err, e.g.
+//
+//	errors.Wrap(err, "service.Create", errors.Code("create_failed"), "creating user")
+//
+// As with E, the Kind of err is used if no Kind is given. The Code of
+// err is used too if no Code is given, so the outermost error carries
+// the classification of err. If err is nil, Wrap returns nil.
+func Wrap(err error, op Op, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var msgs, eargs []interface{}
+	for _, arg := range args {
+		if s, ok := arg.(string); ok {
+			msgs = append(msgs, s)
+			continue
+		}
+		eargs = append(eargs, arg)
+	}
+	return wrap(err, op, strings.TrimSuffix(fmt.Sprintln(msgs...), "\n"), eargs)
+}
+
+// Wrapf is like Wrap, but the message which annotates err is formatted
+// as with fmt.Sprintf. It takes no other argument than op, so the
+// Kind and Code of err are always used.
+func Wrapf(err error, op Op, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(err, op, fmt.Sprintf(format, args...), nil)
+}
+
+// wrap implements Wrap and Wrapf. It must be called directly by them,
+// so the stack trace starts at their caller.
+func wrap(err error, op Op, msg string, args []interface{}) error {
+	wrapped := E(append(append([]interface{}{op}, args...), err)...)
+	e, ok := wrapped.(*Error)
+	if !ok {
+		// Bad argument, reported by E
+		return wrapped
+	}
+	if e.Code == "" {
+		e.Code = chainCode(err)
+	}
+	if msg != "" {
+		e.Err = &annotation{msg: msg, err: e.Err}
+	}
+	e.trace = captureStack(2)
+	e.pc = captureLocation(1)
+	return e
+}
+
+// annotation is an error which annotates err with a message. It is
+// the underlying error of the errors returned by Wrap and Wrapf when
+// they are given a message.
+type annotation struct {
+	msg string
+	err error
+}
+
+func (a *annotation) Error() string {
+	return a.msg + ": " + a.err.Error()
+}
+
+func (a *annotation) Unwrap() error {
+	return a.err
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	inner := E(Op("db.Get"), NotExist, Code("no_user"), io.EOF)
+	tests := []struct {
+		name     string
+		err      error
+		wantKind Kind
+		wantCode Code
+		wantMsg  string
+	}{
+		{"No message", Wrap(inner, "service.Get"), NotExist, "no_user",
+			"service.Get: item_does_not_exist:\n\tdb.Get|: EOF"},
+		{"Message", Wrap(inner, "service.Get", "loading", "user"), NotExist, "no_user",
+			"service.Get: item_does_not_exist|: loading user: db.Get|: EOF"},
+		{"Own Kind and Code", Wrap(inner, "service.Get", Permission, Code("denied")), Permission, "denied",
+			"service.Get: permission_denied:\n\tdb.Get: item_does_not_exist|: EOF"},
+		{"Wrapf", Wrapf(inner, "service.Get", "loading user %d", 42), NotExist, "no_user",
+			"service.Get: item_does_not_exist|: loading user 42: db.Get|: EOF"},
+		{"Other error", Wrapf(io.EOF, "service.Get", "loading user"), Other, "",
+			"service.Get|: loading user: EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := tt.err.(*Error)
+			if !ok {
+				t.Fatalf("got %T; want *Error", tt.err)
+			}
+			if e.Kind != tt.wantKind || e.Code != tt.wantCode {
+				t.Errorf("Kind, Code = %v, %q; want %v, %q", e.Kind, e.Code, tt.wantKind, tt.wantCode)
+			}
+			if got := e.Error(); !strings.HasSuffix(got, tt.wantMsg) {
+				t.Errorf("Error() = %q; want %q", got, tt.wantMsg)
+			}
+			if !stderrors.Is(e, io.EOF) {
+				t.Error("wrapped error is not in the chain")
+			}
+			if ops := Ops(e); len(ops) == 0 || ops[0] != "service.Get" {
+				t.Errorf("Ops() = %v; want service.Get first", ops)
+			}
+		})
+	}
+	if Wrap(nil, "service.Get", "loading user") != nil || Wrapf(nil, "service.Get", "loading user") != nil {
+		t.Error("Wrap(nil) != nil")
+	}
+}
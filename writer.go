@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// TrackWrites wraps w so HTTPError can tell whether the response was
+// already started (see HTTPErrorCtx). Handler and Recoverer wrap their
+// ResponseWriter with TrackWrites. If w already reports whether it was
+// written to, e.g. the ResponseWriter of Gin, it is returned as is.
+func TrackWrites(w http.ResponseWriter) http.ResponseWriter {
+	if _, ok := w.(writeTracker); ok {
+		return w
+	}
+	return &trackingWriter{ResponseWriter: w}
+}
+
+// writeTracker is implemented by ResponseWriters which report whether
+// the response was started.
+type writeTracker interface {
+	Written() bool
+}
+
+// trackingWriter is the ResponseWriter returned by TrackWrites.
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (tw *trackingWriter) WriteHeader(statusCode int) {
+	tw.written = true
+	tw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (tw *trackingWriter) Write(b []byte) (int, error) {
+	tw.written = true
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client, if the underlying
+// ResponseWriter supports it.
+func (tw *trackingWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		tw.written = true
+		f.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection, if the underlying
+// ResponseWriter supports it, e.g. to upgrade it to a WebSocket.
+// Otherwise, it returns http.ErrNotSupported.
+func (tw *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := tw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	// The response can no longer be sent once the connection is
+	// taken over
+	tw.written = true
+	return hj.Hijack()
+}
+
+// Push initiates an HTTP/2 server push, if the underlying
+// ResponseWriter supports it. Otherwise, it returns
+// http.ErrNotSupported.
+func (tw *trackingWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := tw.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// ReadFrom writes the data read from r to the response, using the
+// ReadFrom method of the underlying ResponseWriter, if any, so files
+// can be sent with sendfile.
+func (tw *trackingWriter) ReadFrom(r io.Reader) (int64, error) {
+	tw.written = true
+	if rf, ok := tw.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(r)
+	}
+	// Hide the ReadFrom method of tw from io.Copy
+	return io.Copy(struct{ io.Writer }{tw.ResponseWriter}, r)
+}
+
+// Written reports whether the status code or part of the body
+// was written.
+func (tw *trackingWriter) Written() bool {
+	return tw.written
+}
+
+// Unwrap returns the underlying ResponseWriter, for use by
+// http.ResponseController.
+func (tw *trackingWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// responseStarted reports whether the response of w was started.
+// It is only known if w implements writeTracker (see TrackWrites).
+func responseStarted(w http.ResponseWriter) bool {
+	wt, ok := w.(writeTracker)
+	return ok && wt.Written()
+}
+
+// setErrorTrailers reports err in the trailers of the response, for
+// clients which read them, when the status code was already sent.
+func setErrorTrailers(w http.ResponseWriter, status int, kind Kind, code Code) {
+	h := w.Header()
+	h.Set(http.TrailerPrefix+"X-Error-Status", strconv.Itoa(status))
+	if kind != Other {
+		h.Set(http.TrailerPrefix+"X-Error-Kind", kind.String())
+	}
+	if code != "" {
+		h.Set(http.TrailerPrefix+"X-Error-Code", string(code))
+	}
+}
@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"bufio"
+	"bytes"
+	stderrors "errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPErrorResponseStarted(t *testing.T) {
+	defer SetLogger(nil)
+	tl := &testLogger{}
+	SetLogger(tl)
+
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("partial"))
+		return RE(http.StatusNotFound, NotExist, Code("gone"), Str("stream ended"))
+	})
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusOK)
+	}
+	if got := rr.Body.String(); got != "partial" {
+		t.Errorf("body = %q; want %q", got, "partial")
+	}
+	tests := []struct {
+		trailer string
+		want    string
+	}{
+		{"X-Error-Status", "404"},
+		{"X-Error-Kind", NotExist.String()},
+		{"X-Error-Code", "gone"},
+	}
+	for _, tt := range tests {
+		if got := rr.Header().Get(http.TrailerPrefix + tt.trailer); got != tt.want {
+			t.Errorf("trailer %s = %q; want %q", tt.trailer, got, tt.want)
+		}
+	}
+	if len(tl.entries) != 2 {
+		t.Errorf("got %d log entries; want 2", len(tl.entries))
+	}
+}
+
+func TestHTTPErrorResponseStartedReported(t *testing.T) {
+	defer SetMetrics(nil)
+	tm := &testMetrics{}
+	SetMetrics(tm)
+	defer SetReporter(nil)
+	tr := &testReporter{}
+	SetReporter(tr)
+
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("partial"))
+		return RE(http.StatusServiceUnavailable, IO, Code("upstream_down"), Str("connection reset"))
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := "I/O_error/upstream_down/Service Unavailable"
+	if len(tm.sent) != 1 || tm.sent[0] != want {
+		t.Errorf("ErrorSent calls = %q; want [%q]", tm.sent, want)
+	}
+	if len(tr.infos) != 1 || tr.infos[0].Status != http.StatusServiceUnavailable {
+		t.Errorf("reported %+v; want one error with status 503", tr.infos)
+	}
+}
+
+func TestTrackWrites(t *testing.T) {
+	w := TrackWrites(httptest.NewRecorder())
+	if responseStarted(w) {
+		t.Error("responseStarted() = true before writing; want false")
+	}
+	if TrackWrites(w) != w {
+		t.Error("TrackWrites() wrapped a tracked ResponseWriter again")
+	}
+	w.(http.Flusher).Flush()
+	if !responseStarted(w) {
+		t.Error("responseStarted() = false after Flush; want true")
+	}
+	if responseStarted(httptest.NewRecorder()) {
+		t.Error("responseStarted() = true for an untracked ResponseWriter; want false")
+	}
+}
+
+// hijackRecorder is a ResponseRecorder which can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (hr *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hr.hijacked = true
+	c, _ := net.Pipe()
+	return c, bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c)), nil
+}
+
+func TestTrackWritesInterfaces(t *testing.T) {
+	hr := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		c, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return err
+		}
+		return c.Close()
+	})
+	h.ServeHTTP(hr, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if !hr.hijacked {
+		t.Error("Hijack() through Handler did not hijack the connection")
+	}
+
+	w := TrackWrites(httptest.NewRecorder())
+	if _, _, err := w.(http.Hijacker).Hijack(); !stderrors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Hijack() error = %v; want %v", err, http.ErrNotSupported)
+	}
+	if err := w.(http.Pusher).Push("/app.js", nil); !stderrors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Push() error = %v; want %v", err, http.ErrNotSupported)
+	}
+
+	rr := httptest.NewRecorder()
+	w = TrackWrites(rr)
+	if _, err := io.Copy(w, strings.NewReader("file contents")); err != nil {
+		t.Fatalf("io.Copy() error = %v", err)
+	}
+	if !responseStarted(w) || !bytes.Equal(rr.Body.Bytes(), []byte("file contents")) {
+		t.Errorf("io.Copy() wrote %q, started %v; want the file contents", rr.Body.String(), responseStarted(w))
+	}
+}